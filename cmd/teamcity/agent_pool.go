@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+var agentPoolCommands = []*command{
+	{
+		name:    "list",
+		summary: "List the agent pools",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("project", "", "only list the pools available to the project with given `id`")
+		},
+		run: agentPoolList,
+	},
+	{
+		name:    "get",
+		args:    "<id>",
+		summary: "Show an agent pool and its projects",
+		run:     agentPoolGet,
+	},
+	{
+		name:    "create",
+		args:    "<name>",
+		summary: "Create an agent pool",
+		flags: func(fs *flag.FlagSet) {
+			fs.Int("max-agents", -1, "maximum `number` of agents, unlimited if negative")
+		},
+		run: agentPoolCreate,
+	},
+	{
+		name:    "delete",
+		args:    "<id>",
+		summary: "Delete an agent pool",
+		run:     agentPoolDelete,
+	},
+	{
+		name:    "assign",
+		args:    "<id> <project id>",
+		summary: "Assign a project to an agent pool",
+		run:     agentPoolAssign,
+	},
+	{
+		name:    "unassign",
+		args:    "<id> <project id>",
+		summary: "Unassign a project from an agent pool",
+		run:     agentPoolUnassign,
+	},
+}
+
+// poolID parses the id of an agent pool
+func poolID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid agent pool id '%s'", arg)
+	}
+	return id, nil
+}
+
+func printAgentPool(a *app, pool *teamcity.AgentPool) error {
+	maxAgents := "unlimited"
+	if pool.MaxAgents != nil {
+		maxAgents = strconv.Itoa(*pool.MaxAgents)
+	}
+	var projects []string
+	if pool.Projects != nil {
+		for _, p := range pool.Projects.Project {
+			projects = append(projects, p.ID)
+		}
+	}
+	t := newTable("ID", "NAME", "MAX AGENTS", "PROJECTS")
+	t.add(strconv.Itoa(pool.Id), pool.Name, maxAgents, strings.Join(projects, ","))
+	return a.print(pool, t)
+}
+
+func agentPoolList(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 0); err != nil {
+		return err
+	}
+	var pools *teamcity.ListAgentPools
+	var err error
+	if project := flagValue(fs, "project"); project != "" {
+		pools, err = a.client.AgentPools.ListForProject(project)
+	} else {
+		pools, err = a.client.AgentPools.List()
+	}
+	if err != nil {
+		return err
+	}
+	t := newTable("ID", "NAME")
+	for _, p := range pools.AgentPools {
+		t.add(strconv.Itoa(p.Id), p.Name)
+	}
+	return a.print(pools.AgentPools, t)
+}
+
+func agentPoolGet(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	id, err := poolID(args[0])
+	if err != nil {
+		return err
+	}
+	pool, err := a.client.AgentPools.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return printAgentPool(a, pool)
+}
+
+func agentPoolCreate(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	pool := teamcity.CreateAgentPool{Name: args[0]}
+	if maxAgents, _ := strconv.Atoi(flagValue(fs, "max-agents")); maxAgents >= 0 {
+		pool.MaxAgents = &maxAgents
+	}
+	created, err := a.client.AgentPools.Create(pool)
+	if err != nil {
+		return err
+	}
+	return printAgentPool(a, created)
+}
+
+func agentPoolDelete(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	id, err := poolID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.client.AgentPools.Delete(id); err != nil {
+		return err
+	}
+	return a.printDone("deleted", "agent pool", args[0])
+}
+
+func agentPoolAssign(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	id, err := poolID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.client.AgentPools.AssignProject(id, args[1]); err != nil {
+		return err
+	}
+	return a.printDone("assigned", "project", args[1])
+}
+
+func agentPoolUnassign(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	id, err := poolID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.client.AgentPools.UnassignProject(id, args[1]); err != nil {
+		return err
+	}
+	return a.printDone("unassigned", "project", args[1])
+}
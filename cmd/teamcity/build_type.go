@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+var buildTypeCommands = []*command{
+	{
+		name:    "get",
+		args:    "<id>",
+		summary: "Show a build configuration or template",
+		run:     buildTypeGet,
+	},
+	{
+		name:    "create",
+		args:    "<project id> <name>",
+		summary: "Create a build configuration",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("description", "", "`description` of the build configuration")
+		},
+		run: buildTypeCreate,
+	},
+	{
+		name:    "delete",
+		args:    "<id>",
+		summary: "Delete a build configuration or template",
+		run:     buildTypeDelete,
+	},
+	{
+		name:    "move",
+		args:    "<id> <project id>",
+		summary: "Move a build configuration or template to another project",
+		run:     buildTypeMove,
+	},
+}
+
+func printBuildType(a *app, bt *teamcity.BuildType) error {
+	t := newTable("ID", "NAME", "PROJECT", "TEMPLATE", "PAUSED")
+	t.add(bt.ID, bt.Name, bt.ProjectID, strconv.FormatBool(bt.IsTemplate), strconv.FormatBool(bt.Disabled))
+	return a.print(bt, t)
+}
+
+func buildTypeGet(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	bt, err := a.client.BuildTypes.GetByID(args[0])
+	if err != nil {
+		return err
+	}
+	return printBuildType(a, bt)
+}
+
+func buildTypeCreate(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	bt, err := teamcity.NewBuildType(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	bt.Description = flagValue(fs, "description")
+	ref, err := a.client.BuildTypes.Create(bt)
+	if err != nil {
+		return err
+	}
+	created, err := a.client.BuildTypes.GetByID(ref.ID)
+	if err != nil {
+		return err
+	}
+	return printBuildType(a, created)
+}
+
+func buildTypeDelete(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	if err := a.client.BuildTypes.Delete(args[0]); err != nil {
+		return err
+	}
+	return a.printDone("deleted", "build type", args[0])
+}
+
+func buildTypeMove(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	bt, err := a.client.BuildTypes.Move(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return printBuildType(a, bt)
+}
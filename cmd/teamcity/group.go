@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+var groupCommands = []*command{
+	{
+		name:    "get",
+		args:    "<key>",
+		summary: "Show a user group",
+		run:     groupGet,
+	},
+	{
+		name:    "create",
+		args:    "<key> <name>",
+		summary: "Create a user group",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("description", "", "`description` of the group")
+		},
+		run: groupCreate,
+	},
+	{
+		name:    "delete",
+		args:    "<key>",
+		summary: "Delete a user group",
+		run:     groupDelete,
+	},
+}
+
+func printGroup(a *app, g *teamcity.Group) error {
+	t := newTable("KEY", "NAME", "DESCRIPTION")
+	t.add(g.Key, g.Name, g.Description)
+	return a.print(g, t)
+}
+
+func groupGet(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	g, err := a.client.Groups.GetByKey(args[0])
+	if err != nil {
+		return err
+	}
+	return printGroup(a, g)
+}
+
+func groupCreate(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	g, err := teamcity.NewGroup(args[0], args[1], flagValue(fs, "description"))
+	if err != nil {
+		return err
+	}
+	created, err := a.client.Groups.Create(g)
+	if err != nil {
+		return err
+	}
+	return printGroup(a, created)
+}
+
+func groupDelete(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	if err := a.client.Groups.Delete(args[0]); err != nil {
+		return err
+	}
+	return a.printDone("deleted", "group", args[0])
+}
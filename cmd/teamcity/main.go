@@ -0,0 +1,198 @@
+// Command teamcity manages TeamCity projects, build configurations, VCS roots, agent pools and groups from the command line.
+//
+// The server address is read from TEAMCITY_ADDR and the access token from TEAMCITY_TOKEN, unless set with the -addr and -token flags.
+// TEAMCITY_USERNAME and TEAMCITY_PASSWORD can be used for basic authentication instead of a token.
+//
+// Usage:
+//
+//	teamcity [flags] <resource> <command> [arguments]
+//
+// Run "teamcity help" for the list of resources and commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+// errUsage is returned by commands called with invalid arguments, after printing their usage
+var errUsage = errors.New("invalid usage")
+
+// command is an operation on a resource, such as "project get"
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(a *app, fs *flag.FlagSet, args []string) error
+	// flags defines the flags of the command, if any
+	flags func(fs *flag.FlagSet)
+}
+
+// resources lists the commands of each resource
+var resources = map[string][]*command{
+	"project":   projectCommands,
+	"buildtype": buildTypeCommands,
+	"vcsroot":   vcsRootCommands,
+	"agentpool": agentPoolCommands,
+	"group":     groupCommands,
+}
+
+// app holds the state shared by the commands
+type app struct {
+	client *teamcity.Client
+	output string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run executes the command line given by args and returns the exit code
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("teamcity", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", getenv("TEAMCITY_ADDR"), "TeamCity server `address`")
+	token := fs.String("token", "", "access `token`, defaults to $TEAMCITY_TOKEN")
+	username := fs.String("username", getenv("TEAMCITY_USERNAME"), "`name` of the user, for basic authentication")
+	password := fs.String("password", "", "`password` of the user, for basic authentication, defaults to $TEAMCITY_PASSWORD")
+	output := fs.String("output", "table", "output `format`: table or json")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Secrets are not flag defaults, so that usage never prints them
+	if *token == "" {
+		*token = getenv("TEAMCITY_TOKEN")
+	}
+	if *password == "" {
+		*password = getenv("TEAMCITY_PASSWORD")
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "unsupported output format '%s'\n", *output)
+		return 2
+	}
+
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		usage(fs)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	commands, ok := resources[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown resource '%s'\n", args[0])
+		usage(fs)
+		return 2
+	}
+	if len(args) < 2 {
+		resourceUsage(stderr, args[0], commands)
+		return 2
+	}
+	cmd := findCommand(commands, args[1])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command '%s %s'\n", args[0], args[1])
+		resourceUsage(stderr, args[0], commands)
+		return 2
+	}
+
+	var auth teamcity.Auth
+	switch {
+	case *token != "":
+		auth = teamcity.TokenAuth(*token)
+	case *username != "":
+		auth = teamcity.BasicAuth(*username, *password)
+	default:
+		fmt.Fprintln(stderr, "no credentials: set TEAMCITY_TOKEN or TEAMCITY_USERNAME and TEAMCITY_PASSWORD")
+		return 2
+	}
+	client, err := teamcity.NewClientWithAddress(auth, strings.TrimSuffix(*addr, "/"), http.DefaultClient)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: set TEAMCITY_ADDR or use -addr\n", err)
+		return 2
+	}
+
+	cmdFlags := flag.NewFlagSet(args[0]+" "+cmd.name, flag.ContinueOnError)
+	cmdFlags.SetOutput(stderr)
+	cmdFlags.Usage = func() { commandUsage(stderr, args[0], cmd, cmdFlags) }
+	if cmd.flags != nil {
+		cmd.flags(cmdFlags)
+	}
+	if err := cmdFlags.Parse(args[2:]); err != nil {
+		return 2
+	}
+
+	a := &app{client: client, output: *output, stdout: stdout, stderr: stderr}
+	if err := cmd.run(a, cmdFlags, cmdFlags.Args()); err != nil {
+		if err == errUsage {
+			cmdFlags.Usage()
+			return 2
+		}
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func findCommand(commands []*command, name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// exactArgs returns errUsage unless args has n elements
+func exactArgs(args []string, n int) error {
+	if len(args) != n {
+		return errUsage
+	}
+	return nil
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Usage: teamcity [flags] <resource> <command> [arguments]")
+	fmt.Fprintln(w, "\nFlags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(w, "\nResources:")
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var commands []string
+		for _, c := range resources[name] {
+			commands = append(commands, c.name)
+		}
+		fmt.Fprintf(w, "  %-10s %s\n", name, strings.Join(commands, ", "))
+	}
+}
+
+func resourceUsage(w io.Writer, resource string, commands []*command) {
+	fmt.Fprintf(w, "Usage: teamcity [flags] %s <command> [arguments]\n\nCommands:\n", resource)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-35s %s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+}
+
+func commandUsage(w io.Writer, resource string, cmd *command, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: teamcity [flags] %s %s %s\n\n%s\n", resource, cmd.name, cmd.args, cmd.summary)
+	if cmd.flags != nil {
+		fmt.Fprintln(w, "\nFlags:")
+		fs.PrintDefaults()
+	}
+}
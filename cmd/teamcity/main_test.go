@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /app/rest/agentPools/":
+			w.Write([]byte(`{"count":2,"agentPool":[{"id":0,"name":"Default"},{"id":1,"name":"Linux"}]}`))
+		case "GET /app/rest/userGroups/key:ADMINS":
+			w.Write([]byte(`{"key":"ADMINS","name":"Administrators"}`))
+		case "DELETE /app/rest/agentPools/id:1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runTest(t *testing.T, args ...string) (int, string, string) {
+	server := newTestServer(t)
+	env := map[string]string{"TEAMCITY_ADDR": server.URL, "TEAMCITY_TOKEN": "secret"}
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr, func(name string) string { return env[name] })
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_RunTableOutput(t *testing.T) {
+	code, stdout, _ := runTest(t, "agentpool", "list")
+
+	require.Equal(t, 0, code)
+	assert.Equal(t, "ID  NAME\n0   Default\n1   Linux\n", stdout)
+}
+
+func Test_RunJSONOutput(t *testing.T) {
+	code, stdout, _ := runTest(t, "-output", "json", "group", "get", "ADMINS")
+
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"key":"ADMINS","name":"Administrators"}`, stdout)
+
+	code, stdout, _ = runTest(t, "-output", "json", "agentpool", "delete", "1")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"action":"deleted","resource":"agent pool","id":"1"}`, stdout)
+}
+
+func Test_RunErrors(t *testing.T) {
+	code, _, stderr := runTest(t, "group", "get", "MISSING")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "error: ")
+
+	code, _, stderr = runTest(t, "group", "get")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage: teamcity [flags] group get <key>")
+
+	code, _, stderr = runTest(t, "agentpool", "get", "linux")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "invalid agent pool id 'linux'")
+
+	code, _, stderr = runTest(t, "agent", "list")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "unknown resource 'agent'")
+}
+
+func Test_RunUsageHidesSecrets(t *testing.T) {
+	code, _, stderr := runTest(t, "help")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stderr, "defaults to $TEAMCITY_TOKEN")
+	assert.NotContains(t, stderr, "secret")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is the tabular representation of a command result
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func newTable(header ...string) *table {
+	return &table{header: header}
+}
+
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// print writes v as JSON, or t as a table, depending on the output format
+func (a *app) print(v interface{}, t *table) error {
+	if a.output == "json" {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// printDone reports the success of a command without result, such as a deletion
+func (a *app) printDone(action string, resource string, id string) error {
+	if a.output == "json" {
+		return a.print(map[string]string{"action": action, "resource": resource, "id": id}, nil)
+	}
+	_, err := fmt.Fprintf(a.stdout, "%s %s '%s'\n", resource, action, id)
+	return err
+}
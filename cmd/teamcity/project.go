@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+var projectCommands = []*command{
+	{
+		name:    "get",
+		args:    "<id>",
+		summary: "Show a project",
+		run:     projectGet,
+	},
+	{
+		name:    "create",
+		args:    "<name>",
+		summary: "Create a project",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("description", "", "`description` of the project")
+			fs.String("parent", "", "`id` of the parent project, defaults to the root project")
+		},
+		run: projectCreate,
+	},
+	{
+		name:    "delete",
+		args:    "<id>",
+		summary: "Delete a project",
+		run:     projectDelete,
+	},
+	{
+		name:    "move",
+		args:    "<id> <parent id>",
+		summary: "Move a project to another parent project",
+		run:     projectMove,
+	},
+	{
+		name:    "export",
+		args:    "<id>",
+		summary: "Export a project tree as a document, which can be linted or imported",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("format", string(teamcity.ExportFormatYAML), "document `format`: yaml or json")
+		},
+		run: projectExport,
+	},
+	{
+		name:    "lint",
+		args:    "<id>",
+		summary: "Check a project tree against the built-in lint rules, exiting with an error if any rule of severity error fails",
+		flags: func(fs *flag.FlagSet) {
+			fs.String("format", "", "report `format`: sarif, or the output format by default")
+		},
+		run: projectLint,
+	},
+}
+
+func printProject(a *app, p *teamcity.Project) error {
+	t := newTable("ID", "NAME", "PARENT", "BUILD TYPES", "URL")
+	t.add(p.ID, p.Name, p.ParentProjectID, fmt.Sprint(p.BuildTypes.Count), p.WebURL)
+	return a.print(p, t)
+}
+
+func projectGet(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	p, err := a.client.Projects.GetByID(args[0])
+	if err != nil {
+		return err
+	}
+	return printProject(a, p)
+}
+
+func projectCreate(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	p, err := teamcity.NewProject(args[0], flagValue(fs, "description"), flagValue(fs, "parent"))
+	if err != nil {
+		return err
+	}
+	created, err := a.client.Projects.Create(p)
+	if err != nil {
+		return err
+	}
+	return printProject(a, created)
+}
+
+func projectDelete(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	if err := a.client.Projects.Delete(args[0]); err != nil {
+		return err
+	}
+	return a.printDone("deleted", "project", args[0])
+}
+
+func projectMove(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	p, err := a.client.Projects.Move(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return printProject(a, p)
+}
+
+func projectExport(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	doc, err := teamcity.NewExporter(a.client).Export(args[0])
+	if err != nil {
+		return err
+	}
+	for _, w := range doc.Warnings {
+		fmt.Fprintf(a.stderr, "warning: %s\n", w)
+	}
+	return doc.Encode(a.stdout, teamcity.ExportFormat(flagValue(fs, "format")))
+}
+
+func projectLint(a *app, fs *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	doc, err := teamcity.NewExporter(a.client).Export(args[0])
+	if err != nil {
+		return err
+	}
+	report := teamcity.NewLinter().Lint(doc)
+
+	switch format := flagValue(fs, "format"); {
+	case format == "sarif":
+		err = report.WriteSARIF(a.stdout, "")
+	case format != "":
+		return fmt.Errorf("unsupported report format '%s'", format)
+	case a.output == "json":
+		err = report.WriteJSON(a.stdout)
+	default:
+		t := newTable("SEVERITY", "RULE", "LOCATION", "MESSAGE")
+		for _, f := range report.Findings {
+			t.add(string(f.Severity), f.RuleID, f.Location.String(), f.Message)
+		}
+		err = a.print(report.Findings, t)
+	}
+	if err != nil {
+		return err
+	}
+	if report.HasSeverity(teamcity.LintSeverityError) {
+		return fmt.Errorf("project '%s' has lint errors", args[0])
+	}
+	return nil
+}
+
+// flagValue returns the value of the flag with given name as a string
+func flagValue(fs *flag.FlagSet, name string) string {
+	return fs.Lookup(name).Value.String()
+}
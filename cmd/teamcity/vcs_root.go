@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+)
+
+var vcsRootCommands = []*command{
+	{
+		name:    "get",
+		args:    "<id>",
+		summary: "Show a VCS root",
+		run:     vcsRootGet,
+	},
+	{
+		name:    "delete",
+		args:    "<id>",
+		summary: "Delete a VCS root",
+		run:     vcsRootDelete,
+	},
+}
+
+func vcsRootGet(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	root, err := a.client.VcsRoots.GetByID(args[0])
+	if err != nil {
+		return err
+	}
+	t := newTable("ID", "NAME", "TYPE", "PROJECT")
+	t.add(root.GetID(), root.Name(), root.VcsName(), root.ProjectID())
+	if git, ok := root.(*teamcity.GitVcsRoot); ok && git.Options != nil {
+		t.header = append(t.header, "URL")
+		t.rows[0] = append(t.rows[0], git.Options.FetchURL)
+	}
+	return a.print(root, t)
+}
+
+func vcsRootDelete(a *app, _ *flag.FlagSet, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+	if err := a.client.VcsRoots.Delete(args[0]); err != nil {
+		return err
+	}
+	return a.printDone("deleted", "VCS root", args[0])
+}
@@ -0,0 +1,132 @@
+package teamcity
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// TimeLayout is the layout used by TeamCity to represent timestamps, such as "20180708T112233+0300"
+const TimeLayout = "20060102T150405-0700"
+
+// Artifact represents a single file or directory published as an artifact of a build
+type Artifact struct {
+	// Name is the file or directory name, without its parent path
+	Name string `json:"name,omitempty" xml:"name"`
+
+	// FullName is the path of the artifact relative to the build artifacts root
+	FullName string `json:"fullName,omitempty" xml:"fullName"`
+
+	// Size in bytes. Directories do not report a size.
+	Size int64 `json:"size,omitempty" xml:"size"`
+
+	// ModificationTime is the raw TeamCity timestamp. See ModifiedAt for a parsed value.
+	ModificationTime string `json:"modificationTime,omitempty" xml:"modificationTime"`
+
+	Href string `json:"href,omitempty" xml:"href"`
+
+	// Children is only set for directories and archives that can be browsed
+	Children *artifactHref `json:"children,omitempty"`
+
+	// Content is only set for files that can be downloaded
+	Content *artifactHref `json:"content,omitempty"`
+}
+
+type artifactHref struct {
+	Href string `json:"href,omitempty" xml:"href"`
+}
+
+// Artifacts represents a collection of Artifact
+type Artifacts struct {
+	Count int32       `json:"count,omitempty" xml:"count"`
+	Href  string      `json:"href,omitempty" xml:"href"`
+	Items []*Artifact `json:"file"`
+}
+
+// IsDir returns true if this artifact has children that can be listed, such as directories and archives
+func (a *Artifact) IsDir() bool {
+	return a.Children != nil
+}
+
+// ModifiedAt parses ModificationTime into a time.Time
+func (a *Artifact) ModifiedAt() (time.Time, error) {
+	return time.Parse(TimeLayout, a.ModificationTime)
+}
+
+// ArtifactService provides operations for browsing and downloading artifacts of a build.
+// Downloads are streamed, so callers must close the returned io.ReadCloser.
+type ArtifactService struct {
+	BuildLocator Locator
+	httpClient   *http.Client
+	restHelper   *restHelper
+}
+
+func newArtifactService(build Locator, c *http.Client, base *sling.Sling) *ArtifactService {
+	sling := base.New().Path(fmt.Sprintf("builds/%s/artifacts/", build))
+	return &ArtifactService{
+		BuildLocator: build,
+		httpClient:   c,
+		restHelper:   newRestHelper(c, sling),
+	}
+}
+
+func (s *ArtifactService) fields() getFields {
+	return getFields{
+		Fields: "count,href,file(name,fullName,size,modificationTime,href,children(href),content(href))",
+	}
+}
+
+// List returns the artifacts under path. Use an empty path for the artifacts root.
+// When recursive is true, all nested files and directories are returned as a flat list; use FullName to tell them apart.
+func (s *ArtifactService) List(path string, recursive bool) ([]*Artifact, error) {
+	var out Artifacts
+	uri := "children/" + escapeArtifactPath(path)
+	if recursive {
+		uri += "?locator=recursive:true"
+	}
+	err := s.restHelper.getWithFields(uri, s.fields(), &out, "build artifacts")
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Items, nil
+}
+
+// Metadata returns information about a single artifact without downloading its content
+func (s *ArtifactService) Metadata(path string) (*Artifact, error) {
+	var out Artifact
+	fields := getFields{Fields: "name,fullName,size,modificationTime,href,children(href),content(href)"}
+	err := s.restHelper.getWithFields("metadata/"+escapeArtifactPath(path), fields, &out, "build artifact metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Download streams the content of a single artifact file. The caller must close the returned reader.
+func (s *ArtifactService) Download(path string) (io.ReadCloser, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	return s.restHelper.getStream("files/"+escapeArtifactPath(path), "*/*", "build artifact")
+}
+
+// DownloadArchive streams the directory at path as a zip archive. Use an empty path to download all artifacts.
+// The caller must close the returned reader.
+func (s *ArtifactService) DownloadArchive(path string) (io.ReadCloser, error) {
+	return s.restHelper.getStream("archived/"+escapeArtifactPath(path), "application/zip", "build artifacts archive")
+}
+
+func escapeArtifactPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
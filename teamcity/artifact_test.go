@@ -0,0 +1,92 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ArtifactsDeserialize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var sut Artifacts
+	err := json.Unmarshal([]byte(artifactsRecursiveJSON), &sut)
+	require.NoError(err)
+	require.Len(sut.Items, 2)
+
+	dir := sut.Items[0]
+	assert.Equal("dist", dir.FullName)
+	assert.True(dir.IsDir())
+
+	file := sut.Items[1]
+	assert.Equal("dist/app bundle.tar.gz", file.FullName)
+	assert.Equal(int64(3221225472), file.Size)
+	assert.False(file.IsDir())
+
+	modified, err := file.ModifiedAt()
+	require.NoError(err)
+	assert.Equal(time.Date(2020, 3, 1, 9, 30, 15, 0, time.UTC), modified.UTC())
+}
+
+func Test_ArtifactEscapePath(t *testing.T) {
+	assert.Equal(t, "", escapeArtifactPath(""))
+	assert.Equal(t, "dist/app%20bundle.tar.gz", escapeArtifactPath("/dist/app bundle.tar.gz"))
+}
+
+func Test_ArtifactDownloadStreamsBody(t *testing.T) {
+	require := require.New(t)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/httpAuth/app/rest/builds/id:42/artifacts/files/dist/app%20bundle.tar.gz":
+			w.Write([]byte("content"))
+		default:
+			w.WriteHeader(404)
+			w.Write([]byte("not found"))
+		}
+	}))
+	sut := client.ArtifactService(Locator("id:42"))
+
+	body, err := sut.Download("dist/app bundle.tar.gz")
+	require.NoError(err)
+	defer body.Close()
+	dt, err := io.ReadAll(body)
+	require.NoError(err)
+	require.Equal("content", string(dt))
+
+	_, err = sut.Download("missing.txt")
+	require.EqualError(err, "Error '404' when performing 'GET' operation - build artifact: not found")
+}
+
+const artifactsRecursiveJSON = `
+{
+	"count": 2,
+	"file": [
+		{
+			"name": "dist",
+			"fullName": "dist",
+			"modificationTime": "20200301T093015+0000",
+			"href": "/app/rest/builds/id:42/artifacts/metadata/dist",
+			"children": {
+				"href": "/app/rest/builds/id:42/artifacts/children/dist"
+			}
+		},
+		{
+			"name": "app bundle.tar.gz",
+			"fullName": "dist/app bundle.tar.gz",
+			"size": 3221225472,
+			"modificationTime": "20200301T113015+0200",
+			"href": "/app/rest/builds/id:42/artifacts/metadata/dist/app%20bundle.tar.gz",
+			"content": {
+				"href": "/app/rest/builds/id:42/artifacts/files/dist/app%20bundle.tar.gz"
+			}
+		}
+	]
+}
+`
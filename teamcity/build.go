@@ -0,0 +1,344 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// BuildStatus represents the result of a build
+type BuildStatus string
+
+const (
+	// BuildStatusSuccess is reported for successful builds
+	BuildStatusSuccess BuildStatus = "SUCCESS"
+	// BuildStatusFailure is reported for failed builds
+	BuildStatusFailure BuildStatus = "FAILURE"
+	// BuildStatusUnknown is reported for builds that were canceled or failed to start
+	BuildStatusUnknown BuildStatus = "UNKNOWN"
+)
+
+// BuildReference contains basic information about a build, usually enough to use as a type for relationships
+type BuildReference struct {
+	ID          int    `json:"id,omitempty" xml:"id"`
+	BuildTypeID string `json:"buildTypeId,omitempty" xml:"buildTypeId"`
+	Number      string `json:"number,omitempty" xml:"number"`
+	// Status is one of "SUCCESS", "FAILURE" or "UNKNOWN"
+	Status string `json:"status,omitempty" xml:"status"`
+	// State is one of "queued", "running" or "finished"
+	State      string `json:"state,omitempty" xml:"state"`
+	BranchName string `json:"branchName,omitempty" xml:"branchName"`
+	Href       string `json:"href,omitempty" xml:"href"`
+	WebURL     string `json:"webUrl,omitempty" xml:"webUrl"`
+}
+
+const buildReferenceFields = "id,buildTypeId,number,status,state,branchName,href,webUrl"
+
+// Build is the model for a queued, running or finished build
+type Build struct {
+	ID          int         `json:"id,omitempty" xml:"id"`
+	BuildTypeID string      `json:"buildTypeId,omitempty" xml:"buildTypeId"`
+	Number      string      `json:"number,omitempty" xml:"number"`
+	Status      BuildStatus `json:"status,omitempty" xml:"status"`
+	// State is one of "queued", "running" or "finished"
+	State      string `json:"state,omitempty" xml:"state"`
+	StatusText string `json:"statusText,omitempty" xml:"statusText"`
+	BranchName string `json:"branchName,omitempty" xml:"branchName"`
+	Pinned     bool   `json:"pinned,omitempty" xml:"pinned"`
+
+	// Dates are in TimeLayout format
+	QueuedDate string `json:"queuedDate,omitempty" xml:"queuedDate"`
+	StartDate  string `json:"startDate,omitempty" xml:"startDate"`
+	FinishDate string `json:"finishDate,omitempty" xml:"finishDate"`
+
+	Comment *Comment `json:"comment,omitempty"`
+	Tags    *Tags    `json:"tags,omitempty"`
+	// CanceledInfo is only set for builds that were canceled, holding who canceled it and why
+	CanceledInfo *Comment `json:"canceledInfo,omitempty"`
+
+	Href   string `json:"href,omitempty" xml:"href"`
+	WebURL string `json:"webUrl,omitempty" xml:"webUrl"`
+}
+
+// Locator returns the id: locator for this build
+func (b *Build) Locator() Locator {
+	return LocatorIDInt(b.ID)
+}
+
+// Canceled returns true if the build was canceled, either while queued or running
+func (b *Build) Canceled() bool {
+	return b.CanceledInfo != nil
+}
+
+// Reference converts a Build to a BuildReference
+func (b *Build) Reference() *BuildReference {
+	return &BuildReference{
+		ID:          b.ID,
+		BuildTypeID: b.BuildTypeID,
+		Number:      b.Number,
+		Status:      string(b.Status),
+		State:       b.State,
+		BranchName:  b.BranchName,
+		Href:        b.Href,
+		WebURL:      b.WebURL,
+	}
+}
+
+// Comment is a text comment left by a user on a build
+type Comment struct {
+	Text      string         `json:"text,omitempty" xml:"text"`
+	User      *UserReference `json:"user,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty" xml:"timestamp"`
+}
+
+// Tag is a label attached to a build
+type Tag struct {
+	Name string `json:"name,omitempty" xml:"name"`
+}
+
+// Tags represents a collection of Tag
+type Tags struct {
+	Count int32  `json:"count,omitempty" xml:"count"`
+	Items []*Tag `json:"tag"`
+}
+
+// NewTags returns a Tags collection with the given tag names
+func NewTags(names ...string) *Tags {
+	items := make([]*Tag, len(names))
+	for i, n := range names {
+		items[i] = &Tag{Name: n}
+	}
+	return &Tags{Count: int32(len(items)), Items: items}
+}
+
+// Names returns the names of the tags in this collection
+func (t *Tags) Names() []string {
+	if t == nil {
+		return []string{}
+	}
+	out := make([]string, len(t.Items))
+	for i, item := range t.Items {
+		out[i] = item.Name
+	}
+	return out
+}
+
+type buildsJSON struct {
+	Count int32    `json:"count,omitempty" xml:"count"`
+	Href  string   `json:"href,omitempty" xml:"href"`
+	Items []*Build `json:"build"`
+}
+
+type buildStatusUpdateJSON struct {
+	Status  BuildStatus `json:"status,omitempty" xml:"status"`
+	Comment string      `json:"comment,omitempty" xml:"comment"`
+}
+
+// BuildService has operations for handling builds. Builds are addressed by locators, such as LocatorIDInt or LocatorBuildNumber.
+type BuildService struct {
+	sling      *sling.Sling
+	httpClient *http.Client
+	restHelper *restHelper
+}
+
+func newBuildService(base *sling.Sling, httpClient *http.Client) *BuildService {
+	sling := base.Path("builds/")
+	return &BuildService{
+		sling:      sling,
+		httpClient: httpClient,
+		restHelper: newRestHelper(httpClient, sling),
+	}
+}
+
+// BuildLocator filters builds. Zero values are omitted from the locator.
+type BuildLocator struct {
+	BuildTypeID string
+	// ProjectID returns builds of the project and its subprojects
+	ProjectID string
+	// State is one of "queued", "running", "finished" or "any"
+	State  string
+	Status BuildStatus
+	Tag    string
+	Number string
+	Branch string
+	// SinceBuildID returns only builds started after the build with given id
+	SinceBuildID int
+	// FinishedAfter returns only builds that finished after the given time, to the second
+	FinishedAfter time.Time
+	// DefaultFilter can be set to false to include canceled, failed to start, personal and non-default branch builds
+	DefaultFilter *bool
+
+	// Count limits the number of builds returned. If zero, all builds are returned.
+	Count int
+	Start int
+}
+
+func (l BuildLocator) dimensions() locatorDimensions {
+	var d locatorDimensions
+	if l.BuildTypeID != "" {
+		d.add("buildType", fmt.Sprintf("(id:%s)", l.BuildTypeID))
+	}
+	if l.ProjectID != "" {
+		d.add("affectedProject", fmt.Sprintf("(id:%s)", l.ProjectID))
+	}
+	d.add("state", l.State)
+	d.add("status", string(l.Status))
+	d.add("tag", locatorValue(l.Tag))
+	d.add("number", locatorValue(l.Number))
+	d.add("branch", locatorValue(l.Branch))
+	if l.SinceBuildID != 0 {
+		d.add("sinceBuild", fmt.Sprintf("(id:%d)", l.SinceBuildID))
+	}
+	if !l.FinishedAfter.IsZero() {
+		d.add("finishDate", fmt.Sprintf("(date:%s,condition:after)", l.FinishedAfter.Format(TimeLayout)))
+	}
+	d.addBool("defaultFilter", l.DefaultFilter)
+	d.addInt("count", l.Count)
+	d.addInt("start", l.Start)
+	return d
+}
+
+// String returns the unescaped locator, for instance "buildType:(id:Bt1),state:running"
+func (l BuildLocator) String() string {
+	return l.dimensions().String()
+}
+
+func (s *BuildService) listFields() getFields {
+	return getFields{
+		Fields: "count,href,build(id,buildTypeId,number,status,state,statusText,branchName,pinned,queuedDate,startDate,finishDate,href,webUrl,canceledInfo(text,timestamp,user))",
+	}
+}
+
+// List returns the builds matching the locator, newest first, requesting further pages until all are returned unless locator.Count is set.
+func (s *BuildService) List(locator BuildLocator) ([]*Build, error) {
+	if locator.Count > 0 {
+		return s.page(locator)
+	}
+
+	var out []*Build
+	locator.Count = listPageSize
+	for {
+		page, err := s.page(locator)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < listPageSize {
+			return out, nil
+		}
+		locator.Start += listPageSize
+	}
+}
+
+func (s *BuildService) page(locator BuildLocator) ([]*Build, error) {
+	var out buildsJSON
+	err := s.restHelper.getWithFields(locator.dimensions().query(), s.listFields(), &out, "builds")
+	if err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// Get returns the build matching the locator
+func (s *BuildService) Get(locator Locator) (*Build, error) {
+	var out Build
+	err := s.restHelper.get(locator.String(), &out, "build")
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetByID returns a build by its id
+func (s *BuildService) GetByID(id int) (*Build, error) {
+	return s.Get(LocatorIDInt(id))
+}
+
+// GetTags returns the tag names of the build matching the locator
+func (s *BuildService) GetTags(locator Locator) ([]string, error) {
+	var out Tags
+	err := s.restHelper.get(fmt.Sprintf("%s/tags", locator), &out, "build tags")
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Names(), nil
+}
+
+// AddTags adds the given tags to the build, keeping the existing ones
+func (s *BuildService) AddTags(locator Locator, tags ...string) error {
+	if len(tags) == 0 {
+		return errors.New("at least one tag is required")
+	}
+	return s.restHelper.send("POST", fmt.Sprintf("%s/tags", locator), NewTags(tags...), "build tags")
+}
+
+// ReplaceTags replaces all tags of the build with the given ones. Pass no tags to remove all of them.
+func (s *BuildService) ReplaceTags(locator Locator, tags ...string) error {
+	return s.restHelper.send("PUT", fmt.Sprintf("%s/tags", locator), NewTags(tags...), "build tags")
+}
+
+// RemoveTags removes the given tags from the build, keeping the others
+func (s *BuildService) RemoveTags(locator Locator, tags ...string) error {
+	current, err := s.GetTags(locator)
+	if err != nil {
+		return err
+	}
+
+	removed := make(map[string]bool)
+	for _, t := range tags {
+		removed[t] = true
+	}
+	remaining := make([]string, 0, len(current))
+	for _, t := range current {
+		if !removed[t] {
+			remaining = append(remaining, t)
+		}
+	}
+
+	return s.ReplaceTags(locator, remaining...)
+}
+
+// Pin pins the build, so it is not removed by clean-up rules. Comment is optional.
+func (s *BuildService) Pin(locator Locator, comment string) error {
+	_, err := s.restHelper.putTextPlain(fmt.Sprintf("%s/pin", locator), comment, "build pin")
+	return err
+}
+
+// Unpin removes the pin from the build
+func (s *BuildService) Unpin(locator Locator) error {
+	return s.restHelper.delete(fmt.Sprintf("%s/pin", locator), "build pin")
+}
+
+// SetComment sets the comment of the build, replacing any existing comment
+func (s *BuildService) SetComment(locator Locator, comment string) error {
+	_, err := s.restHelper.putTextPlain(fmt.Sprintf("%s/comment", locator), comment, "build comment")
+	return err
+}
+
+// DeleteComment removes the comment of the build
+func (s *BuildService) DeleteComment(locator Locator) error {
+	return s.restHelper.delete(fmt.Sprintf("%s/comment", locator), "build comment")
+}
+
+// MarkSuccessful changes the status of a finished build to successful, recording the reason as a comment
+func (s *BuildService) MarkSuccessful(locator Locator, reason string) error {
+	return s.setStatus(locator, BuildStatusSuccess, reason)
+}
+
+// MarkFailed changes the status of a finished build to failed, recording the reason as a comment
+func (s *BuildService) MarkFailed(locator Locator, reason string) error {
+	return s.setStatus(locator, BuildStatusFailure, reason)
+}
+
+func (s *BuildService) setStatus(locator Locator, status BuildStatus, reason string) error {
+	update := &buildStatusUpdateJSON{
+		Status:  status,
+		Comment: reason,
+	}
+	return s.restHelper.send("PUT", fmt.Sprintf("%s/status", locator), update, "build status")
+}
@@ -0,0 +1,439 @@
+package teamcity
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ChainEdgeKind is the kind of relation between two build configurations of a build chain
+type ChainEdgeKind string
+
+const (
+	// ChainEdgeSnapshot is a snapshot dependency
+	ChainEdgeSnapshot ChainEdgeKind = "snapshot"
+	// ChainEdgeArtifact is an artifact dependency
+	ChainEdgeArtifact ChainEdgeKind = "artifact"
+	// ChainEdgeFinishTrigger is a finish build trigger
+	ChainEdgeFinishTrigger ChainEdgeKind = "finishTrigger"
+)
+
+// ChainNode is a build configuration of a build chain
+type ChainNode struct {
+	ID        string
+	Name      string
+	ProjectID string
+	// External is true for build configurations that were not loaded, but are referenced by loaded ones
+	External bool
+}
+
+// ChainEdge relates a build configuration to the one it depends on, or is triggered by.
+// Upstream runs first, Downstream depends on or is triggered by it.
+type ChainEdge struct {
+	Upstream   string
+	Downstream string
+	Kind       ChainEdgeKind
+}
+
+// BuildChain is the graph of the dependencies and finish build triggers between build configurations
+type BuildChain struct {
+	nodes map[string]*ChainNode
+	edges map[ChainEdge]bool
+}
+
+// NewBuildChain returns an empty BuildChain
+func NewBuildChain() *BuildChain {
+	return &BuildChain{
+		nodes: make(map[string]*ChainNode),
+		edges: make(map[ChainEdge]bool),
+	}
+}
+
+// AddNode adds a build configuration, replacing the external node of the same id if any
+func (g *BuildChain) AddNode(node *ChainNode) {
+	if current, ok := g.nodes[node.ID]; ok && !current.External {
+		return
+	}
+	g.nodes[node.ID] = node
+}
+
+// AddEdge adds a relation between two build configurations, adding them as external nodes if they are unknown
+func (g *BuildChain) AddEdge(upstream string, downstream string, kind ChainEdgeKind) {
+	for _, id := range []string{upstream, downstream} {
+		if _, ok := g.nodes[id]; !ok {
+			g.nodes[id] = &ChainNode{ID: id, External: true}
+		}
+	}
+	g.edges[ChainEdge{Upstream: upstream, Downstream: downstream, Kind: kind}] = true
+}
+
+// Node returns the build configuration with given id, or nil if it is not part of the chain
+func (g *BuildChain) Node(id string) *ChainNode {
+	return g.nodes[id]
+}
+
+// Nodes returns the build configurations of the chain, sorted by id
+func (g *BuildChain) Nodes() []*ChainNode {
+	out := make([]*ChainNode, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Edges returns the relations of the chain, sorted by upstream, downstream and kind
+func (g *BuildChain) Edges() []*ChainEdge {
+	out := make([]*ChainEdge, 0, len(g.edges))
+	for e := range g.edges {
+		e := e
+		out = append(out, &e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Upstream != out[j].Upstream {
+			return out[i].Upstream < out[j].Upstream
+		}
+		if out[i].Downstream != out[j].Downstream {
+			return out[i].Downstream < out[j].Downstream
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// adjacency returns the sorted, distinct downstream or upstream neighbours of each node
+func (g *BuildChain) adjacency(downstream bool) map[string][]string {
+	out := make(map[string][]string)
+	for _, e := range g.Edges() {
+		from, to := e.Upstream, e.Downstream
+		if !downstream {
+			from, to = to, from
+		}
+		if n := out[from]; len(n) == 0 || indexOf(n, to) < 0 {
+			out[from] = append(out[from], to)
+		}
+	}
+	for _, n := range out {
+		sort.Strings(n)
+	}
+	return out
+}
+
+// Upstream returns the build configurations the one with given id depends on or is triggered by, directly or transitively, sorted by id
+func (g *BuildChain) Upstream(id string) []string {
+	return g.reachable(id, g.adjacency(false))
+}
+
+// Downstream returns the build configurations that depend on or are triggered by the one with given id, directly or transitively, sorted by id.
+// These are the build configurations impacted when it is changed or deleted.
+func (g *BuildChain) Downstream(id string) []string {
+	return g.reachable(id, g.adjacency(true))
+}
+
+func (g *BuildChain) reachable(id string, adjacency map[string][]string) []string {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	var out []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !seen[next] {
+				seen[next] = true
+				out = append(out, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Cycles returns the groups of build configurations that depend on each other, including the ones depending on themselves.
+// Each group is sorted by id, and groups are sorted by their first id.
+func (g *BuildChain) Cycles() [][]string {
+	adjacency := g.adjacency(true)
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var out [][]string
+
+	// Tarjan's strongly connected components
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, next := range adjacency[id] {
+			if _, ok := index[next]; !ok {
+				connect(next)
+				if low[next] < low[id] {
+					low[id] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[id] {
+				low[id] = index[next]
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == id {
+				break
+			}
+		}
+		if len(component) > 1 || indexOf(adjacency[id], id) >= 0 {
+			sort.Strings(component)
+			out = append(out, component)
+		}
+	}
+	for _, n := range g.Nodes() {
+		if _, ok := index[n.ID]; !ok {
+			connect(n.ID)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+	return out
+}
+
+// TopologicalOrder returns the build configurations ordered so that each one comes after the ones it depends on or is triggered by.
+// Build configurations that don't depend on each other are ordered by id. An error is returned if the chain has cycles.
+func (g *BuildChain) TopologicalOrder() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		var groups []string
+		for _, c := range cycles {
+			groups = append(groups, strings.Join(c, ", "))
+		}
+		return nil, fmt.Errorf("build chain has cycles between: (%s)", strings.Join(groups, "), ("))
+	}
+
+	adjacency := g.adjacency(true)
+	pending := make(map[string]int)
+	for _, next := range adjacency {
+		for _, id := range next {
+			pending[id]++
+		}
+	}
+	var ready []string
+	for _, n := range g.Nodes() {
+		if pending[n.ID] == 0 {
+			ready = append(ready, n.ID)
+		}
+	}
+	out := make([]string, 0, len(g.nodes))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		out = append(out, current)
+		for _, next := range adjacency[current] {
+			pending[next]--
+			if pending[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+		sort.Strings(ready)
+	}
+	return out, nil
+}
+
+// chainEdgeStyles are the DOT and Mermaid styles of each edge kind
+var chainEdgeStyles = map[ChainEdgeKind]struct{ dot, mermaid string }{
+	ChainEdgeSnapshot:      {dot: "solid", mermaid: "-->"},
+	ChainEdgeArtifact:      {dot: "dashed", mermaid: "-.->"},
+	ChainEdgeFinishTrigger: {dot: "dotted", mermaid: "==>"},
+}
+
+func (n *ChainNode) label() string {
+	if n.Name == "" {
+		return n.ID
+	}
+	return n.Name
+}
+
+// WriteDOT writes the chain as a Graphviz digraph. Snapshot dependencies are drawn solid, artifact dependencies dashed and finish build triggers dotted.
+func (g *BuildChain) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph \"build chain\" {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes() {
+		style := ""
+		if n.External {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", dotQuote(n.ID), dotQuote(n.label()), style)
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "  %s -> %s [style=%s];\n", dotQuote(e.Upstream), dotQuote(e.Downstream), chainEdgeStyles[e.Kind].dot)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the chain as a Mermaid flowchart. Snapshot dependencies are drawn as plain arrows, artifact dependencies dotted and finish build triggers thick.
+func (g *BuildChain) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes() {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", n.ID, strings.ReplaceAll(n.label(), `"`, "#quot;"))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "  %s %s %s\n", e.Upstream, chainEdgeStyles[e.Kind].mermaid, e.Downstream)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// BuildChainLoader reads build chains from a server
+type BuildChainLoader struct {
+	restHelper *restHelper
+}
+
+// NewBuildChainLoader returns a BuildChainLoader reading from the server of the given client
+func NewBuildChainLoader(client *Client) *BuildChainLoader {
+	return &BuildChainLoader{
+		restHelper: newRestHelper(client.HTTPClient, client.commonBase.New()),
+	}
+}
+
+// buildChainFields are the fields of a build configuration needed to load its relations
+const buildChainFields = "id,name,projectId,snapshot-dependencies(snapshot-dependency(id,disabled,source-buildType(id))),artifact-dependencies(artifact-dependency(id,disabled,source-buildType(id))),triggers(trigger(id,type,disabled,properties(property(name,value))))"
+
+// LoadProject loads the build configurations of the project and its subprojects, along with the ones of other projects depending on them, transitively.
+// Build configurations of other projects they depend on or are triggered by are part of the chain as external nodes.
+// See Load for the build configurations triggered by them.
+func (l *BuildChainLoader) LoadProject(projectID string) (*BuildChain, error) {
+	var d locatorDimensions
+	d.add("affectedProject", fmt.Sprintf("(id:%s)", projectID))
+	var out struct {
+		Items []*rawBuildTypeJSON `json:"buildType"`
+	}
+	if err := l.restHelper.getWithFields("buildTypes"+d.query(), getFields{Fields: "buildType(" + buildChainFields + ")"}, &out, "build types"); err != nil {
+		return nil, err
+	}
+
+	g := NewBuildChain()
+	ids := make([]string, len(out.Items))
+	fetched := make(map[string]*rawBuildTypeJSON, len(out.Items))
+	for i, bt := range out.Items {
+		ids[i] = bt.ID
+		fetched[bt.ID] = bt
+	}
+	if err := l.load(g, ids, fetched, false); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Load loads the build configurations with given ids, along with all the ones they depend on or are triggered by,
+// and the ones depending on them through snapshot or artifact dependencies, transitively.
+// The server cannot search finish build triggers, so build configurations triggered by loaded ones are only part of the chain if they are loaded for another reason.
+func (l *BuildChainLoader) Load(buildTypeIDs ...string) (*BuildChain, error) {
+	g := NewBuildChain()
+	if err := l.load(g, buildTypeIDs, make(map[string]*rawBuildTypeJSON), true); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// load adds the build configurations with given ids to the chain, reading the ones not fetched yet, along with the ones depending on them, transitively.
+// The build configurations they depend on or are triggered by are loaded as well if upstream is true, and are external nodes otherwise.
+func (l *BuildChainLoader) load(g *BuildChain, ids []string, fetched map[string]*rawBuildTypeJSON, upstream bool) error {
+	queue := append([]string{}, ids...)
+	loaded := make(map[string]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if loaded[id] {
+			continue
+		}
+		loaded[id] = true
+
+		bt, ok := fetched[id]
+		if !ok {
+			bt = &rawBuildTypeJSON{}
+			if err := l.restHelper.getWithFields("buildTypes/"+LocatorID(id).String(), getFields{Fields: buildChainFields}, bt, "build type"); err != nil {
+				return err
+			}
+		}
+		upstreamIDs := addChainBuildType(g, bt)
+		if upstream {
+			queue = append(queue, upstreamIDs...)
+		}
+
+		dependents, err := l.dependents(id)
+		if err != nil {
+			return err
+		}
+		for _, dependent := range dependents {
+			if !loaded[dependent.ID] {
+				fetched[dependent.ID] = dependent
+				queue = append(queue, dependent.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// dependents returns the build configurations with a snapshot or artifact dependency on the one with given id
+func (l *BuildChainLoader) dependents(id string) ([]*rawBuildTypeJSON, error) {
+	var out []*rawBuildTypeJSON
+	for _, dimension := range []string{"snapshotDependency", "artifactDependency"} {
+		var d locatorDimensions
+		d.add(dimension, fmt.Sprintf("(from:(id:%s),recursive:false)", id))
+		var items struct {
+			Items []*rawBuildTypeJSON `json:"buildType"`
+		}
+		if err := l.restHelper.getWithFields("buildTypes"+d.query(), getFields{Fields: "buildType(" + buildChainFields + ")"}, &items, "dependent build types"); err != nil {
+			return nil, err
+		}
+		out = append(out, items.Items...)
+	}
+	return out, nil
+}
+
+// addChainBuildType adds the build configuration and its relations, returning the ids of its upstream build configurations
+func addChainBuildType(g *BuildChain, bt *rawBuildTypeJSON) []string {
+	g.AddNode(&ChainNode{ID: bt.ID, Name: bt.Name, ProjectID: bt.ProjectID})
+
+	var upstream []string
+	add := func(id string, kind ChainEdgeKind) {
+		g.AddEdge(id, bt.ID, kind)
+		upstream = append(upstream, id)
+	}
+	dependencies := func(items []*settingItem, kind ChainEdgeKind) {
+		for _, item := range items {
+			if item.SourceBuildType != nil && (item.Disabled == nil || !*item.Disabled) {
+				add(item.SourceBuildType.ID, kind)
+			}
+		}
+	}
+	if bt.SnapshotDependencies != nil {
+		dependencies(bt.SnapshotDependencies.Items, ChainEdgeSnapshot)
+	}
+	if bt.ArtifactDependencies != nil {
+		dependencies(bt.ArtifactDependencies.Items, ChainEdgeArtifact)
+	}
+	if bt.Triggers != nil {
+		for _, t := range bt.Triggers.Items {
+			if t.Type != BuildTriggerBuildFinish || t.Disabled != nil && *t.Disabled || t.Properties == nil {
+				continue
+			}
+			if id, ok := t.Properties.GetOk("dependsOn"); ok && id != "" {
+				add(id, ChainEdgeFinishTrigger)
+			}
+		}
+	}
+	return upstream
+}
@@ -0,0 +1,123 @@
+package teamcity
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBuildChain() *BuildChain {
+	g := NewBuildChain()
+	g.AddNode(&ChainNode{ID: "Lib", Name: "Library"})
+	g.AddNode(&ChainNode{ID: "App", Name: "App \"main\""})
+	g.AddNode(&ChainNode{ID: "Deploy", Name: "Deploy"})
+	g.AddNode(&ChainNode{ID: "Docs", Name: "Docs"})
+	g.AddEdge("Lib", "App", ChainEdgeSnapshot)
+	g.AddEdge("Lib", "App", ChainEdgeArtifact)
+	g.AddEdge("App", "Deploy", ChainEdgeFinishTrigger)
+	g.AddEdge("Tools", "App", ChainEdgeArtifact)
+	return g
+}
+
+func Test_BuildChainTraversal(t *testing.T) {
+	g := newTestBuildChain()
+
+	assert.True(t, g.Node("Tools").External)
+	assert.Equal(t, []string{"Lib", "Tools"}, g.Upstream("App"))
+	assert.Equal(t, []string{"App", "Deploy"}, g.Downstream("Lib"))
+	assert.Empty(t, g.Downstream("Deploy"))
+	assert.Empty(t, g.Cycles())
+
+	order, err := g.TopologicalOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Docs", "Lib", "Tools", "App", "Deploy"}, order)
+}
+
+func Test_BuildChainCycles(t *testing.T) {
+	g := newTestBuildChain()
+	g.AddEdge("Deploy", "Lib", ChainEdgeFinishTrigger)
+	g.AddEdge("Docs", "Docs", ChainEdgeSnapshot)
+
+	assert.Equal(t, [][]string{{"App", "Deploy", "Lib"}, {"Docs"}}, g.Cycles())
+	_, err := g.TopologicalOrder()
+	assert.EqualError(t, err, "build chain has cycles between: (App, Deploy, Lib), (Docs)")
+}
+
+func Test_BuildChainExport(t *testing.T) {
+	g := newTestBuildChain()
+
+	var dot bytes.Buffer
+	require.NoError(t, g.WriteDOT(&dot))
+	assert.Equal(t, `digraph "build chain" {
+  rankdir=LR;
+  "App" [label="App \"main\""];
+  "Deploy" [label="Deploy"];
+  "Docs" [label="Docs"];
+  "Lib" [label="Library"];
+  "Tools" [label="Tools", style=dashed];
+  "App" -> "Deploy" [style=dotted];
+  "Lib" -> "App" [style=dashed];
+  "Lib" -> "App" [style=solid];
+  "Tools" -> "App" [style=dashed];
+}
+`, dot.String())
+
+	var mermaid bytes.Buffer
+	require.NoError(t, g.WriteMermaid(&mermaid))
+	assert.Equal(t, `flowchart LR
+  App["App #quot;main#quot;"]
+  Deploy["Deploy"]
+  Docs["Docs"]
+  Lib["Library"]
+  Tools["Tools"]
+  App ==> Deploy
+  Lib -.-> App
+  Lib --> App
+  Tools -.-> App
+`, mermaid.String())
+}
+
+func Test_BuildChainLoad(t *testing.T) {
+	require := require.New(t)
+	responses := map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3AApp": `{"id":"App","name":"App","projectId":"Proj",
+			"snapshot-dependencies":{"snapshot-dependency":[{"id":"Lib","source-buildType":{"id":"Lib"}}]},
+			"artifact-dependencies":{"artifact-dependency":[{"id":"ARTIFACT_1","disabled":true,"source-buildType":{"id":"Old"}}]},
+			"triggers":{"trigger":[{"id":"TRIGGER_1","type":"buildDependencyTrigger","properties":{"property":[{"name":"dependsOn","value":"Lib"}]}}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3ALib": `{"id":"Lib","name":"Library","projectId":"Shared"}`,
+		"/httpAuth/app/rest/buildTypes?locator=affectedProject:(id:Proj)": `{"buildType":[
+			{"id":"App","name":"App","projectId":"Proj","snapshot-dependencies":{"snapshot-dependency":[{"id":"Lib","source-buildType":{"id":"Lib"}}]}}]}`,
+	}
+	for _, id := range []string{"App", "Lib", "Tests"} {
+		responses["/httpAuth/app/rest/buildTypes?locator=snapshotDependency:(from:(id:"+id+"),recursive:false)"] = `{}`
+		responses["/httpAuth/app/rest/buildTypes?locator=artifactDependency:(from:(id:"+id+"),recursive:false)"] = `{}`
+	}
+	// Tests depends on App from another project, so it is only found by searching the dependents of App
+	responses["/httpAuth/app/rest/buildTypes?locator=artifactDependency:(from:(id:App),recursive:false)"] = `{"buildType":[
+		{"id":"Tests","name":"Tests","projectId":"QA","artifact-dependencies":{"artifact-dependency":[{"id":"ARTIFACT_1","source-buildType":{"id":"App"}}]}}]}`
+	client, _ := newRecordingServer(t, responses)
+	sut := NewBuildChainLoader(client)
+
+	g, err := sut.Load("App")
+	require.NoError(err)
+	require.Equal([]*ChainNode{
+		{ID: "App", Name: "App", ProjectID: "Proj"},
+		{ID: "Lib", Name: "Library", ProjectID: "Shared"},
+		{ID: "Tests", Name: "Tests", ProjectID: "QA"},
+	}, g.Nodes())
+	require.Equal([]*ChainEdge{
+		{Upstream: "App", Downstream: "Tests", Kind: ChainEdgeArtifact},
+		{Upstream: "Lib", Downstream: "App", Kind: ChainEdgeFinishTrigger},
+		{Upstream: "Lib", Downstream: "App", Kind: ChainEdgeSnapshot},
+	}, g.Edges())
+	require.Equal([]string{"App", "Tests"}, g.Downstream("Lib"))
+
+	g, err = sut.LoadProject("Proj")
+	require.NoError(err)
+	require.True(g.Node("Lib").External)
+	require.False(g.Node("Tests").External)
+	require.Equal([]string{"Tests"}, g.Downstream("App"))
+	require.Len(g.Edges(), 2)
+}
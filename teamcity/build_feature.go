@@ -11,6 +11,7 @@ import (
 )
 
 // BuildFeature is an interface representing different types of build features that can be added to a build type.
+// Features of other types can be read by registering them with RegisterBuildFeatureType.
 type BuildFeature interface {
 	ID() string
 	SetID(value string)
@@ -134,35 +135,69 @@ func (s *BuildFeatureService) readBuildFeatureResponse(resp *http.Response) (Bui
 		return nil, err
 	}
 
-	var payload buildFeatureJSON
-	if err := json.Unmarshal(bodyBytes, &payload); err != nil {
-		return nil, err
-	}
+	return decodeBuildFeature(s.BuildTypeID, bodyBytes)
+}
 
-	var out BuildFeature
-	switch payload.Type {
-	case "commit-status-publisher":
-		{
-			var csp FeatureCommitStatusPublisher
-			if err := csp.UnmarshalJSON(bodyBytes); err != nil {
-				return nil, err
-			}
+// buildFeatureBase implements the accessors common to build features
+type buildFeatureBase struct {
+	id          string
+	disabled    bool
+	buildTypeID string
+}
 
-			out = &csp
-		}
-	case "golang":
-		{
-			var csp FeatureGolangPublisher
-			if err := csp.UnmarshalJSON(bodyBytes); err != nil {
-				return nil, err
-			}
-
-			out = &csp
-		}
-	default:
-		return nil, fmt.Errorf("Unsupported build feature type: '%s' (id:'%s') for buildTypeID: %s", payload.Type, payload.ID, s.BuildTypeID)
-	}
+// ID returns the ID for this instance.
+func (f *buildFeatureBase) ID() string {
+	return f.id
+}
+
+// SetID sets the ID for this instance.
+func (f *buildFeatureBase) SetID(value string) {
+	f.id = value
+}
+
+// Disabled returns whether this build feature is disabled or not.
+func (f *buildFeatureBase) Disabled() bool {
+	return f.disabled
+}
 
-	out.SetBuildTypeID(s.BuildTypeID)
-	return out, nil
+// SetDisabled sets whether this build feature is disabled or not.
+func (f *buildFeatureBase) SetDisabled(value bool) {
+	f.disabled = value
+}
+
+// BuildTypeID is a getter for the Build Type ID associated with this build feature.
+func (f *buildFeatureBase) BuildTypeID() string {
+	return f.buildTypeID
+}
+
+// SetBuildTypeID is a setter for the Build Type ID associated with this build feature.
+func (f *buildFeatureBase) SetBuildTypeID(value string) {
+	f.buildTypeID = value
+}
+
+func (f *buildFeatureBase) marshalFeature(featureType string, props *Properties) ([]byte, error) {
+	return json.Marshal(&buildFeatureJSON{
+		ID:         f.id,
+		Disabled:   NewBool(f.disabled),
+		Properties: props,
+		Inherited:  NewFalse(),
+		Type:       featureType,
+	})
+}
+
+// unmarshalFeature reads the common settings of a build feature of the given type, and returns its properties
+func (f *buildFeatureBase) unmarshalFeature(featureType string, data []byte) (*Properties, error) {
+	var aux buildFeatureJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return nil, err
+	}
+	if aux.Type != featureType {
+		return nil, fmt.Errorf("invalid type %s trying to deserialize into %s build feature", aux.Type, featureType)
+	}
+	f.id = aux.ID
+	f.disabled = aux.Disabled != nil && *aux.Disabled
+	if aux.Properties == nil {
+		return NewPropertiesEmpty(), nil
+	}
+	return aux.Properties, nil
 }
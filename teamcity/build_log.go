@@ -0,0 +1,228 @@
+package teamcity
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// BuildLogLine is a single line of a build log, as delivered by BuildLogService.Tail.
+// Offset is the byte offset of the line within the log, which can be passed to Tail to resume.
+type BuildLogLine struct {
+	Offset int64
+	Text   string
+
+	// ServiceMessage is set if the line contains a "##teamcity[...]" service message
+	ServiceMessage *ServiceMessage
+
+	// Err is set on the last value sent before the channel is closed if tailing failed
+	Err error
+}
+
+// BuildLogTailOptions controls how BuildLogService.Tail polls for new log content
+type BuildLogTailOptions struct {
+	// Offset is the byte offset to start reading from. Use 0 to read the whole log.
+	Offset int64
+	// PollInterval is the delay between requests while the build is running. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// FullLogPollInterval replaces PollInterval once the server is found to ignore the Range header, as the whole log is then downloaded on each request.
+	// Defaults to 30 seconds, or PollInterval if longer.
+	FullLogPollInterval time.Duration
+}
+
+type buildLogStateJSON struct {
+	State string `json:"state,omitempty" xml:"state"`
+}
+
+// BuildLogService provides operations for retrieving the log of a build
+type BuildLogService struct {
+	BuildID    int
+	httpClient *http.Client
+	webSling   *sling.Sling
+	restHelper *restHelper
+}
+
+func newBuildLogService(buildID int, c *http.Client, base *sling.Sling, web *sling.Sling) *BuildLogService {
+	sling := base.New().Path("builds/")
+	return &BuildLogService{
+		BuildID:    buildID,
+		httpClient: c,
+		webSling:   web,
+		restHelper: newRestHelper(c, sling),
+	}
+}
+
+type buildLogQuery struct {
+	BuildID int  `url:"buildId"`
+	Plain   bool `url:"plain,omitempty"`
+}
+
+// Get streams the full plain text log of the build. The caller must close the returned reader.
+func (s *BuildLogService) Get() (io.ReadCloser, error) {
+	body, _, err := s.getFrom(0)
+	return body, err
+}
+
+// getFrom streams the build log starting at the given byte offset.
+// A Range header is sent, but if the server ignores it the skipped bytes are discarded on the client, and rangeIgnored is true.
+func (s *BuildLogService) getFrom(offset int64) (body io.ReadCloser, rangeIgnored bool, err error) {
+	req := s.webSling.New().Get("downloadBuildLog.html").
+		QueryStruct(buildLogQuery{BuildID: s.BuildID, Plain: true}).
+		Set("Accept", "text/plain")
+	if offset > 0 {
+		req = req.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	request, err := req.Request()
+	if err != nil {
+		return nil, false, err
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch response.StatusCode {
+	case 206:
+		return response.Body, false, nil
+	case 200:
+		if offset > 0 {
+			if _, err := io.CopyN(io.Discard, response.Body, offset); err != nil && err != io.EOF {
+				response.Body.Close()
+				return nil, true, err
+			}
+		}
+		return response.Body, offset > 0, nil
+	case 416:
+		// Nothing new past offset
+		response.Body.Close()
+		return io.NopCloser(strings.NewReader("")), false, nil
+	}
+
+	defer response.Body.Close()
+	dt, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	return nil, false, s.restHelper.handleRestError(dt, response.StatusCode, "GET", "build log")
+}
+
+func (s *BuildLogService) isFinished() (bool, error) {
+	var out buildLogStateJSON
+	err := s.restHelper.getWithFields(LocatorIDInt(s.BuildID).String(), getFields{Fields: "state"}, &out, "build state")
+	if err != nil {
+		return false, err
+	}
+	return out.State == "finished", nil
+}
+
+// Tail polls the log of the build and sends each new line to the returned channel until the build finishes or ctx is cancelled.
+// Only complete lines are sent; a trailing line without a newline is held back until it is completed or the build finishes.
+// The channel is closed when tailing stops. If tailing fails, the last value sent has Err set.
+func (s *BuildLogService) Tail(ctx context.Context, opt BuildLogTailOptions) <-chan BuildLogLine {
+	out := make(chan BuildLogLine)
+	interval := opt.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	fullLogInterval := opt.FullLogPollInterval
+	if fullLogInterval <= 0 {
+		fullLogInterval = 30 * time.Second
+	}
+	if fullLogInterval < interval {
+		fullLogInterval = interval
+	}
+
+	go func() {
+		defer close(out)
+		offset := opt.Offset
+		for {
+			// Check the state before reading, so the last read after the build finishes contains the whole log
+			finished, err := s.isFinished()
+			if err != nil {
+				s.sendTailError(ctx, out, err)
+				return
+			}
+
+			var rangeIgnored bool
+			offset, rangeIgnored, err = s.readLines(ctx, offset, finished, out)
+			if err != nil {
+				s.sendTailError(ctx, out, err)
+				return
+			}
+			if rangeIgnored {
+				interval = fullLogInterval
+			}
+
+			if finished {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+
+	return out
+}
+
+func (s *BuildLogService) sendTailError(ctx context.Context, out chan<- BuildLogLine, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	select {
+	case out <- BuildLogLine{Err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// readLines sends every complete line after offset and returns the offset following the last line sent,
+// and whether the server ignored the Range header and sent the whole log.
+// If final is true, a trailing line without a newline is sent as well.
+func (s *BuildLogService) readLines(ctx context.Context, offset int64, final bool, out chan<- BuildLogLine) (int64, bool, error) {
+	body, rangeIgnored, err := s.getFrom(offset)
+	if err != nil {
+		return offset, rangeIgnored, err
+	}
+	defer body.Close()
+
+	reader := bufio.NewReader(body)
+	for {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return offset, rangeIgnored, err
+		}
+		complete := strings.HasSuffix(text, "\n")
+		if text == "" || (!complete && !final) {
+			return offset, rangeIgnored, nil
+		}
+
+		line := BuildLogLine{
+			Offset: offset,
+			Text:   strings.TrimRight(text, "\r\n"),
+		}
+		if msg, ok, _ := ParseServiceMessage(line.Text); ok {
+			line.ServiceMessage = msg
+		}
+
+		select {
+		case out <- line:
+		case <-ctx.Done():
+			return offset, rangeIgnored, ctx.Err()
+		}
+		offset += int64(len(text))
+
+		if !complete {
+			return offset, rangeIgnored, nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBuildLogServer serves a build log that grows with every poll and reports the build finished once fully written.
+// The Range header is honoured only if rangeSupported is set, and the number of bytes of the log sent is counted in downloaded.
+type fakeBuildLogServer struct {
+	mu             sync.Mutex
+	chunks         []string
+	served         int
+	rangeSupported bool
+	downloaded     int
+}
+
+func (f *fakeBuildLogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/httpAuth/app/rest/builds/id:7":
+		state := "running"
+		if f.served >= len(f.chunks) {
+			state = "finished"
+		}
+		w.Write([]byte(`{"state":"` + state + `"}`))
+	case "/httpAuth/downloadBuildLog.html":
+		if f.served < len(f.chunks) {
+			f.served++
+		}
+		log := strings.Join(f.chunks[:f.served], "")
+		var offset int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset); err == nil && f.rangeSupported {
+			if offset >= len(log) {
+				w.WriteHeader(416)
+				return
+			}
+			log = log[offset:]
+			w.WriteHeader(206)
+		}
+		f.downloaded += len(log)
+		w.Write([]byte(log))
+	default:
+		w.WriteHeader(404)
+	}
+}
+
+func Test_BuildLogTail(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	fake := &fakeBuildLogServer{chunks: []string{
+		"[10:00:00]i: Step 1/1\n[10:00:01]i: ##teamcity[testSta",
+		"rted name='TestA']\n",
+		"[10:00:02]i: done",
+	}}
+	client := newTestClient(t, fake)
+
+	// The server ignores the Range header, so the first ranged request switches to the longer interval before the last poll
+	start := time.Now()
+	var lines []BuildLogLine
+	for line := range client.BuildLogService(7).Tail(context.Background(), BuildLogTailOptions{PollInterval: time.Millisecond, FullLogPollInterval: 50 * time.Millisecond}) {
+		require.NoError(line.Err)
+		lines = append(lines, line)
+	}
+	assert.GreaterOrEqual(time.Since(start), 50*time.Millisecond)
+
+	require.Len(lines, 3)
+	assert.Equal("[10:00:00]i: Step 1/1", lines[0].Text)
+	assert.Equal(int64(0), lines[0].Offset)
+	assert.Nil(lines[0].ServiceMessage)
+
+	assert.Equal(int64(22), lines[1].Offset)
+	require.NotNil(lines[1].ServiceMessage)
+	assert.Equal(&TestStartedEvent{Name: "TestA"}, lines[1].ServiceMessage.Event())
+
+	assert.Equal("[10:00:02]i: done", lines[2].Text)
+}
+
+func Test_BuildLogTailWithRange(t *testing.T) {
+	require := require.New(t)
+
+	fake := &fakeBuildLogServer{rangeSupported: true, chunks: []string{"line1\n", "line2\n", "line3\n"}}
+	client := newTestClient(t, fake)
+
+	var texts []string
+	for line := range client.BuildLogService(7).Tail(context.Background(), BuildLogTailOptions{PollInterval: time.Millisecond}) {
+		require.NoError(line.Err)
+		texts = append(texts, line.Text)
+	}
+	require.Equal([]string{"line1", "line2", "line3"}, texts)
+	require.Equal(len("line1\nline2\nline3\n"), fake.downloaded)
+}
+
+func Test_BuildLogGet(t *testing.T) {
+	fake := &fakeBuildLogServer{chunks: []string{"line1\nline2\n"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClientWithAddress(TokenAuth("token"), server.URL, http.DefaultClient)
+	require.NoError(t, err)
+
+	_, err = client.BuildLogService(7).Get()
+	// Token auth is served outside of /httpAuth/
+	assert.Error(t, err)
+
+	client, _ = NewClientWithAddress(BasicAuth("admin", "admin"), server.URL, http.DefaultClient)
+	body, err := client.BuildLogService(7).Get()
+	require.NoError(t, err)
+	defer body.Close()
+	dt, _ := io.ReadAll(body)
+	assert.Equal(t, "line1\nline2\n", string(dt))
+}
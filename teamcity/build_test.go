@@ -0,0 +1,147 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// recordingServer answers GETs from fixed responses, keyed by escaped path and optionally "?locator=...", or from resources created earlier.
+// Other requests are recorded; DELETEs are answered with 204, while POST and PUT requests echo their body and store it under "<path>/id:<id>",
+// so created resources can be read back.
+type recordingServer struct {
+	mu        sync.Mutex
+	responses map[string]string
+	created   map[string][]byte
+	requests  []recordedRequest
+}
+
+// newTestClient returns a client of a test server serving requests with the given handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithAddress(BasicAuth("admin", "admin"), server.URL, http.DefaultClient)
+	require.NoError(t, err)
+	return client
+}
+
+func newRecordingServer(t *testing.T, responses map[string]string) (*Client, *recordingServer) {
+	fake := &recordingServer{responses: responses, created: make(map[string][]byte)}
+	return newTestClient(t, fake), fake
+}
+
+func (f *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.EscapedPath(), "/")
+	if r.Method == "GET" {
+		if resp, ok := f.responses[path+"?locator="+r.URL.Query().Get("locator")]; ok {
+			w.Write([]byte(resp))
+		} else if resp, ok := f.responses[path]; ok {
+			w.Write([]byte(resp))
+		} else if body, ok := f.created[path]; ok {
+			w.Write(body)
+		} else {
+			w.WriteHeader(404)
+		}
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	f.requests = append(f.requests, recordedRequest{Method: r.Method, Path: path, Body: string(body)})
+	if r.Method == "DELETE" {
+		w.WriteHeader(204)
+		return
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(body, &created) == nil && created.ID != "" {
+		f.created[path+"/id%3A"+created.ID] = body
+	}
+	w.Write(body)
+}
+
+func Test_BuildDeserialize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var sut Build
+	require.NoError(json.Unmarshal([]byte(buildJSON), &sut))
+
+	assert.Equal(42, sut.ID)
+	assert.Equal(BuildStatusSuccess, sut.Status)
+	assert.True(sut.Pinned)
+	assert.Equal("release candidate", sut.Comment.Text)
+	assert.Equal([]string{"v1.2.3", "release"}, sut.Tags.Names())
+	assert.Equal("id%3A42", sut.Locator().String())
+}
+
+func Test_BuildRemoveTagsKeepsOthers(t *testing.T) {
+	require := require.New(t)
+
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/builds/id%3A42/tags": `{"count":2,"tag":[{"name":"v1.2.3"},{"name":"release"}]}`,
+	})
+
+	err := client.Builds.RemoveTags(LocatorIDInt(42), "release")
+	require.NoError(err)
+
+	require.Len(fake.requests, 1)
+	put := fake.requests[0]
+	require.Equal("PUT", put.Method)
+	require.JSONEq(`{"count":1,"tag":[{"name":"v1.2.3"}]}`, put.Body)
+}
+
+func Test_BuildPinAndMarkFailed(t *testing.T) {
+	require := require.New(t)
+
+	client, fake := newRecordingServer(t, nil)
+
+	locator := LocatorBuildNumber("Release_Build", "1.2.3")
+	require.NoError(client.Builds.Pin(locator, "released"))
+	require.NoError(client.Builds.MarkFailed(locator, "bad release"))
+
+	require.Equal(recordedRequest{
+		Method: "PUT",
+		Path:   "/httpAuth/app/rest/builds/buildType%3A%28id%3ARelease_Build%29%2Cnumber%3A1.2.3/pin",
+		Body:   "released",
+	}, fake.requests[0])
+	require.JSONEq(`{"status":"FAILURE","comment":"bad release"}`, fake.requests[1].Body)
+}
+
+const buildJSON = `
+{
+	"id": 42,
+	"buildTypeId": "Release_Build",
+	"number": "1.2.3",
+	"status": "SUCCESS",
+	"state": "finished",
+	"pinned": true,
+	"statusText": "Tests passed: 10",
+	"finishDate": "20200301T093015+0000",
+	"comment": {
+		"text": "release candidate",
+		"user": {"username": "admin"}
+	},
+	"tags": {
+		"count": 2,
+		"tag": [{"name": "v1.2.3"}, {"name": "release"}]
+	}
+}
+`
@@ -123,7 +123,11 @@ func (b *BuildType) MarshalJSON() ([]byte, error) {
 		out.Description = b.Description
 	}
 	if len(b.Steps) > 0 {
-		out.Steps = b.serializeSteps()
+		steps, err := b.serializeSteps()
+		if err != nil {
+			return nil, err
+		}
+		out.Steps = steps
 	}
 	return json.Marshal(out)
 }
@@ -175,12 +179,16 @@ func (b *BuildType) read(dt *buildTypeJSON) error {
 	return nil
 }
 
-func (b *BuildType) serializeSteps() *stepsJSON {
+func (b *BuildType) serializeSteps() (*stepsJSON, error) {
 	out := &stepsJSON{Count: int32(len(b.Steps)), Items: make([]*stepJSON, len(b.Steps))}
 	for i := 0; i < len(b.Steps); i++ {
-		out.Items[i] = b.Steps[i].serializable()
+		step, err := serializeStep(b.Steps[i])
+		if err != nil {
+			return nil, err
+		}
+		out.Items[i] = step
 	}
-	return out
+	return out, nil
 }
 
 // BuildTypeReference represents a subset detail of a Build Type
@@ -219,14 +227,18 @@ type BuildTypeService struct {
 	sling      *sling.Sling
 	httpClient *http.Client
 	restHelper *restHelper
+	// commonHelper reads the projects and VCS roots a build type refers to, which are not under buildTypes/
+	commonHelper *restHelper
 }
 
 func newBuildTypeService(base *sling.Sling, httpClient *http.Client) *BuildTypeService {
+	common := newRestHelper(httpClient, base.New())
 	sling := base.Path("buildTypes/")
 	return &BuildTypeService{
-		httpClient: httpClient,
-		sling:      sling,
-		restHelper: newRestHelper(httpClient, sling),
+		httpClient:   httpClient,
+		sling:        sling,
+		restHelper:   newRestHelper(httpClient, sling),
+		commonHelper: common,
 	}
 }
 
@@ -285,8 +297,7 @@ func (s *BuildTypeService) Update(buildType *BuildType) (*BuildType, error) {
 	}
 
 	//Update Parameters
-	var parameters *Properties
-	err = s.restHelper.put(buildType.ID+"/parameters", buildType.Parameters, &parameters, "build type parameters")
+	err = putParameters(s.restHelper, buildType.ID+"/parameters", buildType.Parameters, fmt.Sprintf("build type '%s'", buildType.ID))
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +305,11 @@ func (s *BuildTypeService) Update(buildType *BuildType) (*BuildType, error) {
 	//Update Steps
 	if buildType.Steps != nil && len(buildType.Steps) > 0 {
 		var steps []Step
-		err = s.restHelper.putCustom(buildType.ID+"/steps", buildType.serializeSteps(), &steps, "build type steps", stepsReadingFunc)
+		serialized, err := buildType.serializeSteps()
+		if err != nil {
+			return nil, err
+		}
+		err = s.restHelper.putCustom(buildType.ID+"/steps", serialized, &steps, "build type steps", stepsReadingFunc)
 		if err != nil {
 			return nil, err
 		}
@@ -350,6 +365,11 @@ func (s *BuildTypeService) AttachVcsRootEntry(id string, entry *VcsRootEntry) er
 	return nil
 }
 
+// DetachVcsRoot removes the VCS root with given id from this build type
+func (s *BuildTypeService) DetachVcsRoot(id string, vcsRootID string) error {
+	return s.restHelper.delete(fmt.Sprintf("%s/vcs-root-entries/%s", LocatorID(id), LocatorID(vcsRootID)), "vcs root entry")
+}
+
 // AddStep creates a new build step for the build configuration with given id.
 func (s *BuildTypeService) AddStep(id string, step Step) (Step, error) {
 	var created Step
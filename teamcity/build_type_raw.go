@@ -0,0 +1,88 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// rawBuildTypeJSON is the raw representation of a build configuration, including the settings managed by separate services.
+// Settings are kept raw, so types the package cannot deserialize can still be compared, exported and deleted.
+type rawBuildTypeJSON struct {
+	ID                   string                   `json:"id,omitempty"`
+	Name                 string                   `json:"name,omitempty"`
+	Description          string                   `json:"description,omitempty"`
+	ProjectID            string                   `json:"projectId,omitempty"`
+	TemplateFlag         *bool                    `json:"templateFlag,omitempty"`
+	Templates            *Templates               `json:"templates,omitempty"`
+	Settings             *Properties              `json:"settings,omitempty"`
+	Parameters           *Parameters              `json:"parameters,omitempty"`
+	Entries              *VcsRootEntries          `json:"vcs-root-entries,omitempty"`
+	Steps                *rawSteps                `json:"steps,omitempty"`
+	Triggers             *rawTriggers             `json:"triggers,omitempty"`
+	Features             *rawFeatures             `json:"features,omitempty"`
+	SnapshotDependencies *rawSnapshotDependencies `json:"snapshot-dependencies,omitempty"`
+	ArtifactDependencies *rawArtifactDependencies `json:"artifact-dependencies,omitempty"`
+	AgentRequirements    *rawAgentRequirements    `json:"agent-requirements,omitempty"`
+}
+
+type rawSteps struct {
+	Items []*settingItem `json:"step"`
+}
+
+type rawTriggers struct {
+	Items []*settingItem `json:"trigger"`
+}
+
+type rawFeatures struct {
+	Items []*settingItem `json:"feature"`
+}
+
+type rawSnapshotDependencies struct {
+	Items []*settingItem `json:"snapshot-dependency"`
+}
+
+type rawArtifactDependencies struct {
+	Items []*settingItem `json:"artifact-dependency"`
+}
+
+type rawAgentRequirements struct {
+	Items []*settingItem `json:"agent-requirement"`
+}
+
+// settingItem is the common raw representation of steps, triggers, features, dependencies and agent requirements,
+// used to handle them regardless of their concrete type
+type settingItem struct {
+	ID              string              `json:"id,omitempty"`
+	Name            string              `json:"name,omitempty"`
+	Type            string              `json:"type,omitempty"`
+	Disabled        *bool               `json:"disabled,omitempty"`
+	Inherited       *bool               `json:"inherited,omitempty"`
+	Properties      *Properties         `json:"properties,omitempty"`
+	SourceBuildType *BuildTypeReference `json:"source-buildType,omitempty"`
+}
+
+func newSettingItem(v interface{}) (*settingItem, error) {
+	dt, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out settingItem
+	if err := json.Unmarshal(dt, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (i *settingItem) inherited() bool {
+	return i.Inherited != nil && *i.Inherited
+}
+
+// signature returns a representation of the item that is equal for equivalent items, regardless of their id and property order
+func (i *settingItem) signature() string {
+	var source string
+	if i.SourceBuildType != nil {
+		source = i.SourceBuildType.ID
+	}
+	disabled := i.Disabled != nil && *i.Disabled
+	return fmt.Sprintf("%s|%s|%t|%s|%s", i.Type, i.Name, disabled, source, propertiesSignature(i.Properties))
+}
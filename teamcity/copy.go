@@ -0,0 +1,300 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CopyOptions controls the IDs and names a project or build configuration is copied under
+type CopyOptions struct {
+	// Name of the copy. Defaults to the name of the source.
+	Name string
+	// Recursive also copies the subprojects of a project. Ignored when copying a build configuration.
+	Recursive bool
+	// MapID returns the ID of the copy of a project, VCS root, template or build configuration. Required.
+	// References between copied resources are rewritten to the new IDs, while references to resources that are not copied are kept.
+	MapID func(id string) string
+}
+
+// Copier clones projects and build configurations within a server
+type Copier struct {
+	client     *Client
+	exporter   *Exporter
+	importer   *Importer
+	restHelper *restHelper
+}
+
+// NewCopier returns a Copier working on the server of the given client
+func NewCopier(client *Client) *Copier {
+	return &Copier{
+		client:     client,
+		exporter:   NewExporter(client),
+		importer:   NewImporter(client),
+		restHelper: newRestHelper(client.HTTPClient, client.commonBase.New()),
+	}
+}
+
+// copySource is the locator of the project or build configuration a copy is made from
+type copySource struct {
+	Locator string `json:"locator"`
+}
+
+// newProjectDescription is the body of a request making the server copy a project
+type newProjectDescription struct {
+	ID                        string      `json:"id"`
+	Name                      string      `json:"name"`
+	ParentProject             *copySource `json:"parentProject"`
+	SourceProject             *copySource `json:"sourceProject"`
+	CopyAllAssociatedSettings bool        `json:"copyAllAssociatedSettings"`
+}
+
+// newBuildTypeDescription is the body of a request making the server copy a build configuration
+type newBuildTypeDescription struct {
+	ID                        string      `json:"id"`
+	Name                      string      `json:"name"`
+	SourceBuildType           *copySource `json:"sourceBuildType"`
+	CopyAllAssociatedSettings bool        `json:"copyAllAssociatedSettings"`
+	ShareVCSRoots             bool        `json:"shareVCSRoots"`
+}
+
+// CopyProject copies the project with given id, along with its VCS roots, templates, build configurations and project features, into the parent project.
+// It returns the IDs the resources were copied to, keyed by their original IDs.
+//
+// A recursive copy is made by the server, so values of password parameters and secure properties are copied as well, then the copied resources are renamed with MapID.
+// Since the server always copies subprojects, a non-recursive copy is recreated from an export instead, and fails if the project has such values, which the server never returns.
+func (c *Copier) CopyProject(projectID string, parentProjectID string, opt CopyOptions) (map[string]string, error) {
+	if opt.MapID == nil {
+		return nil, errors.New("MapID is required to copy a project")
+	}
+	project, err := c.exporter.exportProject(projectID, opt.Recursive)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Name != "" {
+		project.Name = opt.Name
+	}
+	if !opt.Recursive {
+		if warnings := secureValueWarnings(project); len(warnings) > 0 {
+			return nil, fmt.Errorf("cannot copy project '%s' without its subprojects: %s", projectID, strings.Join(warnings, ", "))
+		}
+		doc := &ProjectExport{Version: ProjectExportVersion, Project: project}
+		return c.importer.Import(doc, ImportOptions{ParentProjectID: parentProjectID, MapID: opt.MapID})
+	}
+
+	dt := &newProjectDescription{
+		ID:                        opt.MapID(projectID),
+		Name:                      project.Name,
+		ParentProject:             &copySource{Locator: "id:" + rootProjectID(parentProjectID)},
+		SourceProject:             &copySource{Locator: "id:" + projectID},
+		CopyAllAssociatedSettings: true,
+	}
+	var created ProjectReference
+	if err := c.restHelper.post("projects", dt, &created, "project copy"); err != nil {
+		return nil, fmt.Errorf("error copying project '%s': %s", projectID, err)
+	}
+	copied, err := c.exporter.exportProject(created.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server assigns its own IDs to the copied resources, so they are matched with the source by name and renamed
+	ids := make(map[string]string)
+	generated := make(map[string]string)
+	if err := c.renameProjectCopy(project, copied, opt.MapID, ids, generated); err != nil {
+		return nil, fmt.Errorf("project '%s' copied, but %s", created.ID, err)
+	}
+	if err := c.mapProjectReferences(copied, ids, generated); err != nil {
+		return nil, fmt.Errorf("project '%s' copied, but %s", ids[projectID], err)
+	}
+	return ids, nil
+}
+
+// CopyBuildType copies the build configuration or template with given id into the project.
+// The copy is made by the server, so values of password parameters and secure properties are copied as well.
+// Its templates and VCS roots are attached to the copy as they are, since they are not copied.
+// It returns the ID of the copy, keyed by the original ID.
+func (c *Copier) CopyBuildType(buildTypeID string, projectID string, opt CopyOptions) (map[string]string, error) {
+	if opt.MapID == nil {
+		return nil, errors.New("MapID is required to copy a build type")
+	}
+	name := opt.Name
+	if name == "" {
+		source, err := c.exporter.readBuildType(buildTypeID)
+		if err != nil {
+			return nil, err
+		}
+		name = source.Name
+	}
+
+	dt := &newBuildTypeDescription{
+		ID:                        opt.MapID(buildTypeID),
+		Name:                      name,
+		SourceBuildType:           &copySource{Locator: "id:" + buildTypeID},
+		CopyAllAssociatedSettings: true,
+		ShareVCSRoots:             true,
+	}
+	var created BuildTypeReference
+	if err := c.restHelper.post(fmt.Sprintf("projects/%s/buildTypes", LocatorID(projectID)), dt, &created, "build type copy"); err != nil {
+		return nil, fmt.Errorf("error copying build type '%s': %s", buildTypeID, err)
+	}
+	return map[string]string{buildTypeID: created.ID}, nil
+}
+
+// renameProjectCopy renames the resources of the copy to the IDs returned by mapID for their source,
+// recording the new IDs by source ID in ids, and by the ID assigned by the server in generated
+func (c *Copier) renameProjectCopy(source *ExportedProject, copied *ExportedProject, mapID func(string) string, ids map[string]string, generated map[string]string) error {
+	rename := func(path string, sourceID string, copiedID string, resourceDescription string) error {
+		id := mapID(sourceID)
+		ids[sourceID] = id
+		generated[copiedID] = id
+		if id == copiedID {
+			return nil
+		}
+		if _, err := c.restHelper.putTextPlain(fmt.Sprintf("%s/%s/id", path, LocatorID(copiedID)), id, resourceDescription); err != nil {
+			return fmt.Errorf("error renaming %s '%s' to '%s': %s", resourceDescription, copiedID, id, err)
+		}
+		return nil
+	}
+
+	if err := rename("projects", source.ID, copied.ID, "project"); err != nil {
+		return err
+	}
+	for _, root := range source.VcsRoots {
+		match := copiedVcsRoot(copied.VcsRoots, root.Name)
+		if match == nil {
+			return fmt.Errorf("no copy of vcs root '%s'", root.ID)
+		}
+		if err := rename("vcs-roots", root.ID, match.ID, "vcs root"); err != nil {
+			return err
+		}
+	}
+	for _, pair := range [][2][]*ExportedBuildType{{source.Templates, copied.Templates}, {source.BuildTypes, copied.BuildTypes}} {
+		for _, bt := range pair[0] {
+			match := copiedBuildType(pair[1], bt.Name)
+			if match == nil {
+				return fmt.Errorf("no copy of build type '%s'", bt.ID)
+			}
+			if err := rename("buildTypes", bt.ID, match.ID, "build type"); err != nil {
+				return err
+			}
+			match.ID = ids[bt.ID]
+		}
+	}
+	for _, sub := range source.Projects {
+		match := copiedProject(copied.Projects, sub.Name)
+		if match == nil {
+			return fmt.Errorf("no copy of project '%s'", sub.ID)
+		}
+		if err := c.renameProjectCopy(sub, match, mapID, ids, generated); err != nil {
+			return err
+		}
+	}
+	copied.ID = ids[source.ID]
+	return nil
+}
+
+// mapProjectReferences rewrites the references of the parameters, project features, build steps, triggers and build features of the copy,
+// such as %dep.<id>.<name>% parameter references or the VCS root of versioned settings, which still point to the source or to the IDs assigned by the server
+func (c *Copier) mapProjectReferences(copied *ExportedProject, ids map[string]string, generated map[string]string) error {
+	im := &projectImport{Importer: c.importer, ids: make(map[string]string, len(ids)+len(generated))}
+	for k, v := range generated {
+		im.ids[k] = v
+	}
+	for k, v := range ids {
+		im.ids[k] = v
+	}
+
+	if err := c.mapParameterReferences(im, c.client.ProjectParameterService(copied.ID), copied.Parameters); err != nil {
+		return err
+	}
+	projectPath := fmt.Sprintf("projects/%s", LocatorID(copied.ID))
+	if err := c.mapSettingReferences(im, projectPath+"/projectFeatures", "properties", copied.Features, "project feature"); err != nil {
+		return err
+	}
+	for _, bt := range append(append([]*ExportedBuildType{}, copied.Templates...), copied.BuildTypes...) {
+		if err := c.mapParameterReferences(im, c.client.BuildTypeParameterService(bt.ID), bt.Parameters); err != nil {
+			return err
+		}
+		path := fmt.Sprintf("buildTypes/%s", LocatorID(bt.ID))
+		if err := c.mapSettingReferences(im, path+"/steps", "parameters", bt.Steps, "build step"); err != nil {
+			return err
+		}
+		if err := c.mapSettingReferences(im, path+"/triggers", "parameters", bt.Triggers, "trigger"); err != nil {
+			return err
+		}
+		if err := c.mapSettingReferences(im, path+"/features", "parameters", bt.Features, "build feature"); err != nil {
+			return err
+		}
+	}
+	for _, sub := range copied.Projects {
+		if err := c.mapProjectReferences(sub, ids, generated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Copier) mapParameterReferences(im *projectImport, service *ParameterService, params map[string]ExportedParameter) error {
+	for _, name := range sortedParameterNames(params) {
+		value := im.mapReferences(params[name].Value)
+		if value == params[name].Value {
+			continue
+		}
+		prop := NewProperty(name, value)
+		if params[name].Spec != "" {
+			prop.Type = &Type{RawValue: params[name].Spec}
+		}
+		param, err := parameterFromProperty(prop)
+		if err != nil {
+			return err
+		}
+		if _, err := service.Set(param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapSettingReferences updates the properties of the settings under path that refer to copied resources, one at a time, so that secure properties are left untouched
+func (c *Copier) mapSettingReferences(im *projectImport, path string, propertiesPath string, settings []*ExportedSetting, resourceDescription string) error {
+	for _, s := range settings {
+		for _, name := range sortedKeys(s.Properties) {
+			value := im.mapProperty(name, s.Properties[name])
+			if value == s.Properties[name] {
+				continue
+			}
+			if _, err := c.restHelper.putTextPlain(fmt.Sprintf("%s/%s/%s/%s", path, s.ID, propertiesPath, name), value, resourceDescription+" property"); err != nil {
+				return fmt.Errorf("error updating property '%s' of %s '%s': %s", name, resourceDescription, s.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func copiedProject(projects []*ExportedProject, name string) *ExportedProject {
+	for _, p := range projects {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func copiedVcsRoot(roots []*ExportedVcsRoot, name string) *ExportedVcsRoot {
+	for _, root := range roots {
+		if root.Name == name {
+			return root
+		}
+	}
+	return nil
+}
+
+func copiedBuildType(buildTypes []*ExportedBuildType, name string) *ExportedBuildType {
+	for _, bt := range buildTypes {
+		if bt.Name == name {
+			return bt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package teamcity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CopyProjectOnServer(t *testing.T) {
+	require := require.New(t)
+
+	responses := make(map[string]string, len(exportSourceResponses))
+	for k, v := range exportSourceResponses {
+		responses[k] = v
+	}
+	for k, v := range copiedProjectResponses {
+		responses[k] = v
+	}
+	responses["/httpAuth/app/rest/buildTypes/id%3AProj_Sub_Lib"] = `{"id":"Proj_Sub_Lib","name":"Lib","projectId":"Proj_Sub",
+		"parameters":{"property":[{"name":"upstream","value":"%dep.Proj_Build.build.number%-%dep.Other.build.number%"}]}}`
+
+	client, fake := newRecordingServer(t, responses)
+	ids, err := NewCopier(client).CopyProject("Proj", "Releases", CopyOptions{
+		Name:      "Project 2.0",
+		Recursive: true,
+		MapID: func(id string) string {
+			return strings.Replace(id, "Proj", "Rel20", 1)
+		},
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{
+		"Proj":         "Rel20",
+		"Proj_Git":     "Rel20_Git",
+		"Proj_Tpl":     "Rel20_Tpl",
+		"Proj_Build":   "Rel20_Build",
+		"Proj_Sub":     "Rel20_Sub",
+		"Proj_Sub_Lib": "Rel20_Sub_Lib",
+	}, ids)
+
+	projects := fake.posted("projects")
+	require.Len(projects, 1)
+	require.JSONEq(`{"id":"Rel20","name":"Project 2.0","parentProject":{"locator":"id:Releases"},
+		"sourceProject":{"locator":"id:Proj"},"copyAllAssociatedSettings":true}`, projects[0])
+	require.Empty(fake.posted("buildTypes"))
+	require.Empty(fake.posted("vcs-roots"))
+
+	// The build configuration the server copied under another ID is renamed, and references to it are rewritten
+	require.Contains(fake.requests, recordedRequest{
+		Method: "PUT",
+		Path:   "/httpAuth/app/rest/buildTypes/id%3ARel20_Build_2/id",
+		Body:   "Rel20_Build",
+	})
+	var upstream *recordedRequest
+	for i, r := range fake.requests {
+		if r.Path == "/httpAuth/app/rest/buildTypes/id%3ARel20_Sub_Lib/parameters/upstream" {
+			upstream = &fake.requests[i]
+		}
+	}
+	require.NotNil(upstream)
+	require.Contains(upstream.Body, `"value":"%dep.Rel20_Build.build.number%-%dep.Other.build.number%"`)
+
+	// Properties of features, steps and triggers referring to copied resources are rewritten, others are left untouched
+	var properties []recordedRequest
+	for _, r := range fake.requests {
+		if strings.Contains(r.Path, "/projectFeatures/") || strings.Contains(r.Path, "/steps/") || strings.Contains(r.Path, "/triggers/") || strings.Contains(r.Path, "/features/") {
+			properties = append(properties, r)
+		}
+	}
+	require.Equal([]recordedRequest{
+		{Method: "PUT", Path: "/httpAuth/app/rest/projects/id%3ARel20/projectFeatures/PROJECT_EXT_2/properties/rootId", Body: "Rel20_Git"},
+		{Method: "PUT", Path: "/httpAuth/app/rest/buildTypes/id%3ARel20_Sub_Lib/steps/RUNNER_1/parameters/script.content", Body: "echo %dep.Rel20_Build.build.number%"},
+		{Method: "PUT", Path: "/httpAuth/app/rest/buildTypes/id%3ARel20_Sub_Lib/triggers/TRIGGER_1/parameters/dependsOn", Body: "Rel20_Build"},
+	}, properties)
+}
+
+func Test_CopyProjectNonRecursive(t *testing.T) {
+	require := require.New(t)
+
+	client, fake := newRecordingServer(t, exportSourceResponses)
+	ids, err := NewCopier(client).CopyProject("Proj", "Releases", CopyOptions{
+		MapID: func(id string) string { return "Copy_" + id },
+	})
+	require.NoError(err)
+	require.NotContains(ids, "Proj_Sub")
+	require.Len(fake.posted("projects"), 1)
+
+	// The dependency on the build type of the subproject that was not copied is kept
+	deps := fake.posted("buildTypes/id%3ACopy_Proj_Build/snapshot-dependencies")
+	require.Len(deps, 1)
+	require.Contains(deps[0], `"source-buildType":{"id":"Proj_Sub_Lib"}`)
+}
+
+func Test_CopyProjectNonRecursiveRefusesSecureValues(t *testing.T) {
+	responses := make(map[string]string, len(exportSourceResponses))
+	for k, v := range exportSourceResponses {
+		responses[k] = v
+	}
+	responses["/httpAuth/app/rest/projects/id%3AProj"] = `{"id":"Proj","name":"Project","parentProjectId":"_Root",
+		"parameters":{"property":[{"name":"env.TOKEN","value":"","type":{"rawValue":"password"}}]}}`
+
+	client, fake := newRecordingServer(t, responses)
+	_, err := NewCopier(client).CopyProject("Proj", "Releases", CopyOptions{
+		MapID: func(id string) string { return "Copy_" + id },
+	})
+	require.EqualError(t, err, "cannot copy project 'Proj' without its subprojects: value of password parameter 'env.TOKEN' of project 'Proj' was not exported")
+	require.Empty(t, fake.requests)
+}
+
+func Test_CopyBuildType(t *testing.T) {
+	require := require.New(t)
+
+	client, fake := newRecordingServer(t, exportSourceResponses)
+	sut := NewCopier(client)
+	ids, err := sut.CopyBuildType("Proj_Build", "Proj", CopyOptions{
+		Name:  "Build (release)",
+		MapID: func(id string) string { return id + "_Release" },
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{"Proj_Build": "Proj_Build_Release"}, ids)
+
+	buildTypes := fake.posted("projects/id%3AProj/buildTypes")
+	require.Len(buildTypes, 1)
+	require.JSONEq(`{"id":"Proj_Build_Release","name":"Build (release)","sourceBuildType":{"locator":"id:Proj_Build"},
+		"copyAllAssociatedSettings":true,"shareVCSRoots":true}`, buildTypes[0])
+
+	ids, err = sut.CopyBuildType("Proj_Build", "Proj", CopyOptions{
+		MapID: func(id string) string { return id + "_Copy" },
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{"Proj_Build": "Proj_Build_Copy"}, ids)
+	require.Contains(fake.posted("projects/id%3AProj/buildTypes")[1], `"name":"Build"`)
+
+	_, err = sut.CopyBuildType("Proj_Build", "Proj", CopyOptions{})
+	require.EqualError(err, "MapID is required to copy a build type")
+}
+
+// copiedProjectResponses is the copy of the project of exportSourceResponses made by the server, which assigned another ID to the build configuration
+var copiedProjectResponses = map[string]string{
+	"/httpAuth/app/rest/projects/id%3ARel20": `{"id":"Rel20","name":"Project 2.0","parentProjectId":"Releases","parameters":{"property":[{"name":"env.A","value":"1"}]}}`,
+	"/httpAuth/app/rest/projects/id%3ARel20/projectFeatures": `{"projectFeature":[{"id":"PROJECT_EXT_2","type":"versionedSettings",
+		"properties":{"property":[{"name":"enabled","value":"true"},{"name":"rootId","value":"Proj_Git"}]}}]}`,
+	"/httpAuth/app/rest/projects?locator=parentProject:(id:Rel20)":                  `{"project":[{"id":"Rel20_Sub"}]}`,
+	"/httpAuth/app/rest/vcs-roots?locator=project:(id:Rel20)":                       `{"vcs-root":[{"id":"Rel20_Git"}]}`,
+	"/httpAuth/app/rest/vcs-roots/id%3ARel20_Git":                                   `{"id":"Rel20_Git","name":"Git","vcsName":"jetbrains.git"}`,
+	"/httpAuth/app/rest/buildTypes?locator=project:(id:Rel20),templateFlag:any":     `{"buildType":[{"id":"Rel20_Tpl"},{"id":"Rel20_Build_2"}]}`,
+	"/httpAuth/app/rest/buildTypes/id%3ARel20_Tpl":                                  `{"id":"Rel20_Tpl","name":"Template","templateFlag":true}`,
+	"/httpAuth/app/rest/buildTypes/id%3ARel20_Build_2":                              `{"id":"Rel20_Build_2","name":"Build","templateFlag":false}`,
+	"/httpAuth/app/rest/projects/id%3ARel20_Sub":                                    `{"id":"Rel20_Sub","name":"Sub","parentProjectId":"Rel20","parameters":{"count":0}}`,
+	"/httpAuth/app/rest/projects/id%3ARel20_Sub/projectFeatures":                    `{}`,
+	"/httpAuth/app/rest/projects?locator=parentProject:(id:Rel20_Sub)":              `{}`,
+	"/httpAuth/app/rest/vcs-roots?locator=project:(id:Rel20_Sub)":                   `{}`,
+	"/httpAuth/app/rest/buildTypes?locator=project:(id:Rel20_Sub),templateFlag:any": `{"buildType":[{"id":"Rel20_Sub_Lib"}]}`,
+	"/httpAuth/app/rest/buildTypes/id%3ARel20_Sub_Lib": `{"id":"Rel20_Sub_Lib","name":"Lib","projectId":"Rel20_Sub",
+		"parameters":{"property":[{"name":"upstream","value":"%dep.Rel20_Build_2.build.number%-%dep.Other.build.number%"}]},
+		"steps":{"step":[{"id":"RUNNER_1","type":"simpleRunner","properties":{"property":[{"name":"script.content","value":"echo %dep.Rel20_Build_2.build.number%"}]}}]},
+		"triggers":{"trigger":[{"id":"TRIGGER_1","type":"buildDependencyTrigger","properties":{"property":[{"name":"dependsOn","value":"Rel20_Build_2"}]}}]},
+		"features":{"feature":[{"id":"BUILD_EXT_1","type":"commit-status-publisher","properties":{"property":[{"name":"vcsRootId","value":"Rel20_Git"}]}}]}}`,
+}
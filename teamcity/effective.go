@@ -0,0 +1,239 @@
+package teamcity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SettingOrigin tells where an item of an effective build configuration is defined
+type SettingOrigin string
+
+const (
+	// SettingOriginOwn is an item defined by the build configuration only
+	SettingOriginOwn SettingOrigin = "own"
+	// SettingOriginInherited is an item inherited from a template
+	SettingOriginInherited SettingOrigin = "inherited"
+	// SettingOriginOverridden is an item defined by a template, and redefined by the build configuration
+	SettingOriginOverridden SettingOrigin = "overridden"
+)
+
+// EffectiveValue is a parameter or option of an effective build configuration
+type EffectiveValue struct {
+	Name   string
+	Value  string
+	Origin SettingOrigin
+	// TemplateID is the template the value is inherited from, or whose value is overridden. Empty for own values.
+	TemplateID string
+}
+
+// EffectiveSetting is a step, trigger, feature, dependency or agent requirement of an effective build configuration
+type EffectiveSetting struct {
+	ID                string
+	Name              string
+	Type              string
+	Disabled          bool
+	SourceBuildTypeID string
+	Properties        map[string]string
+	Origin            SettingOrigin
+	// TemplateID is the template the item is inherited from, or whose item is overridden. Empty for own items.
+	TemplateID string
+}
+
+// EffectiveBuildType is the configuration a build configuration runs with once its templates are applied.
+// Values and items are sorted by name and id, except for steps, which are in execution order.
+type EffectiveBuildType struct {
+	ID        string
+	Name      string
+	ProjectID string
+	// Templates are the ids of the attached templates, by priority
+	Templates            []string
+	Settings             []*EffectiveValue
+	Parameters           []*EffectiveValue
+	Steps                []*EffectiveSetting
+	Triggers             []*EffectiveSetting
+	Features             []*EffectiveSetting
+	SnapshotDependencies []*EffectiveSetting
+	ArtifactDependencies []*EffectiveSetting
+	AgentRequirements    []*EffectiveSetting
+}
+
+// GetEffective retrieves the build configuration with given id along with its templates, and computes the configuration it runs with.
+// When templates define the same parameter, option or item, the template attached first wins. Values defined by the build configuration win over all templates.
+// Parameters inherited from projects are not included.
+func (s *BuildTypeService) GetEffective(id string) (*EffectiveBuildType, error) {
+	bt, err := s.getRaw(id)
+	if err != nil {
+		return nil, err
+	}
+	var templates []*rawBuildTypeJSON
+	if bt.Templates != nil {
+		for _, ref := range bt.Templates.Items {
+			t, err := s.getRaw(ref.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error reading template '%s' of build type '%s': %s", ref.ID, id, err)
+			}
+			templates = append(templates, t)
+		}
+	}
+	return resolveEffectiveBuildType(bt, templates), nil
+}
+
+func (s *BuildTypeService) getRaw(id string) (*rawBuildTypeJSON, error) {
+	var out rawBuildTypeJSON
+	if err := s.restHelper.get(LocatorID(id).String(), &out, "build type"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func resolveEffectiveBuildType(bt *rawBuildTypeJSON, templates []*rawBuildTypeJSON) *EffectiveBuildType {
+	out := &EffectiveBuildType{
+		ID:        bt.ID,
+		Name:      bt.Name,
+		ProjectID: bt.ProjectID,
+	}
+	for _, t := range templates {
+		out.Templates = append(out.Templates, t.ID)
+	}
+
+	settings := func(b *rawBuildTypeJSON) map[string]string { return exportProperties(b.Settings) }
+	parameters := func(b *rawBuildTypeJSON) map[string]string {
+		out := make(map[string]string)
+		for name, p := range exportParameters(b.Parameters) {
+			out[name] = p.Value
+		}
+		return out
+	}
+	out.Settings = resolveEffectiveValues(bt, templates, settings)
+	out.Parameters = resolveEffectiveValues(bt, templates, parameters)
+
+	out.Steps = resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.Steps == nil {
+			return nil
+		}
+		return b.Steps.Items
+	})
+	// The server lists the steps of a build configuration in execution order, inherited ones included
+	order := make(map[string]int)
+	if bt.Steps != nil {
+		for i, item := range bt.Steps.Items {
+			order[item.ID] = i
+		}
+	}
+	sort.SliceStable(out.Steps, func(i, j int) bool {
+		oi, iok := order[out.Steps[i].ID]
+		oj, jok := order[out.Steps[j].ID]
+		return iok && (!jok || oi < oj)
+	})
+
+	sortByID := func(items []*EffectiveSetting) []*EffectiveSetting {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+		return items
+	}
+	out.Triggers = sortByID(resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.Triggers == nil {
+			return nil
+		}
+		return b.Triggers.Items
+	}))
+	out.Features = sortByID(resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.Features == nil {
+			return nil
+		}
+		return b.Features.Items
+	}))
+	out.SnapshotDependencies = sortByID(resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.SnapshotDependencies == nil {
+			return nil
+		}
+		return b.SnapshotDependencies.Items
+	}))
+	out.ArtifactDependencies = sortByID(resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.ArtifactDependencies == nil {
+			return nil
+		}
+		return b.ArtifactDependencies.Items
+	}))
+	out.AgentRequirements = sortByID(resolveEffectiveSettings(bt, templates, func(b *rawBuildTypeJSON) []*settingItem {
+		if b.AgentRequirements == nil {
+			return nil
+		}
+		return b.AgentRequirements.Items
+	}))
+	return out
+}
+
+// resolveEffectiveValues merges the own values of the build configuration with the ones of its templates, sorted by name
+func resolveEffectiveValues(bt *rawBuildTypeJSON, templates []*rawBuildTypeJSON, values func(*rawBuildTypeJSON) map[string]string) []*EffectiveValue {
+	merged := make(map[string]*EffectiveValue)
+	for i := len(templates) - 1; i >= 0; i-- {
+		for name, value := range values(templates[i]) {
+			merged[name] = &EffectiveValue{Name: name, Value: value, Origin: SettingOriginInherited, TemplateID: templates[i].ID}
+		}
+	}
+	for name, value := range values(bt) {
+		v, ok := merged[name]
+		if !ok {
+			merged[name] = &EffectiveValue{Name: name, Value: value, Origin: SettingOriginOwn}
+			continue
+		}
+		v.Value = value
+		v.Origin = SettingOriginOverridden
+	}
+
+	out := make([]*EffectiveValue, 0, len(merged))
+	for _, v := range merged {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// resolveEffectiveSettings merges the own items of the build configuration with the ones of its templates, in template order
+func resolveEffectiveSettings(bt *rawBuildTypeJSON, templates []*rawBuildTypeJSON, items func(*rawBuildTypeJSON) []*settingItem) []*EffectiveSetting {
+	var out []*EffectiveSetting
+	byID := make(map[string]*EffectiveSetting)
+	for _, t := range templates {
+		for _, item := range items(t) {
+			if _, ok := byID[item.ID]; ok {
+				continue
+			}
+			s := newEffectiveSetting(item, SettingOriginInherited, t.ID)
+			byID[item.ID] = s
+			out = append(out, s)
+		}
+	}
+
+	for _, item := range items(bt) {
+		s, ok := byID[item.ID]
+		switch {
+		case !ok && item.inherited():
+			// Inherited from a template that is not attached anymore, or not visible
+			continue
+		case !ok:
+			out = append(out, newEffectiveSetting(item, SettingOriginOwn, ""))
+		case item.inherited():
+			// Inherited items can only be enabled or disabled in the build configuration
+			s.Disabled = item.Disabled != nil && *item.Disabled
+		default:
+			*s = *newEffectiveSetting(item, SettingOriginOverridden, s.TemplateID)
+		}
+	}
+	return out
+}
+
+func newEffectiveSetting(item *settingItem, origin SettingOrigin, templateID string) *EffectiveSetting {
+	out := &EffectiveSetting{
+		ID:         item.ID,
+		Name:       item.Name,
+		Type:       item.Type,
+		Disabled:   item.Disabled != nil && *item.Disabled,
+		Properties: exportProperties(item.Properties),
+		Origin:     origin,
+		TemplateID: templateID,
+	}
+	if item.SourceBuildType != nil {
+		out.SourceBuildTypeID = item.SourceBuildType.ID
+	}
+	return out
+}
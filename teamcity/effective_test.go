@@ -0,0 +1,63 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildTypeGetEffective(t *testing.T) {
+	require := require.New(t)
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3ATpl_A": `{"id":"Tpl_A","templateFlag":true,
+			"parameters":{"property":[{"name":"env.GOOS","value":"linux"},{"name":"env.GOFLAGS","value":"-mod=vendor"}]},
+			"settings":{"property":[{"name":"executionTimeoutMin","value":"30"}]},
+			"steps":{"step":[{"id":"RUNNER_1","name":"build","type":"simpleRunner"},{"id":"RUNNER_2","name":"test","type":"simpleRunner"}]},
+			"features":{"feature":[{"id":"BUILD_EXT_1","type":"golang"}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3ATpl_B": `{"id":"Tpl_B","templateFlag":true,
+			"parameters":{"property":[{"name":"env.GOOS","value":"windows"},{"name":"env.CGO_ENABLED","value":"0"}]},
+			"agent-requirements":{"agent-requirement":[{"id":"RQ_1","type":"exists","properties":{"property":[{"name":"property-name","value":"docker.version"}]}}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build": `{"id":"Proj_Build","name":"Build","projectId":"Proj",
+			"templates":{"buildType":[{"id":"Tpl_A"},{"id":"Tpl_B"}]},
+			"parameters":{"property":[{"name":"env.GOOS","value":"linux","inherited":true},{"name":"env.GOFLAGS","value":"-mod=mod"},{"name":"own","value":"1"}]},
+			"settings":{"property":[{"name":"executionTimeoutMin","value":"30","inherited":true}]},
+			"steps":{"step":[
+				{"id":"RUNNER_3","name":"lint","type":"simpleRunner"},
+				{"id":"RUNNER_1","name":"build","type":"simpleRunner","inherited":true},
+				{"id":"RUNNER_2","name":"test","type":"simpleRunner","inherited":true,"disabled":true}]},
+			"features":{"feature":[{"id":"BUILD_EXT_1","type":"golang","properties":{"property":[{"name":"test.format","value":"json"}]}}]},
+			"agent-requirements":{"agent-requirement":[{"id":"RQ_1","type":"exists","inherited":true,"properties":{"property":[{"name":"property-name","value":"docker.version"}]}}]}}`,
+	})
+
+	actual, err := client.BuildTypes.GetEffective("Proj_Build")
+	require.NoError(err)
+	require.Equal([]string{"Tpl_A", "Tpl_B"}, actual.Templates)
+	require.Equal([]*EffectiveValue{
+		{Name: "env.CGO_ENABLED", Value: "0", Origin: SettingOriginInherited, TemplateID: "Tpl_B"},
+		{Name: "env.GOFLAGS", Value: "-mod=mod", Origin: SettingOriginOverridden, TemplateID: "Tpl_A"},
+		{Name: "env.GOOS", Value: "linux", Origin: SettingOriginInherited, TemplateID: "Tpl_A"},
+		{Name: "own", Value: "1", Origin: SettingOriginOwn},
+	}, actual.Parameters)
+	require.Equal([]*EffectiveValue{
+		{Name: "executionTimeoutMin", Value: "30", Origin: SettingOriginInherited, TemplateID: "Tpl_A"},
+	}, actual.Settings)
+
+	require.Len(actual.Steps, 3)
+	require.Equal("RUNNER_3", actual.Steps[0].ID)
+	require.Equal(SettingOriginOwn, actual.Steps[0].Origin)
+	require.Equal("RUNNER_1", actual.Steps[1].ID)
+	require.Equal(SettingOriginInherited, actual.Steps[1].Origin)
+	require.Equal("RUNNER_2", actual.Steps[2].ID)
+	require.True(actual.Steps[2].Disabled)
+
+	require.Equal([]*EffectiveSetting{{
+		ID:         "BUILD_EXT_1",
+		Type:       "golang",
+		Properties: map[string]string{"test.format": "json"},
+		Origin:     SettingOriginOverridden,
+		TemplateID: "Tpl_A",
+	}}, actual.Features)
+	require.Len(actual.AgentRequirements, 1)
+	require.Equal("Tpl_B", actual.AgentRequirements[0].TemplateID)
+	require.Empty(actual.Triggers)
+}
@@ -0,0 +1,443 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportFormat is the encoding of a ProjectExport document
+type ExportFormat string
+
+const (
+	// ExportFormatJSON encodes documents as indented JSON
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatYAML encodes documents as YAML
+	ExportFormatYAML ExportFormat = "yaml"
+)
+
+// ProjectExportVersion is the version of the document format written by Exporter
+const ProjectExportVersion = 1
+
+// ProjectExport is a self-contained document describing a project tree, as written by Exporter and read by Importer.
+// Collections are sorted by ID, except steps whose order is significant, so exporting an unchanged project gives the same document.
+// Values of password parameters and secure properties are never returned by the server, so they are exported empty, see Warnings.
+type ProjectExport struct {
+	Version int              `json:"version" yaml:"version"`
+	Project *ExportedProject `json:"project" yaml:"project"`
+	// Warnings lists the password parameters and secure properties whose values could not be exported. It is not part of the document.
+	Warnings []string `json:"-" yaml:"-"`
+}
+
+// ExportedProject is a project within a ProjectExport
+type ExportedProject struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Parameters are keyed by their full name, such as "env.JAVA_HOME"
+	Parameters map[string]ExportedParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Features   []*ExportedSetting           `json:"features,omitempty" yaml:"features,omitempty"`
+	VcsRoots   []*ExportedVcsRoot           `json:"vcsRoots,omitempty" yaml:"vcsRoots,omitempty"`
+	Templates  []*ExportedBuildType         `json:"templates,omitempty" yaml:"templates,omitempty"`
+	BuildTypes []*ExportedBuildType         `json:"buildTypes,omitempty" yaml:"buildTypes,omitempty"`
+	Projects   []*ExportedProject           `json:"projects,omitempty" yaml:"projects,omitempty"`
+}
+
+// ExportedParameter is a parameter within a ProjectExport.
+// It is encoded as its value, or as an object with its value and specification if it has one.
+type ExportedParameter struct {
+	Value string `json:"value" yaml:"value"`
+	// Spec is the raw specification of the parameter, such as "password display='hidden'", see ParameterSpec
+	Spec string `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+// exportedParameterObject has the fields of ExportedParameter, without its encoding methods
+type exportedParameterObject ExportedParameter
+
+// MarshalJSON encodes the parameter as a string if it has no specification
+func (p ExportedParameter) MarshalJSON() ([]byte, error) {
+	if p.Spec == "" {
+		return json.Marshal(p.Value)
+	}
+	return json.Marshal(exportedParameterObject(p))
+}
+
+// UnmarshalJSON decodes a parameter encoded as a string or as an object
+func (p *ExportedParameter) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*p = ExportedParameter{}
+		return json.Unmarshal(data, &p.Value)
+	}
+	return json.Unmarshal(data, (*exportedParameterObject)(p))
+}
+
+// MarshalYAML encodes the parameter as a string if it has no specification
+func (p ExportedParameter) MarshalYAML() (interface{}, error) {
+	if p.Spec == "" {
+		return p.Value, nil
+	}
+	return exportedParameterObject(p), nil
+}
+
+// UnmarshalYAML decodes a parameter encoded as a string or as an object
+func (p *ExportedParameter) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = ExportedParameter{}
+		return node.Decode(&p.Value)
+	}
+	return node.Decode((*exportedParameterObject)(p))
+}
+
+// ExportedVcsRoot is a VCS root within a ProjectExport
+type ExportedVcsRoot struct {
+	ID      string `json:"id" yaml:"id"`
+	Name    string `json:"name" yaml:"name"`
+	VcsName string `json:"vcsName" yaml:"vcsName"`
+	// ModificationCheckInterval is zero if the root uses the server setting
+	ModificationCheckInterval int32             `json:"modificationCheckInterval,omitempty" yaml:"modificationCheckInterval,omitempty"`
+	Properties                map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// ExportedBuildType is a build configuration or template within a ProjectExport. Only its own settings are exported, not those inherited from templates.
+type ExportedBuildType struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Templates are the IDs of the templates attached to the build configuration
+	Templates []string `json:"templates,omitempty" yaml:"templates,omitempty"`
+	// Settings are the options of the build configuration, except the build counter
+	Settings   map[string]string            `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Parameters map[string]ExportedParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	VcsRoots             []*ExportedVcsRootEntry `json:"vcsRoots,omitempty" yaml:"vcsRoots,omitempty"`
+	Steps                []*ExportedSetting      `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Triggers             []*ExportedSetting      `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Features             []*ExportedSetting      `json:"features,omitempty" yaml:"features,omitempty"`
+	SnapshotDependencies []*ExportedSetting      `json:"snapshotDependencies,omitempty" yaml:"snapshotDependencies,omitempty"`
+	ArtifactDependencies []*ExportedSetting      `json:"artifactDependencies,omitempty" yaml:"artifactDependencies,omitempty"`
+	AgentRequirements    []*ExportedSetting      `json:"agentRequirements,omitempty" yaml:"agentRequirements,omitempty"`
+}
+
+// ExportedVcsRootEntry is a VCS root attached to an ExportedBuildType
+type ExportedVcsRootEntry struct {
+	VcsRootID     string `json:"vcsRootId" yaml:"vcsRootId"`
+	CheckoutRules string `json:"checkoutRules,omitempty" yaml:"checkoutRules,omitempty"`
+}
+
+// ExportedSetting is a step, trigger, feature, dependency or agent requirement within a ProjectExport.
+// Settings are kept as type and properties, so types not modelled by this package are exported as well.
+type ExportedSetting struct {
+	ID       string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type     string `json:"type" yaml:"type"`
+	Disabled bool   `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// SourceBuildTypeID is set for snapshot and artifact dependencies
+	SourceBuildTypeID string            `json:"sourceBuildTypeId,omitempty" yaml:"sourceBuildTypeId,omitempty"`
+	Properties        map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// Encode writes the document in the given format
+func (d *ProjectExport) Encode(w io.Writer, format ExportFormat) error {
+	switch format {
+	case ExportFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	case ExportFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("unsupported export format '%s'", format)
+}
+
+// DecodeProjectExport reads a document in the given format, as written by ProjectExport.Encode
+func DecodeProjectExport(r io.Reader, format ExportFormat) (*ProjectExport, error) {
+	var out ProjectExport
+	switch format {
+	case ExportFormatJSON:
+		if err := json.NewDecoder(r).Decode(&out); err != nil {
+			return nil, err
+		}
+	case ExportFormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&out); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+
+	if out.Version != ProjectExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d", out.Version)
+	}
+	if out.Project == nil {
+		return nil, errors.New("export has no project")
+	}
+	return &out, nil
+}
+
+// Exporter reads a project tree from the server into a ProjectExport
+type Exporter struct {
+	client     *Client
+	restHelper *restHelper
+}
+
+// NewExporter returns an Exporter reading from the server of the given client
+func NewExporter(client *Client) *Exporter {
+	return &Exporter{
+		client:     client,
+		restHelper: newRestHelper(client.HTTPClient, client.commonBase.New()),
+	}
+}
+
+// Export reads the project with given id, along with its subprojects, VCS roots, templates, build configurations and project features
+func (e *Exporter) Export(projectID string) (*ProjectExport, error) {
+	project, err := e.exportProject(projectID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectExport{
+		Version:  ProjectExportVersion,
+		Project:  project,
+		Warnings: secureValueWarnings(project),
+	}, nil
+}
+
+func (e *Exporter) exportProject(id string, recursive bool) (*ExportedProject, error) {
+	project, err := e.client.Projects.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	out := &ExportedProject{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Parameters:  exportParameters(project.Parameters),
+	}
+
+	var features struct {
+		Items []*settingItem `json:"projectFeature"`
+	}
+	err = e.restHelper.get(fmt.Sprintf("projects/%s/projectFeatures", LocatorID(id)), &features, "project features")
+	if err != nil {
+		return nil, err
+	}
+	out.Features = exportSettings(features.Items, true)
+
+	rootIDs, err := listVcsRootIDs(e.restHelper, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, rootID := range rootIDs {
+		var root vcsRootJSON
+		if err := e.restHelper.get("vcs-roots/"+LocatorID(rootID).String(), &root, "vcs root"); err != nil {
+			return nil, err
+		}
+		out.VcsRoots = append(out.VcsRoots, &ExportedVcsRoot{
+			ID:                        root.ID,
+			Name:                      root.Name,
+			VcsName:                   root.VcsName,
+			ModificationCheckInterval: root.ModificationCheckInterval,
+			Properties:                exportProperties(root.Properties),
+		})
+	}
+
+	buildTypes, err := listBuildTypes(e.restHelper, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range buildTypes {
+		bt, err := e.readBuildType(ref.ID)
+		if err != nil {
+			return nil, err
+		}
+		if bt.TemplateFlag != nil && *bt.TemplateFlag {
+			out.Templates = append(out.Templates, exportBuildType(bt))
+		} else {
+			out.BuildTypes = append(out.BuildTypes, exportBuildType(bt))
+		}
+	}
+
+	if !recursive {
+		return out, nil
+	}
+	subprojects, err := listSubprojectIDs(e.restHelper, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, subID := range subprojects {
+		sub, err := e.exportProject(subID, true)
+		if err != nil {
+			return nil, err
+		}
+		out.Projects = append(out.Projects, sub)
+	}
+	return out, nil
+}
+
+func (e *Exporter) readBuildType(id string) (*rawBuildTypeJSON, error) {
+	var out rawBuildTypeJSON
+	if err := e.restHelper.get("buildTypes/"+LocatorID(id).String(), &out, "build type"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func exportBuildType(bt *rawBuildTypeJSON) *ExportedBuildType {
+	out := &ExportedBuildType{
+		ID:          bt.ID,
+		Name:        bt.Name,
+		Description: bt.Description,
+		Settings:    exportProperties(bt.Settings),
+		Parameters:  exportParameters(bt.Parameters),
+	}
+	delete(out.Settings, "buildNumberCounter")
+	if len(out.Settings) == 0 {
+		out.Settings = nil
+	}
+
+	if bt.Templates != nil {
+		for _, t := range bt.Templates.Items {
+			out.Templates = append(out.Templates, t.ID)
+		}
+	}
+	if bt.Entries != nil {
+		for _, e := range bt.Entries.Items {
+			if e.Inherited != nil && *e.Inherited || e.VcsRoot == nil {
+				continue
+			}
+			out.VcsRoots = append(out.VcsRoots, &ExportedVcsRootEntry{VcsRootID: e.VcsRoot.ID, CheckoutRules: e.CheckoutRules})
+		}
+		sort.Slice(out.VcsRoots, func(i, j int) bool { return out.VcsRoots[i].VcsRootID < out.VcsRoots[j].VcsRootID })
+	}
+	if bt.Steps != nil {
+		out.Steps = exportSettings(bt.Steps.Items, false)
+	}
+	if bt.Triggers != nil {
+		out.Triggers = exportSettings(bt.Triggers.Items, true)
+	}
+	if bt.Features != nil {
+		out.Features = exportSettings(bt.Features.Items, true)
+	}
+	if bt.SnapshotDependencies != nil {
+		out.SnapshotDependencies = exportSettings(bt.SnapshotDependencies.Items, true)
+	}
+	if bt.ArtifactDependencies != nil {
+		out.ArtifactDependencies = exportSettings(bt.ArtifactDependencies.Items, true)
+	}
+	if bt.AgentRequirements != nil {
+		out.AgentRequirements = exportSettings(bt.AgentRequirements.Items, true)
+	}
+	return out
+}
+
+// exportSettings converts the own items, skipping the ones inherited from templates
+func exportSettings(items []*settingItem, sorted bool) []*ExportedSetting {
+	var out []*ExportedSetting
+	for _, item := range items {
+		if item.inherited() {
+			continue
+		}
+		s := &ExportedSetting{
+			ID:         item.ID,
+			Name:       item.Name,
+			Type:       item.Type,
+			Disabled:   item.Disabled != nil && *item.Disabled,
+			Properties: exportProperties(item.Properties),
+		}
+		if item.SourceBuildType != nil {
+			s.SourceBuildTypeID = item.SourceBuildType.ID
+		}
+		out = append(out, s)
+	}
+	if sorted {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	}
+	return out
+}
+
+func exportProperties(p *Properties) map[string]string {
+	if p == nil || len(p.Items) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(p.Items))
+	for _, item := range p.Items {
+		if item.Inherited != nil && *item.Inherited {
+			continue
+		}
+		out[item.Name] = item.Value
+	}
+	return out
+}
+
+func exportParameters(p *Parameters) map[string]ExportedParameter {
+	if p == nil {
+		return nil
+	}
+	out := make(map[string]ExportedParameter)
+	for _, item := range p.Items {
+		if item.Inherited {
+			continue
+		}
+		param := ExportedParameter{Value: item.Value}
+		if item.Spec != nil {
+			param.Spec = item.Spec.String()
+		}
+		out[item.Property().Name] = param
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// secureValueWarnings lists the password parameters and secure properties of the project tree, whose values the server does not return
+func secureValueWarnings(p *ExportedProject) []string {
+	var out []string
+	parameters := func(owner string, params map[string]ExportedParameter) {
+		for _, name := range sortedParameterNames(params) {
+			if spec, err := ParseParameterSpec(params[name].Spec); err == nil && spec.Type == ParameterSpecPassword && params[name].Value == "" {
+				out = append(out, fmt.Sprintf("value of password parameter '%s' of %s was not exported", name, owner))
+			}
+		}
+	}
+	properties := func(owner string, props map[string]string) {
+		for _, name := range sortedKeys(props) {
+			if strings.HasPrefix(name, "secure:") && props[name] == "" {
+				out = append(out, fmt.Sprintf("value of secure property '%s' of %s was not exported", name, owner))
+			}
+		}
+	}
+	settings := func(owner string, items []*ExportedSetting) {
+		for _, s := range items {
+			properties(fmt.Sprintf("%s of %s", s.ID, owner), s.Properties)
+		}
+	}
+
+	project := fmt.Sprintf("project '%s'", p.ID)
+	parameters(project, p.Parameters)
+	settings(project, p.Features)
+	for _, root := range p.VcsRoots {
+		properties(fmt.Sprintf("vcs root '%s'", root.ID), root.Properties)
+	}
+	for _, bt := range append(append([]*ExportedBuildType{}, p.Templates...), p.BuildTypes...) {
+		owner := fmt.Sprintf("build type '%s'", bt.ID)
+		parameters(owner, bt.Parameters)
+		properties(owner, bt.Settings)
+		for _, items := range [][]*ExportedSetting{bt.Steps, bt.Triggers, bt.Features, bt.SnapshotDependencies, bt.ArtifactDependencies, bt.AgentRequirements} {
+			settings(owner, items)
+		}
+	}
+	for _, sub := range p.Projects {
+		out = append(out, secureValueWarnings(sub)...)
+	}
+	return out
+}
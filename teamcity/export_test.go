@@ -0,0 +1,300 @@
+package teamcity
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// posted returns the bodies of the POST requests to the given path, relative to the REST API root
+func (f *recordingServer) posted(path string) []string {
+	var out []string
+	for _, r := range f.requests {
+		if r.Method == "POST" && r.Path == "/httpAuth/app/rest/"+path {
+			out = append(out, r.Body)
+		}
+	}
+	return out
+}
+
+func Test_ExportEncodesStableDocument(t *testing.T) {
+	require := require.New(t)
+
+	client, _ := newRecordingServer(t, exportSourceResponses)
+	doc, err := NewExporter(client).Export("Proj")
+	require.NoError(err)
+
+	var out bytes.Buffer
+	require.NoError(doc.Encode(&out, ExportFormatYAML))
+	require.Equal(exportExpectedYAML, out.String())
+
+	decoded, err := DecodeProjectExport(&out, ExportFormatYAML)
+	require.NoError(err)
+	assert.Equal(t, doc, decoded)
+}
+
+func Test_ImportRemapsIDs(t *testing.T) {
+	require := require.New(t)
+
+	doc, err := DecodeProjectExport(strings.NewReader(exportExpectedYAML), ExportFormatYAML)
+	require.NoError(err)
+
+	client, fake := newRecordingServer(t, nil)
+	ids, err := NewImporter(client).Import(doc, ImportOptions{
+		ParentProjectID: "Staging",
+		MapID: func(id string) string {
+			return strings.Replace(id, "Proj", "Copy", 1)
+		},
+	})
+	require.NoError(err)
+	require.Equal("Copy_Build", ids["Proj_Build"])
+
+	projects := fake.posted("projects")
+	require.Len(projects, 2)
+	require.Contains(projects[0], `"id":"Copy"`)
+	require.Contains(projects[0], `"parentProjectId":"Staging"`)
+	require.Contains(projects[0], `"property":[{"name":"env.A","value":"1"}]`)
+	require.Contains(projects[1], `"parentProjectId":"Copy"`)
+
+	roots := fake.posted("vcs-roots")
+	require.Len(roots, 1)
+	require.Contains(roots[0], `"id":"Copy_Git"`)
+	require.Contains(roots[0], `"project":{"id":"Copy"}`)
+
+	buildTypes := fake.posted("buildTypes")
+	require.Len(buildTypes, 3)
+	var tpl, bt rawBuildTypeJSON
+	require.NoError(json.Unmarshal([]byte(buildTypes[0]), &tpl))
+	require.NoError(json.Unmarshal([]byte(buildTypes[1]), &bt))
+	require.Equal("Copy_Tpl", tpl.ID)
+	require.Equal("", tpl.Description)
+	require.Equal("Copy_Build", bt.ID)
+	require.Equal("Copy", bt.ProjectID)
+	require.Equal("Copy_Tpl", bt.Templates.Items[0].ID)
+	require.Equal("Copy_Git", bt.Entries.Items[0].VcsRoot.ID)
+	dependsOn, _ := bt.Triggers.Items[0].Properties.GetOk("dependsOn")
+	require.Equal("Copy_Sub_Lib", dependsOn)
+
+	deps := fake.posted("buildTypes/id%3ACopy_Build/snapshot-dependencies")
+	require.Len(deps, 1)
+	require.Contains(deps[0], `"source-buildType":{"id":"Copy_Sub_Lib"}`)
+
+	require.Contains(fake.requests, recordedRequest{
+		Method: "PUT",
+		Path:   "/httpAuth/app/rest/buildTypes/id%3ACopy_Tpl/description",
+		Body:   "shared steps",
+	})
+}
+
+func Test_ImportRemapsIDProperties(t *testing.T) {
+	require := require.New(t)
+
+	doc, err := DecodeProjectExport(strings.NewReader(`{"version":1,"project":{"id":"Proj","name":"Project",
+		"vcsRoots":[{"id":"Proj_Git","name":"Git","vcsName":"jetbrains.git"}],
+		"features":[{"id":"PROJECT_EXT_1","type":"versionedSettings","properties":{"enabled":"true","rootId":"Proj_Git"}}],
+		"buildTypes":[{"id":"Proj_Build","name":"Build",
+			"features":[{"id":"BUILD_EXT_1","type":"commit-status-publisher","properties":{"vcsRootId":"Proj_Git","publisherId":"githubStatusPublisher"}},
+				{"id":"BUILD_EXT_2","type":"pullRequests","properties":{"vcsRootId":"Shared_Git"}}]}]}}`), ExportFormatJSON)
+	require.NoError(err)
+
+	client, fake := newRecordingServer(t, nil)
+	_, err = NewImporter(client).Import(doc, ImportOptions{
+		MapID: func(id string) string { return strings.Replace(id, "Proj", "Copy", 1) },
+	})
+	require.NoError(err)
+
+	features := fake.posted("projects/id%3ACopy/projectFeatures")
+	require.Len(features, 1)
+	require.Contains(features[0], `{"name":"rootId","value":"Copy_Git"}`)
+
+	buildTypes := fake.posted("buildTypes")
+	require.Len(buildTypes, 1)
+	var bt rawBuildTypeJSON
+	require.NoError(json.Unmarshal([]byte(buildTypes[0]), &bt))
+	publisherRoot, _ := bt.Features.Items[0].Properties.GetOk("vcsRootId")
+	require.Equal("Copy_Git", publisherRoot)
+	// The VCS root outside of the document is kept
+	pullRequestsRoot, _ := bt.Features.Items[1].Properties.GetOk("vcsRootId")
+	require.Equal("Shared_Git", pullRequestsRoot)
+}
+
+var exportSourceResponses = map[string]string{
+	"/httpAuth/app/rest/projects/id%3AProj": `{"id":"Proj","name":"Project","parentProjectId":"_Root",
+		"parameters":{"property":[{"name":"env.A","value":"1"},{"name":"inherited","value":"x","inherited":true}]}}`,
+	"/httpAuth/app/rest/projects/id%3AProj/projectFeatures": `{"projectFeature":[{"id":"PROJECT_EXT_1","type":"ReportTab",
+		"properties":{"property":[{"name":"title","value":"Docs"}]}}]}`,
+	"/httpAuth/app/rest/projects?locator=parentProject:(id:Proj)": `{"project":[{"id":"Proj_Sub"}]}`,
+	"/httpAuth/app/rest/vcs-roots?locator=project:(id:Proj)":      `{"vcs-root":[{"id":"Proj_Git"}]}`,
+	"/httpAuth/app/rest/vcs-roots/id%3AProj_Git": `{"id":"Proj_Git","name":"Git","vcsName":"jetbrains.git","project":{"id":"Proj"},
+		"properties":{"property":[{"name":"url","value":"https://example.com/repo.git"},{"name":"branch","value":"refs/heads/main"}]}}`,
+	"/httpAuth/app/rest/buildTypes?locator=project:(id:Proj),templateFlag:any": `{"buildType":[{"id":"Proj_Tpl","templateFlag":true},{"id":"Proj_Build","templateFlag":false}]}`,
+	"/httpAuth/app/rest/buildTypes/id%3AProj_Tpl": `{"id":"Proj_Tpl","name":"Template","description":"shared steps","templateFlag":true,
+		"steps":{"step":[{"id":"RUNNER_1","name":"test","type":"simpleRunner","properties":{"property":[{"name":"script.content","value":"make test"}]}}]}}`,
+	"/httpAuth/app/rest/buildTypes/id%3AProj_Build": `{"id":"Proj_Build","name":"Build","projectId":"Proj","templateFlag":false,
+		"templates":{"buildType":[{"id":"Proj_Tpl"}]},
+		"settings":{"property":[{"name":"buildNumberCounter","value":"42"},{"name":"artifactRules","value":"out/**"}]},
+		"vcs-root-entries":{"vcs-root-entry":[{"id":"Proj_Git","vcs-root":{"id":"Proj_Git"},"checkout-rules":"+:src"}]},
+		"steps":{"step":[{"id":"RUNNER_1","name":"test","type":"simpleRunner","inherited":true}]},
+		"triggers":{"trigger":[{"id":"TRIGGER_1","type":"buildDependencyTrigger","properties":{"property":[{"name":"dependsOn","value":"Proj_Sub_Lib"}]}}]},
+		"snapshot-dependencies":{"snapshot-dependency":[{"id":"Proj_Sub_Lib","type":"snapshot_dependency","source-buildType":{"id":"Proj_Sub_Lib","name":"Lib"},
+			"properties":{"property":[{"name":"run-build-on-the-same-agent","value":"false"}]}}]},
+		"agent-requirements":{"agent-requirement":[{"id":"RQ_1","type":"exists","properties":{"property":[{"name":"property-name","value":"docker.version"}]}}]}}`,
+	"/httpAuth/app/rest/projects/id%3AProj_Sub":                                    `{"id":"Proj_Sub","name":"Sub","parentProjectId":"Proj","parameters":{"count":0}}`,
+	"/httpAuth/app/rest/projects/id%3AProj_Sub/projectFeatures":                    `{}`,
+	"/httpAuth/app/rest/projects?locator=parentProject:(id:Proj_Sub)":              `{}`,
+	"/httpAuth/app/rest/vcs-roots?locator=project:(id:Proj_Sub)":                   `{}`,
+	"/httpAuth/app/rest/buildTypes?locator=project:(id:Proj_Sub),templateFlag:any": `{"buildType":[{"id":"Proj_Sub_Lib"}]}`,
+	"/httpAuth/app/rest/buildTypes/id%3AProj_Sub_Lib":                              `{"id":"Proj_Sub_Lib","name":"Lib","projectId":"Proj_Sub"}`,
+}
+
+const exportExpectedYAML = `version: 1
+project:
+  id: Proj
+  name: Project
+  parameters:
+    env.A: "1"
+  features:
+    - id: PROJECT_EXT_1
+      type: ReportTab
+      properties:
+        title: Docs
+  vcsRoots:
+    - id: Proj_Git
+      name: Git
+      vcsName: jetbrains.git
+      properties:
+        branch: refs/heads/main
+        url: https://example.com/repo.git
+  templates:
+    - id: Proj_Tpl
+      name: Template
+      description: shared steps
+      steps:
+        - id: RUNNER_1
+          name: test
+          type: simpleRunner
+          properties:
+            script.content: make test
+  buildTypes:
+    - id: Proj_Build
+      name: Build
+      templates:
+        - Proj_Tpl
+      settings:
+        artifactRules: out/**
+      vcsRoots:
+        - vcsRootId: Proj_Git
+          checkoutRules: +:src
+      triggers:
+        - id: TRIGGER_1
+          type: buildDependencyTrigger
+          properties:
+            dependsOn: Proj_Sub_Lib
+      snapshotDependencies:
+        - id: Proj_Sub_Lib
+          type: snapshot_dependency
+          sourceBuildTypeId: Proj_Sub_Lib
+          properties:
+            run-build-on-the-same-agent: "false"
+      agentRequirements:
+        - id: RQ_1
+          type: exists
+          properties:
+            property-name: docker.version
+  projects:
+    - id: Proj_Sub
+      name: Sub
+      buildTypes:
+        - id: Proj_Sub_Lib
+          name: Lib
+`
+
+func Test_ExportWarnsOfSecureValues(t *testing.T) {
+	require := require.New(t)
+
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/projects/id%3AProj": `{"id":"Proj","name":"Project","parameters":{"property":[
+			{"name":"deploy.key","value":"","type":{"rawValue":"password display='hidden'"}}]}}`,
+		"/httpAuth/app/rest/projects/id%3AProj/projectFeatures":                    `{}`,
+		"/httpAuth/app/rest/projects?locator=parentProject:(id:Proj)":              `{}`,
+		"/httpAuth/app/rest/vcs-roots?locator=project:(id:Proj)":                   `{"vcs-root":[{"id":"Proj_Git"}]}`,
+		"/httpAuth/app/rest/buildTypes?locator=project:(id:Proj),templateFlag:any": `{}`,
+		"/httpAuth/app/rest/vcs-roots/id%3AProj_Git": `{"id":"Proj_Git","name":"Git","vcsName":"jetbrains.git",
+			"properties":{"property":[{"name":"url","value":"https://example.com/repo.git"},{"name":"secure:password","value":""}]}}`,
+	})
+	doc, err := NewExporter(client).Export("Proj")
+	require.NoError(err)
+	require.Equal([]string{
+		"value of password parameter 'deploy.key' of project 'Proj' was not exported",
+		"value of secure property 'secure:password' of vcs root 'Proj_Git' was not exported",
+	}, doc.Warnings)
+
+	var out bytes.Buffer
+	require.NoError(doc.Encode(&out, ExportFormatJSON))
+	require.Contains(out.String(), `"deploy.key": {
+        "value": "",
+        "spec": "password display='hidden'"
+      }`)
+}
+
+func Test_ImportRejectsInvalidParameterSpec(t *testing.T) {
+	doc, err := DecodeProjectExport(strings.NewReader(`{"version":1,"project":{"id":"Proj","name":"Project",
+		"parameters":{"plain":"1","broken":{"value":"x","spec":"select data_1"}}}}`), ExportFormatJSON)
+	require.NoError(t, err)
+	require.Equal(t, ExportedParameter{Value: "1"}, doc.Project.Parameters["plain"])
+
+	client, fake := newRecordingServer(t, nil)
+	_, err = NewImporter(client).Import(doc, ImportOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parameter 'broken'")
+	assert.Empty(t, fake.posted("projects"))
+}
+
+func Test_ExportImportKeepsParameterSpecs(t *testing.T) {
+	require := require.New(t)
+
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/projects/id%3AProj": `{"id":"Proj","name":"Project","parameters":{"property":[
+			{"name":"env.TOKEN","value":"","type":{"rawValue":"password display='hidden'"}},
+			{"name":"stage","value":"prod","type":{"rawValue":"select label='Stage' data_1='dev' label_2='Production' data_2='prod'"}}]}}`,
+		"/httpAuth/app/rest/projects/id%3AProj/projectFeatures":                    `{}`,
+		"/httpAuth/app/rest/projects?locator=parentProject:(id:Proj)":              `{}`,
+		"/httpAuth/app/rest/vcs-roots?locator=project:(id:Proj)":                   `{}`,
+		"/httpAuth/app/rest/buildTypes?locator=project:(id:Proj),templateFlag:any": `{}`,
+	})
+	doc, err := NewExporter(client).Export("Proj")
+	require.NoError(err)
+
+	var out bytes.Buffer
+	require.NoError(doc.Encode(&out, ExportFormatYAML))
+	decoded, err := DecodeProjectExport(&out, ExportFormatYAML)
+	require.NoError(err)
+
+	target, fake := newRecordingServer(t, map[string]string{"/httpAuth/app/rest/projects/Proj/parameters": `{}`})
+	_, err = NewImporter(target).Import(decoded, ImportOptions{})
+	require.NoError(err)
+
+	var params []*Parameter
+	for _, r := range fake.requests {
+		if r.Method == "PUT" && strings.HasPrefix(r.Path, "/httpAuth/app/rest/projects/Proj/parameters/") {
+			var param Parameter
+			require.NoError(json.Unmarshal([]byte(r.Body), &param))
+			params = append(params, &param)
+		}
+	}
+	require.Len(params, 2)
+
+	require.Equal("TOKEN", params[0].Name)
+	require.True(params[0].IsPassword())
+	require.Equal(ParameterDisplayHidden, params[0].Spec.Display)
+
+	require.Equal("stage", params[1].Name)
+	require.Equal("prod", params[1].Value)
+	require.Equal(ParameterSpecSelect, params[1].Spec.Type)
+	require.Equal("Stage", params[1].Spec.Label)
+	require.Equal([]ParameterSpecOption{{Value: "dev"}, {Label: "Production", Value: "prod"}}, params[1].Spec.Options)
+}
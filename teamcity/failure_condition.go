@@ -0,0 +1,164 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// FailureCondition is a build feature failing builds when a condition is met, such as a text logged or a metric change.
+// The conditions common to all build configurations, such as a non-zero exit code, are FailureConditionOptions settings instead.
+type FailureCondition interface {
+	BuildFeature
+}
+
+// failureConditionTypes are the build feature types read by the FailureConditionService
+var failureConditionTypes = []string{"BuildFailureOnMetric", "BuildFailureOnMessage"}
+
+// FailureConditionOptions represents the common failure conditions of a build configuration, stored as its settings
+type FailureConditionOptions struct {
+	// FailOnExitCode fails the build if a build step exits with a non-zero code
+	FailOnExitCode bool `prop:"shouldFailBuildOnBadExitCode,default=true" force:""`
+	// FailOnTestFailure fails the build if at least one test failed
+	FailOnTestFailure bool `prop:"shouldFailBuildIfTestsFailed,default=true" force:""`
+	// FailOnErrorMessage fails the build if an error message is logged by the build runner
+	FailOnErrorMessage bool `prop:"shouldFailBuildOnAnyErrorMessage,default=false" force:""`
+	// FailOnOOMOrCrash fails the build if the build process runs out of memory or crashes
+	FailOnOOMOrCrash bool `prop:"shouldFailBuildOnOOMEOrCrash,default=true" force:""`
+	// ExecutionTimeout fails builds running longer, in whole minutes. Builds have no timeout if zero.
+	ExecutionTimeout time.Duration `prop:"executionTimeoutMin,unit=m,default=0"`
+}
+
+// NewFailureConditionOptionsWithDefaults returns the failure conditions of a new build configuration, as presented in the TeamCity UI
+func NewFailureConditionOptionsWithDefaults() *FailureConditionOptions {
+	return &FailureConditionOptions{
+		FailOnExitCode:    true,
+		FailOnTestFailure: true,
+		FailOnOOMOrCrash:  true,
+	}
+}
+
+// FailureConditionService provides operations for managing the failure conditions of a buildType
+type FailureConditionService struct {
+	BuildTypeID string
+	httpClient  *http.Client
+	base        *sling.Sling
+	restHelper  *restHelper
+	settings    *restHelper
+}
+
+func newFailureConditionService(buildTypeID string, c *http.Client, base *sling.Sling) *FailureConditionService {
+	locator := LocatorID(buildTypeID)
+	features := base.New().Path(fmt.Sprintf("buildTypes/%s/features/", locator))
+	return &FailureConditionService{
+		BuildTypeID: buildTypeID,
+		httpClient:  c,
+		base:        features,
+		restHelper:  newRestHelper(c, features),
+		settings:    newRestHelper(c, base.New().Path(fmt.Sprintf("buildTypes/%s/settings/", locator))),
+	}
+}
+
+// Create adds a new failure condition to the build type
+func (s *FailureConditionService) Create(fc FailureCondition) (FailureCondition, error) {
+	if fc == nil {
+		return nil, fmt.Errorf("fc can't be nil")
+	}
+	var out FailureCondition
+	if err := s.restHelper.postCustom("", fc, &out, "failure condition", s.failureConditionReadingFunc); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetByID returns a failure condition by its id
+func (s *FailureConditionService) GetByID(id string) (FailureCondition, error) {
+	var out FailureCondition
+	if err := s.restHelper.getCustom(id, &out, "failure condition", s.failureConditionReadingFunc); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAll returns the failure conditions of the build type. Other build features are skipped.
+func (s *FailureConditionService) GetAll() ([]FailureCondition, error) {
+	var features Features
+	if err := s.restHelper.get("", &features, "failure conditions"); err != nil {
+		return nil, err
+	}
+
+	var out []FailureCondition
+	for _, item := range features.Items {
+		if !containsString(failureConditionTypes, item.Type) {
+			continue
+		}
+		dt, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var fc FailureCondition
+		if err := s.failureConditionReadingFunc(dt, &fc); err != nil {
+			return nil, err
+		}
+		out = append(out, fc)
+	}
+	return out, nil
+}
+
+// Delete removes a failure condition from the build configuration by its id
+func (s *FailureConditionService) Delete(id string) error {
+	return s.restHelper.delete(id, "failure condition")
+}
+
+// GetOptions returns the common failure conditions of the build type
+func (s *FailureConditionService) GetOptions() (*FailureConditionOptions, error) {
+	var props Properties
+	if err := s.settings.get("", &props, "build type settings"); err != nil {
+		return nil, err
+	}
+	var out FailureConditionOptions
+	if err := UnmarshalProperties(&props, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateOptions changes the common failure conditions of the build type.
+// It does a remote call for each setting, and returns the error of the first failure without updating the rest.
+func (s *FailureConditionService) UpdateOptions(opt *FailureConditionOptions) error {
+	if opt == nil {
+		return fmt.Errorf("opt can't be nil")
+	}
+	if opt.ExecutionTimeout < 0 {
+		return fmt.Errorf("ExecutionTimeout must not be negative")
+	}
+	if opt.ExecutionTimeout%time.Minute != 0 {
+		return fmt.Errorf("ExecutionTimeout must be a whole number of minutes, got %s", opt.ExecutionTimeout)
+	}
+	props, err := MarshalProperties(opt)
+	if err != nil {
+		return err
+	}
+	for _, item := range props.Items {
+		if _, err := s.settings.putTextPlain(item.Name, item.Value, "build type setting "+item.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *FailureConditionService) failureConditionReadingFunc(dt []byte, out interface{}) error {
+	feature, err := decodeBuildFeature(s.BuildTypeID, dt)
+	if err != nil {
+		return err
+	}
+	if !containsString(failureConditionTypes, feature.Type()) {
+		return fmt.Errorf("build feature '%s' of type '%s' is not a failure condition", feature.ID(), feature.Type())
+	}
+	var fc FailureCondition = feature
+	replaceValue(out, &fc)
+	return nil
+}
@@ -0,0 +1,165 @@
+package teamcity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FailureMetric is a build metric compared by a FailureConditionOnMetric
+type FailureMetric = string
+
+const (
+	// FailureMetricArtifactsSize is the total size of the build artifacts, in bytes
+	FailureMetricArtifactsSize FailureMetric = "buildArtifactsSize"
+	// FailureMetricBuildDuration is the build duration, in seconds
+	FailureMetricBuildDuration FailureMetric = "buildDurationNetTime"
+	// FailureMetricTestCount is the number of tests
+	FailureMetricTestCount FailureMetric = "buildTestCount"
+	// FailureMetricFailedTestCount is the number of failed tests
+	FailureMetricFailedTestCount FailureMetric = "buildFailedTestCount"
+	// FailureMetricIgnoredTestCount is the number of ignored tests
+	FailureMetricIgnoredTestCount FailureMetric = "buildIgnoredTestCount"
+	// FailureMetricLineCoverage is the percentage of lines covered by tests
+	FailureMetricLineCoverage FailureMetric = "CodeCoverageL"
+	// FailureMetricInspectionErrors is the number of code inspection errors
+	FailureMetricInspectionErrors FailureMetric = "InspectionStatsE"
+)
+
+// FailureMetricComparison is how a FailureConditionOnMetric compares a metric to its threshold
+type FailureMetricComparison = string
+
+const (
+	// FailureMetricMore fails the build if the metric is more than the threshold
+	FailureMetricMore FailureMetricComparison = "more"
+	// FailureMetricLess fails the build if the metric is less than the threshold
+	FailureMetricLess FailureMetricComparison = "less"
+	// FailureMetricDiff fails the build if the metric differs from the threshold
+	FailureMetricDiff FailureMetricComparison = "diff"
+)
+
+// FailureMetricUnits is the unit of the threshold of a FailureConditionOnMetric
+type FailureMetricUnits = string
+
+const (
+	// FailureMetricUnitsDefault compares the metric to the threshold as a value, or as a change by this value if compared to a build
+	FailureMetricUnitsDefault FailureMetricUnits = "metricUnitsDefault"
+	// FailureMetricUnitsPercents compares the metric change by this percentage of the value of the build compared to
+	FailureMetricUnitsPercents FailureMetricUnits = "metricUnitsPercents"
+)
+
+// FailureMetricAnchor is the build a FailureConditionOnMetric compares a metric to
+type FailureMetricAnchor = string
+
+const (
+	// FailureMetricAnchorLastSuccessful compares to the last successful build
+	FailureMetricAnchorLastSuccessful FailureMetricAnchor = "lastSuccessful"
+	// FailureMetricAnchorLastPinned compares to the last pinned build
+	FailureMetricAnchorLastPinned FailureMetricAnchor = "lastPinned"
+	// FailureMetricAnchorLastFinished compares to the last finished build
+	FailureMetricAnchorLastFinished FailureMetricAnchor = "lastFinished"
+	// FailureMetricAnchorBuildNumber compares to the build with number BuildNumber
+	FailureMetricAnchorBuildNumber FailureMetricAnchor = "buildNumber"
+	// FailureMetricAnchorBuildTag compares to the last build tagged with BuildTag
+	FailureMetricAnchorBuildTag FailureMetricAnchor = "buildTag"
+)
+
+// FailureConditionOnMetricOptions represents the settings of a failure condition on a metric change
+type FailureConditionOnMetricOptions struct {
+	// Metric is the metric compared
+	Metric FailureMetric `prop:"metricKey"`
+	// Comparison is how the metric is compared to Threshold
+	Comparison FailureMetricComparison `prop:"moreOrLess"`
+	// Threshold is the value or change the metric is compared to, such as "20", or a parameter reference such as "%max.size%"
+	Threshold string `prop:"metricThreshold"`
+	// Units is the unit of Threshold. Defaults to FailureMetricUnitsDefault.
+	Units FailureMetricUnits `prop:"metricUnits,default=metricUnitsDefault"`
+	// Anchor is the build the metric change is compared with. The metric is compared to Threshold as a constant value if empty.
+	Anchor FailureMetricAnchor `prop:"anchorBuild"`
+	// BuildNumber is the number of the build compared to, required if Anchor is FailureMetricAnchorBuildNumber
+	BuildNumber string `prop:"buildNumber"`
+	// BuildTag is the tag of the build compared to, required if Anchor is FailureMetricAnchorBuildTag
+	BuildTag string `prop:"buildTag"`
+	// StopBuild stops the build as soon as the condition is met
+	StopBuild bool `prop:"stopBuildOnFailure"`
+}
+
+// FailureConditionOnMetric represents a failure condition failing builds when a metric, such as the artifacts size, changes. Implements FailureCondition interface
+type FailureConditionOnMetric struct {
+	buildFeatureBase
+	Options FailureConditionOnMetricOptions
+}
+
+// NewFailureConditionOnMetric returns a failure condition on a metric change with the given options, after validating them
+func NewFailureConditionOnMetric(opt FailureConditionOnMetricOptions) (*FailureConditionOnMetric, error) {
+	if opt.Metric == "" {
+		return nil, fmt.Errorf("Metric is required")
+	}
+	if !containsString([]string{FailureMetricMore, FailureMetricLess, FailureMetricDiff}, opt.Comparison) {
+		return nil, fmt.Errorf("invalid Comparison '%s'", opt.Comparison)
+	}
+	if opt.Threshold == "" {
+		return nil, fmt.Errorf("Threshold is required")
+	}
+	if !strings.Contains(opt.Threshold, "%") {
+		if n, err := strconv.ParseFloat(opt.Threshold, 64); err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid Threshold '%s', must be a non-negative number or a parameter reference", opt.Threshold)
+		}
+	}
+	if opt.Units == "" {
+		opt.Units = FailureMetricUnitsDefault
+	}
+	if opt.Units != FailureMetricUnitsDefault && opt.Units != FailureMetricUnitsPercents {
+		return nil, fmt.Errorf("invalid Units '%s'", opt.Units)
+	}
+	switch opt.Anchor {
+	case "":
+		if opt.Units == FailureMetricUnitsPercents {
+			return nil, fmt.Errorf("Anchor is required for Units '%s'", opt.Units)
+		}
+	case FailureMetricAnchorLastSuccessful, FailureMetricAnchorLastPinned, FailureMetricAnchorLastFinished:
+	case FailureMetricAnchorBuildNumber:
+		if opt.BuildNumber == "" {
+			return nil, fmt.Errorf("BuildNumber is required for Anchor '%s'", opt.Anchor)
+		}
+	case FailureMetricAnchorBuildTag:
+		if opt.BuildTag == "" {
+			return nil, fmt.Errorf("BuildTag is required for Anchor '%s'", opt.Anchor)
+		}
+	default:
+		return nil, fmt.Errorf("invalid Anchor '%s'", opt.Anchor)
+	}
+	return &FailureConditionOnMetric{Options: opt}, nil
+}
+
+// Type returns the "BuildFailureOnMetric", the keyed-type for this failure condition instance
+func (f *FailureConditionOnMetric) Type() string {
+	return "BuildFailureOnMetric"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FailureConditionOnMetric) Properties() *Properties {
+	props := serializeToProperties(&f.Options)
+	props.AddOrReplaceValue("withBuildAnchor", fmt.Sprint(f.Options.Anchor != ""))
+	return props
+}
+
+// MarshalJSON implements JSON serialization for FailureConditionOnMetric
+func (f *FailureConditionOnMetric) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FailureConditionOnMetric
+func (f *FailureConditionOnMetric) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	if err := UnmarshalProperties(props, &f.Options); err != nil {
+		return err
+	}
+	if v, ok := props.GetOk("withBuildAnchor"); ok && v == "false" {
+		f.Options.Anchor = ""
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package teamcity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureConditionOnMetric_Validation(t *testing.T) {
+	cases := []struct {
+		opt      FailureConditionOnMetricOptions
+		expected string
+	}{
+		{FailureConditionOnMetricOptions{}, "Metric is required"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: "equal"}, "invalid Comparison 'equal'"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: FailureMetricLess}, "Threshold is required"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: FailureMetricLess, Threshold: "-1"}, "invalid Threshold '-1', must be a non-negative number or a parameter reference"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: FailureMetricLess, Threshold: "20", Units: FailureMetricUnitsPercents}, "Anchor is required for Units 'metricUnitsPercents'"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: FailureMetricLess, Threshold: "20", Anchor: FailureMetricAnchorBuildTag}, "BuildTag is required for Anchor 'buildTag'"},
+		{FailureConditionOnMetricOptions{Metric: FailureMetricArtifactsSize, Comparison: FailureMetricLess, Threshold: "20", Anchor: "lastStarted"}, "invalid Anchor 'lastStarted'"},
+	}
+	for _, c := range cases {
+		_, err := NewFailureConditionOnMetric(c.opt)
+		assert.EqualError(t, err, c.expected)
+	}
+}
+
+func TestFailureConditionOnMetric_Properties(t *testing.T) {
+	sut, err := NewFailureConditionOnMetric(FailureConditionOnMetricOptions{
+		Metric:     FailureMetricArtifactsSize,
+		Comparison: FailureMetricLess,
+		Threshold:  "20",
+		Units:      FailureMetricUnitsPercents,
+		Anchor:     FailureMetricAnchorLastSuccessful,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"metricKey":       "buildArtifactsSize",
+		"moreOrLess":      "less",
+		"metricThreshold": "20",
+		"metricUnits":     "metricUnitsPercents",
+		"anchorBuild":     "lastSuccessful",
+		"withBuildAnchor": "true",
+	}, sut.Properties().Map())
+}
+
+func TestFailureConditionOnMetric_AcceptsParameterReferences(t *testing.T) {
+	sut, err := NewFailureConditionOnMetric(FailureConditionOnMetricOptions{
+		Metric:     FailureMetricArtifactsSize,
+		Comparison: FailureMetricMore,
+		Threshold:  "%max.size%",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "%max.size%", sut.Properties().Map()["metricThreshold"])
+}
+
+func TestFailureConditionOnText_Validation(t *testing.T) {
+	_, err := NewFailureConditionOnText(FailureConditionOnTextOptions{})
+	assert.EqualError(t, err, "Pattern is required")
+	_, err = NewFailureConditionOnText(FailureConditionOnTextOptions{Match: "glob", Pattern: "*"})
+	assert.EqualError(t, err, "invalid Match 'glob'")
+
+	sut, err := NewFailureConditionOnText(FailureConditionOnTextOptions{Pattern: "FATAL"})
+	require.NoError(t, err)
+	assert.Equal(t, FailureTextContains, sut.Options.Match)
+}
+
+func TestFailureConditionService(t *testing.T) {
+	require := require.New(t)
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3AProject_Build/features": `{"count":3,"feature":[
+			{"id":"swabra","type":"swabra","properties":{"property":[{"name":"swabra.enabled","value":"swabra.before.build"}]}},
+			{"id":"BUILD_EXT_1","type":"BuildFailureOnMessage","properties":{"property":[
+				{"name":"buildFailureOnMessage.conditionText","value":"^FATAL"},
+				{"name":"buildFailureOnMessage.conditionType","value":"regexp"},
+				{"name":"buildFailureOnMessage.stopBuildOnFailure","value":"true"}]}},
+			{"id":"BUILD_EXT_2","type":"BuildFailureOnMetric","properties":{"property":[
+				{"name":"metricKey","value":"buildTestCount"},
+				{"name":"moreOrLess","value":"less"},
+				{"name":"metricThreshold","value":"%min.tests%"},
+				{"name":"withBuildAnchor","value":"false"}]}}]}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProject_Build/features/swabra": `{"id":"swabra","type":"swabra","properties":{"property":[{"name":"swabra.enabled","value":"swabra.before.build"}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProject_Build/settings":        `{"count":2,"property":[{"name":"shouldFailBuildIfTestsFailed","value":"false"},{"name":"executionTimeoutMin","value":"30"}]}`,
+	})
+	sut := client.FailureConditionService("Project_Build")
+
+	actual, err := sut.GetAll()
+	require.NoError(err)
+	require.Len(actual, 2)
+	require.IsType(&FailureConditionOnText{}, actual[0])
+	assert.Equal(t, FailureConditionOnTextOptions{Match: FailureTextRegexp, Pattern: "^FATAL", StopBuild: true}, actual[0].(*FailureConditionOnText).Options)
+	require.IsType(&FailureConditionOnMetric{}, actual[1])
+	assert.Equal(t, "Project_Build", actual[1].BuildTypeID())
+	assert.Equal(t, FailureConditionOnMetricOptions{
+		Metric:     FailureMetricTestCount,
+		Comparison: FailureMetricLess,
+		Threshold:  "%min.tests%",
+		Units:      FailureMetricUnitsDefault,
+	}, actual[1].(*FailureConditionOnMetric).Options)
+
+	_, err = sut.GetByID("swabra")
+	assert.EqualError(t, err, "build feature 'swabra' of type 'swabra' is not a failure condition")
+
+	opt, err := sut.GetOptions()
+	require.NoError(err)
+	assert.Equal(t, &FailureConditionOptions{
+		FailOnExitCode:   true,
+		FailOnOOMOrCrash: true,
+		ExecutionTimeout: 30 * time.Minute,
+	}, opt)
+
+	opt.FailOnErrorMessage = true
+	require.NoError(sut.UpdateOptions(opt))
+	updated := make(map[string]string)
+	for _, r := range fake.requests {
+		updated[r.Path] = r.Body
+	}
+	settings := "/httpAuth/app/rest/buildTypes/id%3AProject_Build/settings/"
+	assert.Equal(t, map[string]string{
+		settings + "shouldFailBuildOnBadExitCode":     "true",
+		settings + "shouldFailBuildIfTestsFailed":     "false",
+		settings + "shouldFailBuildOnAnyErrorMessage": "true",
+		settings + "shouldFailBuildOnOOMEOrCrash":     "true",
+		settings + "executionTimeoutMin":              "30",
+	}, updated)
+}
+
+func TestFailureConditionService_UpdateOptionsRejectsPartialMinutes(t *testing.T) {
+	client, fake := newRecordingServer(t, nil)
+	sut := client.FailureConditionService("Project_Build")
+
+	opt := NewFailureConditionOptionsWithDefaults()
+	opt.ExecutionTimeout = 30 * time.Second
+	assert.EqualError(t, sut.UpdateOptions(opt), "ExecutionTimeout must be a whole number of minutes, got 30s")
+	assert.Empty(t, fake.requests)
+}
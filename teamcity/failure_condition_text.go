@@ -0,0 +1,72 @@
+package teamcity
+
+import "fmt"
+
+// FailureTextMatch is how a FailureConditionOnText matches the build log messages
+type FailureTextMatch = string
+
+const (
+	// FailureTextContains matches the messages containing the pattern
+	FailureTextContains FailureTextMatch = "contains"
+	// FailureTextRegexp matches the messages with the pattern as a Java regular expression
+	FailureTextRegexp FailureTextMatch = "regexp"
+)
+
+// FailureConditionOnTextOptions represents the settings of a failure condition on a text logged
+type FailureConditionOnTextOptions struct {
+	// Match is how Pattern is matched. Defaults to FailureTextContains.
+	Match FailureTextMatch `prop:"buildFailureOnMessage.conditionType,default=contains"`
+	// Pattern is the text, or regular expression, matched against the build log messages
+	Pattern string `prop:"buildFailureOnMessage.conditionText"`
+	// Reverse fails the build if no message matches the pattern, instead of if any message does
+	Reverse bool `prop:"buildFailureOnMessage.messageInverse"`
+	// FailureMessage is the build problem reported when the condition is met
+	FailureMessage string `prop:"buildFailureOnMessage.outputText"`
+	// StopBuild stops the build as soon as the condition is met
+	StopBuild bool `prop:"buildFailureOnMessage.stopBuildOnFailure"`
+}
+
+// FailureConditionOnText represents a failure condition failing builds when a text is logged. Implements FailureCondition interface
+type FailureConditionOnText struct {
+	buildFeatureBase
+	Options FailureConditionOnTextOptions
+}
+
+// NewFailureConditionOnText returns a failure condition on a text logged with the given options, after validating them.
+// Regular expressions use the Java syntax and are validated by the server.
+func NewFailureConditionOnText(opt FailureConditionOnTextOptions) (*FailureConditionOnText, error) {
+	if opt.Match == "" {
+		opt.Match = FailureTextContains
+	}
+	if opt.Match != FailureTextContains && opt.Match != FailureTextRegexp {
+		return nil, fmt.Errorf("invalid Match '%s'", opt.Match)
+	}
+	if opt.Pattern == "" {
+		return nil, fmt.Errorf("Pattern is required")
+	}
+	return &FailureConditionOnText{Options: opt}, nil
+}
+
+// Type returns the "BuildFailureOnMessage", the keyed-type for this failure condition instance
+func (f *FailureConditionOnText) Type() string {
+	return "BuildFailureOnMessage"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FailureConditionOnText) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FailureConditionOnText
+func (f *FailureConditionOnText) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FailureConditionOnText
+func (f *FailureConditionOnText) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
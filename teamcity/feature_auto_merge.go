@@ -0,0 +1,90 @@
+package teamcity
+
+import "fmt"
+
+// AutoMergePolicy is how a FeatureAutoMerge merges branches
+type AutoMergePolicy = string
+
+const (
+	// AutoMergeFastForward fast-forwards the destination branch when possible, and creates a merge commit otherwise
+	AutoMergeFastForward AutoMergePolicy = "fastForward"
+	// AutoMergeAlwaysCreateMergeCommit always creates a merge commit
+	AutoMergeAlwaysCreateMergeCommit AutoMergePolicy = "alwaysCreateMergeCommit"
+)
+
+// AutoMergeCondition is the build status required by a FeatureAutoMerge to merge
+type AutoMergeCondition = string
+
+const (
+	// AutoMergeIfSuccessful merges if the build is successful
+	AutoMergeIfSuccessful AutoMergeCondition = "successful"
+	// AutoMergeIfNoNewTestsFailed merges if the build has no new failed tests, even if it fails
+	AutoMergeIfNoNewTestsFailed AutoMergeCondition = "noNewTestsFailed"
+)
+
+// FeatureAutoMergeOptions represents the settings of an automatic merge build feature
+type FeatureAutoMergeOptions struct {
+	// BranchFilter selects the branches to merge, with +:/-: branch filter rules such as "+:pull/*". Required.
+	BranchFilter []string `prop:"teamcity.automerge.srcBranchFilter" separator:"\n"`
+	// DestinationBranch is the logical name of the branch to merge into, such as "<default>". Required.
+	DestinationBranch string `prop:"teamcity.automerge.dstBranch"`
+	// CommitMessage is the message of merge commits. Parameter references are supported. Uses the TeamCity default message if empty.
+	CommitMessage string `prop:"teamcity.automerge.message"`
+	// MergePolicy defaults to AutoMergeFastForward
+	MergePolicy AutoMergePolicy `prop:"teamcity.merge.policy"`
+	// Condition defaults to AutoMergeIfSuccessful
+	Condition AutoMergeCondition `prop:"teamcity.automerge.buildStatusCondition"`
+}
+
+// FeatureAutoMerge represents an automatic merge build feature, merging branches into a destination branch when their builds satisfy a condition. Implements BuildFeature interface
+type FeatureAutoMerge struct {
+	buildFeatureBase
+	Options FeatureAutoMergeOptions
+}
+
+// NewFeatureAutoMerge returns an automatic merge build feature with the given options, after validating them
+func NewFeatureAutoMerge(opt FeatureAutoMergeOptions) (*FeatureAutoMerge, error) {
+	if len(opt.BranchFilter) == 0 {
+		return nil, fmt.Errorf("BranchFilter is required")
+	}
+	if opt.DestinationBranch == "" {
+		return nil, fmt.Errorf("DestinationBranch is required")
+	}
+	if opt.MergePolicy == "" {
+		opt.MergePolicy = AutoMergeFastForward
+	}
+	if opt.MergePolicy != AutoMergeFastForward && opt.MergePolicy != AutoMergeAlwaysCreateMergeCommit {
+		return nil, fmt.Errorf("invalid MergePolicy '%s'", opt.MergePolicy)
+	}
+	if opt.Condition == "" {
+		opt.Condition = AutoMergeIfSuccessful
+	}
+	if opt.Condition != AutoMergeIfSuccessful && opt.Condition != AutoMergeIfNoNewTestsFailed {
+		return nil, fmt.Errorf("invalid Condition '%s'", opt.Condition)
+	}
+	return &FeatureAutoMerge{Options: opt}, nil
+}
+
+// Type returns the "AutoMergeFeature", the keyed-type for this build feature instance
+func (f *FeatureAutoMerge) Type() string {
+	return "AutoMergeFeature"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeatureAutoMerge) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FeatureAutoMerge
+func (f *FeatureAutoMerge) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeatureAutoMerge
+func (f *FeatureAutoMerge) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
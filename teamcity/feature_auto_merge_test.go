@@ -0,0 +1,43 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureAutoMerge_Validation(t *testing.T) {
+	_, err := teamcity.NewFeatureAutoMerge(teamcity.FeatureAutoMergeOptions{DestinationBranch: "main"})
+	assert.EqualError(t, err, "BranchFilter is required")
+
+	_, err = teamcity.NewFeatureAutoMerge(teamcity.FeatureAutoMergeOptions{BranchFilter: []string{"+:pull/*"}})
+	assert.EqualError(t, err, "DestinationBranch is required")
+
+	_, err = teamcity.NewFeatureAutoMerge(teamcity.FeatureAutoMergeOptions{BranchFilter: []string{"+:pull/*"}, DestinationBranch: "main", MergePolicy: "rebase"})
+	assert.EqualError(t, err, "invalid MergePolicy 'rebase'")
+}
+
+func TestFeatureAutoMerge_Serialization(t *testing.T) {
+	require := require.New(t)
+	sut, err := teamcity.NewFeatureAutoMerge(teamcity.FeatureAutoMergeOptions{
+		BranchFilter:      []string{"+:pull/*"},
+		DestinationBranch: "<default>",
+		CommitMessage:     "Merge %teamcity.build.branch%",
+	})
+	require.NoError(err)
+	assert.Equal(t, map[string]string{
+		"teamcity.automerge.srcBranchFilter":      "+:pull/*",
+		"teamcity.automerge.dstBranch":            "<default>",
+		"teamcity.automerge.message":              "Merge %teamcity.build.branch%",
+		"teamcity.merge.policy":                   "fastForward",
+		"teamcity.automerge.buildStatusCondition": "successful",
+	}, sut.Properties().Map())
+
+	dt, err := sut.MarshalJSON()
+	require.NoError(err)
+	var actual teamcity.FeatureAutoMerge
+	require.NoError(actual.UnmarshalJSON(dt))
+	assert.Equal(t, sut.Options, actual.Options)
+}
@@ -4,6 +4,41 @@ import (
 	"encoding/json"
 )
 
+const (
+	publisherIDGithub          = "githubStatusPublisher"
+	publisherIDGitlab          = "gitlabStatusPublisher"
+	publisherIDBitbucketCloud  = "bitbucketCloudPublisher"
+	publisherIDBitbucketServer = "atlassianStashPublisher"
+	publisherIDGerrit          = "gerritStatusPublisher"
+	publisherIDAzureDevOps     = "tfs"
+	publisherIDSpace           = "spaceStatusPublisher"
+)
+
+// statusPublisherOptionsReaders maps the publisherId property of a commit status publisher to the function reading its options
+var statusPublisherOptionsReaders = map[string]func(*Properties) (FeatureCommitStatusPublisherOptions, error){
+	publisherIDGithub: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherGithubOptionsFromProperties(p)
+	},
+	publisherIDGitlab: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherGitlabOptionsFromProperties(p)
+	},
+	publisherIDBitbucketCloud: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherBitbucketCloudOptionsFromProperties(p)
+	},
+	publisherIDBitbucketServer: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherBitbucketServerOptionsFromProperties(p)
+	},
+	publisherIDGerrit: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherGerritOptionsFromProperties(p)
+	},
+	publisherIDAzureDevOps: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherAzureDevOpsOptionsFromProperties(p)
+	},
+	publisherIDSpace: func(p *Properties) (FeatureCommitStatusPublisherOptions, error) {
+		return CommitStatusPublisherSpaceOptionsFromProperties(p)
+	},
+}
+
 // FeatureCommitStatusPublisherOptions represents options needed to create a commit status publisher build feature
 type FeatureCommitStatusPublisherOptions interface {
 	Properties() *Properties
@@ -101,15 +136,36 @@ func (f *FeatureCommitStatusPublisher) UnmarshalJSON(data []byte) error {
 	f.disabled = *disabled
 	f.properties = NewProperties(aux.Properties.Items...)
 
-	opt, err := CommitStatusPublisherGithubOptionsFromProperties(f.properties)
-	if err != nil {
-		return err
-	}
-
 	if v, ok := f.properties.GetOk("vcsRootId"); ok {
 		f.vcsRootID = v
 	}
+
+	// Publishers not known to this package are kept as raw properties only
+	publisherID, _ := f.properties.GetOk("publisherId")
+	read, ok := statusPublisherOptionsReaders[publisherID]
+	if !ok {
+		return nil
+	}
+	opt, err := read(f.properties)
+	if err != nil {
+		return err
+	}
 	f.Options = opt
 
 	return nil
 }
+
+func newFeatureCommitStatusPublisher(opt FeatureCommitStatusPublisherOptions, vcsRootID string) *FeatureCommitStatusPublisher {
+	return &FeatureCommitStatusPublisher{
+		Options:    opt,
+		vcsRootID:  vcsRootID,
+		properties: opt.Properties(),
+	}
+}
+
+// statusPublisherProperties returns the properties of the publisher options opt, tagged as described in MarshalProperties
+func statusPublisherProperties(publisherID string, opt interface{}) *Properties {
+	props := serializeToProperties(opt)
+	props.AddOrReplaceValue("publisherId", publisherID)
+	return props
+}
@@ -59,3 +59,45 @@ func TestFeatureCommitPublisher_UnmarshallProperties_Github(t *testing.T) {
 	assert.Equal("password", actualOpt.AuthenticationType)
 	assert.Equal("me@me.com", actualOpt.Username)
 }
+
+func TestFeatureCommitPublisher_UnmarshallProperties_Publishers(t *testing.T) {
+	cases := []struct {
+		properties string
+		expected   teamcity.FeatureCommitStatusPublisherOptions
+	}{
+		{
+			`{"name":"publisherId","value":"gitlabStatusPublisher"},{"name":"gitlabApiUrl","value":"https://gitlab.com/api/v4"},{"name":"secure:gitlabAccessToken"}`,
+			&teamcity.StatusPublisherGitlabOptions{APIURL: "https://gitlab.com/api/v4"},
+		},
+		{
+			`{"name":"publisherId","value":"bitbucketCloudPublisher"},{"name":"bitbucketUsername","value":"bob"}`,
+			&teamcity.StatusPublisherBitbucketCloudOptions{Username: "bob"},
+		},
+		{
+			`{"name":"publisherId","value":"atlassianStashPublisher"},{"name":"stashBaseUrl","value":"https://bitbucket.example.com"},{"name":"stashUsername","value":"bob"}`,
+			&teamcity.StatusPublisherBitbucketServerOptions{URL: "https://bitbucket.example.com", Username: "bob"},
+		},
+		{
+			`{"name":"publisherId","value":"gerritStatusPublisher"},{"name":"gerritServer","value":"gerrit.example.com"},{"name":"successVote","value":"+1"}`,
+			&teamcity.StatusPublisherGerritOptions{Server: "gerrit.example.com", SuccessVote: "+1"},
+		},
+		{
+			`{"name":"publisherId","value":"tfs"},{"name":"tfsAuthType","value":"token"},{"name":"tfsPublishPullRequests","value":"true"}`,
+			&teamcity.StatusPublisherAzureDevOpsOptions{PublishPullRequests: true},
+		},
+		{
+			`{"name":"publisherId","value":"spaceStatusPublisher"},{"name":"spaceProjectKey","value":"BACKEND"}`,
+			&teamcity.StatusPublisherSpaceOptions{ProjectKey: "BACKEND"},
+		},
+		{
+			`{"name":"publisherId","value":"upsourcePublisher"}`,
+			nil,
+		},
+	}
+	for _, c := range cases {
+		var actual teamcity.FeatureCommitStatusPublisher
+		err := actual.UnmarshalJSON([]byte(`{"id":"BUILD_EXT_1","type":"commit-status-publisher","properties":{"property":[` + c.properties + `]}}`))
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, actual.Options, c.properties)
+	}
+}
@@ -0,0 +1,84 @@
+package teamcity
+
+import "fmt"
+
+// FileContentReplacerMode is how the pattern of a FeatureFileContentReplacer is matched
+type FileContentReplacerMode = string
+
+const (
+	// FileContentReplacerRegex matches the pattern as a Java regular expression
+	FileContentReplacerRegex FileContentReplacerMode = "REGEX"
+	// FileContentReplacerFixedStrings matches the pattern as a fixed string
+	FileContentReplacerFixedStrings FileContentReplacerMode = "FIXED_STRINGS"
+	// FileContentReplacerRegexMixed matches the pattern as a regular expression, and uses the replacement as a fixed string
+	FileContentReplacerRegexMixed FileContentReplacerMode = "REGEX_MIXED"
+)
+
+// FeatureFileContentReplacerOptions represents the settings of a file content replacer build feature
+type FeatureFileContentReplacerOptions struct {
+	// Files are the paths of the processed files, relative to the checkout directory. Wildcards and +:/-: rules are supported. Required.
+	Files []string `prop:"teamcity.file.content.replacer.wildcards" separator:"\n"`
+	// Pattern is the text to replace. Required.
+	Pattern string `prop:"teamcity.file.content.replacer.pattern"`
+	// Replacement is the text replacing the pattern, such as "%build.number%". Can be empty, to remove matches.
+	Replacement string `prop:"teamcity.file.content.replacer.replacement" force:""`
+	// Mode is how Pattern is matched. Defaults to FileContentReplacerRegex.
+	Mode FileContentReplacerMode `prop:"teamcity.file.content.replacer.regexMode"`
+	// CaseSensitive makes the pattern match case-sensitive
+	CaseSensitive bool `prop:"teamcity.file.content.replacer.pattern.case.sensitive" force:""`
+	// Encoding is the encoding of the files, such as "UTF-8". Detected automatically if empty.
+	Encoding string `prop:"teamcity.file.content.replacer.file.encoding.custom"`
+}
+
+// FeatureFileContentReplacer represents a file content replacer build feature, replacing text in files before the build, such as to update version numbers. Implements BuildFeature interface
+type FeatureFileContentReplacer struct {
+	buildFeatureBase
+	Options FeatureFileContentReplacerOptions
+}
+
+// NewFeatureFileContentReplacer returns a file content replacer build feature with the given options, after validating them
+func NewFeatureFileContentReplacer(opt FeatureFileContentReplacerOptions) (*FeatureFileContentReplacer, error) {
+	if len(opt.Files) == 0 {
+		return nil, fmt.Errorf("Files is required")
+	}
+	if opt.Pattern == "" {
+		return nil, fmt.Errorf("Pattern is required")
+	}
+	if opt.Mode == "" {
+		opt.Mode = FileContentReplacerRegex
+	}
+	if opt.Mode != FileContentReplacerRegex && opt.Mode != FileContentReplacerFixedStrings && opt.Mode != FileContentReplacerRegexMixed {
+		return nil, fmt.Errorf("invalid Mode '%s'", opt.Mode)
+	}
+	return &FeatureFileContentReplacer{Options: opt}, nil
+}
+
+// Type returns the "JetBrains.FileContentReplacer", the keyed-type for this build feature instance
+func (f *FeatureFileContentReplacer) Type() string {
+	return "JetBrains.FileContentReplacer"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeatureFileContentReplacer) Properties() *Properties {
+	props := serializeToProperties(&f.Options)
+	if f.Options.Encoding == "" {
+		props.AddOrReplaceValue("teamcity.file.content.replacer.file.encoding", "autodetect")
+	} else {
+		props.AddOrReplaceValue("teamcity.file.content.replacer.file.encoding", "custom")
+	}
+	return props
+}
+
+// MarshalJSON implements JSON serialization for FeatureFileContentReplacer
+func (f *FeatureFileContentReplacer) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeatureFileContentReplacer
+func (f *FeatureFileContentReplacer) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
@@ -0,0 +1,44 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFileContentReplacer_Validation(t *testing.T) {
+	_, err := teamcity.NewFeatureFileContentReplacer(teamcity.FeatureFileContentReplacerOptions{Pattern: "1.0.0"})
+	assert.EqualError(t, err, "Files is required")
+
+	_, err = teamcity.NewFeatureFileContentReplacer(teamcity.FeatureFileContentReplacerOptions{Files: []string{"version.txt"}})
+	assert.EqualError(t, err, "Pattern is required")
+
+	_, err = teamcity.NewFeatureFileContentReplacer(teamcity.FeatureFileContentReplacerOptions{Files: []string{"version.txt"}, Pattern: "1.0.0", Mode: "GLOB"})
+	assert.EqualError(t, err, "invalid Mode 'GLOB'")
+}
+
+func TestFeatureFileContentReplacer_Serialization(t *testing.T) {
+	require := require.New(t)
+	sut, err := teamcity.NewFeatureFileContentReplacer(teamcity.FeatureFileContentReplacerOptions{
+		Files:       []string{"**/AssemblyInfo.cs"},
+		Pattern:     `AssemblyVersion\("[^"]*"\)`,
+		Replacement: `AssemblyVersion("%build.number%")`,
+	})
+	require.NoError(err)
+	assert.Equal(t, map[string]string{
+		"teamcity.file.content.replacer.wildcards":              "**/AssemblyInfo.cs",
+		"teamcity.file.content.replacer.pattern":                `AssemblyVersion\("[^"]*"\)`,
+		"teamcity.file.content.replacer.replacement":            `AssemblyVersion("%build.number%")`,
+		"teamcity.file.content.replacer.regexMode":              "REGEX",
+		"teamcity.file.content.replacer.pattern.case.sensitive": "false",
+		"teamcity.file.content.replacer.file.encoding":          "autodetect",
+	}, sut.Properties().Map())
+
+	dt, err := sut.MarshalJSON()
+	require.NoError(err)
+	var actual teamcity.FeatureFileContentReplacer
+	require.NoError(actual.UnmarshalJSON(dt))
+	assert.Equal(t, sut.Options, actual.Options)
+}
@@ -0,0 +1,32 @@
+package teamcity
+
+// FeaturePerformanceMonitor represents a performance monitor build feature, collecting CPU, disk and memory usage statistics of the agent during builds. Implements BuildFeature interface
+type FeaturePerformanceMonitor struct {
+	buildFeatureBase
+}
+
+// NewFeaturePerformanceMonitor returns a performance monitor build feature. It has no settings.
+func NewFeaturePerformanceMonitor() *FeaturePerformanceMonitor {
+	return &FeaturePerformanceMonitor{}
+}
+
+// Type returns the "perfmon", the keyed-type for this build feature instance
+func (f *FeaturePerformanceMonitor) Type() string {
+	return "perfmon"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeaturePerformanceMonitor) Properties() *Properties {
+	return NewPropertiesEmpty()
+}
+
+// MarshalJSON implements JSON serialization for FeaturePerformanceMonitor
+func (f *FeaturePerformanceMonitor) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeaturePerformanceMonitor
+func (f *FeaturePerformanceMonitor) UnmarshalJSON(data []byte) error {
+	_, err := f.unmarshalFeature(f.Type(), data)
+	return err
+}
@@ -0,0 +1,19 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeaturePerformanceMonitor_Serialization(t *testing.T) {
+	var actual teamcity.FeaturePerformanceMonitor
+	require.NoError(t, actual.UnmarshalJSON([]byte(`{"id":"perfmon","type":"perfmon","disabled":true}`)))
+	assert.Equal(t, "perfmon", actual.ID())
+	assert.True(t, actual.Disabled())
+
+	err := actual.UnmarshalJSON([]byte(`{"id":"swabra","type":"swabra"}`))
+	assert.EqualError(t, err, "invalid type swabra trying to deserialize into perfmon build feature")
+}
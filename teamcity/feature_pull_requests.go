@@ -0,0 +1,147 @@
+package teamcity
+
+import "fmt"
+
+// PullRequestsProvider is the VCS hosting service monitored by a FeaturePullRequests
+type PullRequestsProvider = string
+
+const (
+	PullRequestsProviderGitHub          PullRequestsProvider = "github"
+	PullRequestsProviderGitLab          PullRequestsProvider = "gitlab"
+	PullRequestsProviderBitbucketServer PullRequestsProvider = "bitbucketServer"
+	PullRequestsProviderAzureDevOps     PullRequestsProvider = "azureDevOps"
+)
+
+// PullRequestsAuthType is how a FeaturePullRequests authenticates to its provider
+type PullRequestsAuthType = string
+
+const (
+	// PullRequestsAuthToken uses an access token. Supported by all providers.
+	PullRequestsAuthToken PullRequestsAuthType = "token"
+	// PullRequestsAuthPassword uses a username and password. Only supported by Bitbucket Server.
+	PullRequestsAuthPassword PullRequestsAuthType = "password"
+	// PullRequestsAuthVcsRoot uses the credentials of the VCS root. Only supported by GitHub and Bitbucket Server.
+	PullRequestsAuthVcsRoot PullRequestsAuthType = "vcsRoot"
+)
+
+// PullRequestsAuthorRole filters the pull requests of a GitHub FeaturePullRequests by the role of their author
+type PullRequestsAuthorRole = string
+
+const (
+	PullRequestsAuthorMember               PullRequestsAuthorRole = "MEMBER"
+	PullRequestsAuthorMemberOrCollaborator PullRequestsAuthorRole = "MEMBER_OR_COLLABORATOR"
+	PullRequestsAuthorEverybody            PullRequestsAuthorRole = "EVERYBODY"
+)
+
+var pullRequestsAuthTypes = map[PullRequestsProvider][]PullRequestsAuthType{
+	PullRequestsProviderGitHub:          {PullRequestsAuthToken, PullRequestsAuthVcsRoot},
+	PullRequestsProviderGitLab:          {PullRequestsAuthToken},
+	PullRequestsProviderBitbucketServer: {PullRequestsAuthPassword, PullRequestsAuthVcsRoot},
+	PullRequestsProviderAzureDevOps:     {PullRequestsAuthToken},
+}
+
+// FeaturePullRequestsOptions represents the settings of a pull requests build feature
+type FeaturePullRequestsOptions struct {
+	// Provider is the VCS hosting service. Required.
+	Provider PullRequestsProvider `prop:"providerType"`
+	// AuthenticationType defaults to PullRequestsAuthToken, or PullRequestsAuthPassword for Bitbucket Server
+	AuthenticationType PullRequestsAuthType `prop:"authenticationType"`
+	// AccessToken is required if AuthenticationType is PullRequestsAuthToken. It is never read back from the server.
+	AccessToken string `prop:"secure:accessToken"`
+	// Username is required if AuthenticationType is PullRequestsAuthPassword
+	Username string `prop:"username"`
+	// Password is required if AuthenticationType is PullRequestsAuthPassword. It is never read back from the server.
+	Password string `prop:"secure:password"`
+	// ServerURL is the URL of the server for GitHub Enterprise, self-hosted GitLab and Bitbucket Server, where it is required
+	ServerURL string `prop:"serverUrl"`
+	// ProjectURL is the URL of the Azure DevOps project, required for Azure DevOps
+	ProjectURL string `prop:"projectUrl"`
+	// VcsRootID restricts the feature to a VCS root of the build configuration. Applies to all VCS roots if empty.
+	VcsRootID string `prop:"vcsRootId"`
+	// FilterAuthorRole only monitors the pull requests of authors with the given role. Only supported by GitHub.
+	FilterAuthorRole PullRequestsAuthorRole `prop:"filterAuthorRole"`
+	// FilterTargetBranch only monitors the pull requests targeting branches matching the given +:/-: branch filter rules
+	FilterTargetBranch []string `prop:"filterTargetBranch,omitempty" separator:"\n"`
+	// IgnoreDrafts does not monitor draft pull requests. Only supported by GitHub.
+	IgnoreDrafts bool `prop:"ignoreDrafts"`
+}
+
+// FeaturePullRequests represents a pull requests build feature, making the builds of pull requests branches aware of their pull request. Implements BuildFeature interface
+type FeaturePullRequests struct {
+	buildFeatureBase
+	Options FeaturePullRequestsOptions
+}
+
+// NewFeaturePullRequests returns a pull requests build feature with the given options, after validating them
+func NewFeaturePullRequests(opt FeaturePullRequestsOptions) (*FeaturePullRequests, error) {
+	if opt.Provider == "" {
+		return nil, fmt.Errorf("Provider is required")
+	}
+	authTypes, ok := pullRequestsAuthTypes[opt.Provider]
+	if !ok {
+		return nil, fmt.Errorf("invalid Provider '%s'", opt.Provider)
+	}
+
+	if opt.AuthenticationType == "" {
+		opt.AuthenticationType = authTypes[0]
+	}
+	if !containsString(authTypes, opt.AuthenticationType) {
+		return nil, fmt.Errorf("invalid AuthenticationType '%s' for provider '%s'", opt.AuthenticationType, opt.Provider)
+	}
+	switch opt.AuthenticationType {
+	case PullRequestsAuthToken:
+		if opt.AccessToken == "" {
+			return nil, fmt.Errorf("AccessToken required for auth type 'token'")
+		}
+	case PullRequestsAuthPassword:
+		if opt.Username == "" || opt.Password == "" {
+			return nil, fmt.Errorf("Username/Password required for auth type 'password'")
+		}
+	}
+
+	switch opt.Provider {
+	case PullRequestsProviderBitbucketServer:
+		if opt.ServerURL == "" {
+			return nil, fmt.Errorf("ServerURL is required for provider '%s'", opt.Provider)
+		}
+	case PullRequestsProviderAzureDevOps:
+		if opt.ProjectURL == "" {
+			return nil, fmt.Errorf("ProjectURL is required for provider '%s'", opt.Provider)
+		}
+	}
+
+	if opt.Provider != PullRequestsProviderGitHub && (opt.FilterAuthorRole != "" || opt.IgnoreDrafts) {
+		return nil, fmt.Errorf("FilterAuthorRole and IgnoreDrafts are only supported for provider '%s'", PullRequestsProviderGitHub)
+	}
+	switch opt.FilterAuthorRole {
+	case "", PullRequestsAuthorMember, PullRequestsAuthorMemberOrCollaborator, PullRequestsAuthorEverybody:
+	default:
+		return nil, fmt.Errorf("invalid FilterAuthorRole '%s'", opt.FilterAuthorRole)
+	}
+
+	return &FeaturePullRequests{Options: opt}, nil
+}
+
+// Type returns the "pullRequests", the keyed-type for this build feature instance
+func (f *FeaturePullRequests) Type() string {
+	return "pullRequests"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeaturePullRequests) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FeaturePullRequests
+func (f *FeaturePullRequests) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeaturePullRequests
+func (f *FeaturePullRequests) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
@@ -0,0 +1,81 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeaturePullRequests_Validation(t *testing.T) {
+	cases := []struct {
+		opt      FeaturePullRequestsOptions
+		expected string
+	}{
+		{FeaturePullRequestsOptions{}, "Provider is required"},
+		{FeaturePullRequestsOptions{Provider: "gitea"}, "invalid Provider 'gitea'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderGitHub}, "AccessToken required for auth type 'token'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderGitLab, AuthenticationType: PullRequestsAuthVcsRoot}, "invalid AuthenticationType 'vcsRoot' for provider 'gitlab'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderBitbucketServer, Username: "ci"}, "Username/Password required for auth type 'password'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderBitbucketServer, AuthenticationType: PullRequestsAuthVcsRoot}, "ServerURL is required for provider 'bitbucketServer'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderAzureDevOps, AccessToken: "token"}, "ProjectURL is required for provider 'azureDevOps'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderGitLab, AccessToken: "token", IgnoreDrafts: true}, "FilterAuthorRole and IgnoreDrafts are only supported for provider 'github'"},
+		{FeaturePullRequestsOptions{Provider: PullRequestsProviderGitHub, AccessToken: "token", FilterAuthorRole: "OWNER"}, "invalid FilterAuthorRole 'OWNER'"},
+	}
+	for _, c := range cases {
+		_, err := NewFeaturePullRequests(c.opt)
+		assert.EqualError(t, err, c.expected)
+	}
+}
+
+func TestFeaturePullRequests_Serialization(t *testing.T) {
+	require := require.New(t)
+	sut, err := NewFeaturePullRequests(FeaturePullRequestsOptions{
+		Provider:           PullRequestsProviderGitHub,
+		AccessToken:        "token",
+		FilterAuthorRole:   PullRequestsAuthorMember,
+		FilterTargetBranch: []string{"+:refs/heads/main"},
+	})
+	require.NoError(err)
+	assert.Equal(t, map[string]string{
+		"providerType":       "github",
+		"authenticationType": "token",
+		"secure:accessToken": "token",
+		"filterAuthorRole":   "MEMBER",
+		"filterTargetBranch": "+:refs/heads/main",
+	}, sut.Properties().Map())
+}
+
+func TestBuildFeatureService_ReadsPullRequestsAndAutoMerge(t *testing.T) {
+	require := require.New(t)
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3AProject_Build/features/BUILD_EXT_1": `{"id":"BUILD_EXT_1","type":"pullRequests","properties":{"property":[
+			{"name":"providerType","value":"azureDevOps"},
+			{"name":"authenticationType","value":"token"},
+			{"name":"projectUrl","value":"https://dev.azure.com/org/project"}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProject_Build/features/BUILD_EXT_2": `{"id":"BUILD_EXT_2","type":"AutoMergeFeature","properties":{"property":[
+			{"name":"teamcity.automerge.srcBranchFilter","value":"+:pull/*"},
+			{"name":"teamcity.automerge.dstBranch","value":"<default>"},
+			{"name":"teamcity.merge.policy","value":"alwaysCreateMergeCommit"},
+			{"name":"teamcity.automerge.buildStatusCondition","value":"successful"}]}}`,
+	})
+	service := client.BuildFeatureService("Project_Build")
+
+	actual, err := service.GetByID("BUILD_EXT_1")
+	require.NoError(err)
+	require.IsType(&FeaturePullRequests{}, actual)
+	pr := actual.(*FeaturePullRequests)
+	assert.Equal(t, "Project_Build", pr.BuildTypeID())
+	assert.Equal(t, PullRequestsProviderAzureDevOps, pr.Options.Provider)
+	assert.Equal(t, "https://dev.azure.com/org/project", pr.Options.ProjectURL)
+
+	actual, err = service.GetByID("BUILD_EXT_2")
+	require.NoError(err)
+	require.IsType(&FeatureAutoMerge{}, actual)
+	assert.Equal(t, FeatureAutoMergeOptions{
+		BranchFilter:      []string{"+:pull/*"},
+		DestinationBranch: "<default>",
+		MergePolicy:       AutoMergeAlwaysCreateMergeCommit,
+		Condition:         AutoMergeIfSuccessful,
+	}, actual.(*FeatureAutoMerge).Options)
+}
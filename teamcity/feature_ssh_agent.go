@@ -0,0 +1,49 @@
+package teamcity
+
+import "fmt"
+
+// FeatureSSHAgentOptions represents the settings of a SSH agent build feature
+type FeatureSSHAgentOptions struct {
+	// KeyName is the name of a SSH key uploaded to the project or one of its parents. Required.
+	KeyName string `prop:"teamcitySshKey"`
+	// Passphrase of the key, if any. It is never read back from the server.
+	Passphrase string `prop:"secure:passphrase"`
+}
+
+// FeatureSSHAgent represents a SSH agent build feature, loading an uploaded SSH key into a ssh-agent for the duration of the build. Implements BuildFeature interface
+type FeatureSSHAgent struct {
+	buildFeatureBase
+	Options FeatureSSHAgentOptions
+}
+
+// NewFeatureSSHAgent returns a SSH agent build feature with the given options, after validating them
+func NewFeatureSSHAgent(opt FeatureSSHAgentOptions) (*FeatureSSHAgent, error) {
+	if opt.KeyName == "" {
+		return nil, fmt.Errorf("KeyName is required")
+	}
+	return &FeatureSSHAgent{Options: opt}, nil
+}
+
+// Type returns the "ssh-agent-build-feature", the keyed-type for this build feature instance
+func (f *FeatureSSHAgent) Type() string {
+	return "ssh-agent-build-feature"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeatureSSHAgent) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FeatureSSHAgent
+func (f *FeatureSSHAgent) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeatureSSHAgent
+func (f *FeatureSSHAgent) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
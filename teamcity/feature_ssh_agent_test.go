@@ -0,0 +1,24 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureSSHAgent_Serialization(t *testing.T) {
+	require := require.New(t)
+	_, err := teamcity.NewFeatureSSHAgent(teamcity.FeatureSSHAgentOptions{})
+	assert.EqualError(t, err, "KeyName is required")
+
+	sut, err := teamcity.NewFeatureSSHAgent(teamcity.FeatureSSHAgentOptions{KeyName: "deploy", Passphrase: "secret"})
+	require.NoError(err)
+	assert.Equal(t, map[string]string{"teamcitySshKey": "deploy", "secure:passphrase": "secret"}, sut.Properties().Map())
+
+	var actual teamcity.FeatureSSHAgent
+	require.NoError(actual.UnmarshalJSON([]byte(`{"id":"ssh-agent-build-feature","type":"ssh-agent-build-feature","properties":{"count":1,"property":[{"name":"teamcitySshKey","value":"deploy"}]}}`)))
+	assert.Equal(t, "deploy", actual.Options.KeyName)
+	assert.False(t, actual.Disabled())
+}
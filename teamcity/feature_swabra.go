@@ -0,0 +1,81 @@
+package teamcity
+
+import "fmt"
+
+// SwabraCleanup is when a FeatureSwabra cleans the files created by a build
+type SwabraCleanup = string
+
+const (
+	// SwabraCleanupBeforeBuild cleans the files of the previous build before the next build starts
+	SwabraCleanupBeforeBuild SwabraCleanup = "swabra.before.build"
+	// SwabraCleanupAfterBuild cleans the files as soon as the build finishes
+	SwabraCleanupAfterBuild SwabraCleanup = "swabra.after.build"
+)
+
+// SwabraLockingProcesses is what a FeatureSwabra does with the processes locking the checkout directory
+type SwabraLockingProcesses = string
+
+const (
+	// SwabraLockingProcessesReport reports the processes locking the checkout directory
+	SwabraLockingProcessesReport SwabraLockingProcesses = "report"
+	// SwabraLockingProcessesKill reports and kills the processes locking the checkout directory
+	SwabraLockingProcessesKill SwabraLockingProcesses = "kill"
+)
+
+// FeatureSwabraOptions represents the settings of a build files cleaner (Swabra) build feature
+type FeatureSwabraOptions struct {
+	// Cleanup is when files are cleaned. No files are cleaned if empty.
+	Cleanup SwabraCleanup `prop:"swabra.enabled"`
+	// ForceCleanCheckout forces a clean checkout if the checkout directory cannot be restored to its state before the build
+	ForceCleanCheckout bool `prop:"swabra.strict"`
+	// LockingProcesses is what to do with the processes locking the checkout directory, on Windows agents. Disabled if empty.
+	LockingProcesses SwabraLockingProcesses `prop:"swabra.processes"`
+	// Verbose enables verbose logging of the cleaned files
+	Verbose bool `prop:"swabra.verbose"`
+	// Paths restrict the monitored paths, relative to the checkout directory, with +:/-: rules
+	Paths []string `prop:"swabra.rules,omitempty" separator:"\n"`
+}
+
+// FeatureSwabra represents a build files cleaner (Swabra) build feature, removing the files created by builds from the checkout directory. Implements BuildFeature interface
+type FeatureSwabra struct {
+	buildFeatureBase
+	Options FeatureSwabraOptions
+}
+
+// NewFeatureSwabra returns a build files cleaner build feature with the given options, after validating them
+func NewFeatureSwabra(opt FeatureSwabraOptions) (*FeatureSwabra, error) {
+	if opt.Cleanup != "" && opt.Cleanup != SwabraCleanupBeforeBuild && opt.Cleanup != SwabraCleanupAfterBuild {
+		return nil, fmt.Errorf("invalid Cleanup '%s'", opt.Cleanup)
+	}
+	if opt.LockingProcesses != "" && opt.LockingProcesses != SwabraLockingProcessesReport && opt.LockingProcesses != SwabraLockingProcessesKill {
+		return nil, fmt.Errorf("invalid LockingProcesses '%s'", opt.LockingProcesses)
+	}
+	if opt.Cleanup == "" && opt.LockingProcesses == "" {
+		return nil, fmt.Errorf("Cleanup or LockingProcesses is required")
+	}
+	return &FeatureSwabra{Options: opt}, nil
+}
+
+// Type returns the "swabra", the keyed-type for this build feature instance
+func (f *FeatureSwabra) Type() string {
+	return "swabra"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeatureSwabra) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FeatureSwabra
+func (f *FeatureSwabra) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeatureSwabra
+func (f *FeatureSwabra) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
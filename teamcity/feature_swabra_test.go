@@ -0,0 +1,41 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureSwabra_Validation(t *testing.T) {
+	_, err := teamcity.NewFeatureSwabra(teamcity.FeatureSwabraOptions{})
+	assert.EqualError(t, err, "Cleanup or LockingProcesses is required")
+
+	_, err = teamcity.NewFeatureSwabra(teamcity.FeatureSwabraOptions{Cleanup: "always"})
+	assert.EqualError(t, err, "invalid Cleanup 'always'")
+
+	_, err = teamcity.NewFeatureSwabra(teamcity.FeatureSwabraOptions{LockingProcesses: "ignore"})
+	assert.EqualError(t, err, "invalid LockingProcesses 'ignore'")
+}
+
+func TestFeatureSwabra_Serialization(t *testing.T) {
+	require := require.New(t)
+	sut, err := teamcity.NewFeatureSwabra(teamcity.FeatureSwabraOptions{
+		Cleanup:            teamcity.SwabraCleanupAfterBuild,
+		ForceCleanCheckout: true,
+		LockingProcesses:   teamcity.SwabraLockingProcessesKill,
+	})
+	require.NoError(err)
+	assert.Equal(t, map[string]string{
+		"swabra.enabled":   "swabra.after.build",
+		"swabra.strict":    "true",
+		"swabra.processes": "kill",
+	}, sut.Properties().Map())
+
+	dt, err := sut.MarshalJSON()
+	require.NoError(err)
+	var actual teamcity.FeatureSwabra
+	require.NoError(actual.UnmarshalJSON(dt))
+	assert.Equal(t, sut.Options, actual.Options)
+}
@@ -0,0 +1,84 @@
+package teamcity
+
+import "fmt"
+
+// XMLReportType is the format of the reports processed by a FeatureXMLReportProcessing
+type XMLReportType = string
+
+const (
+	XMLReportJUnit      XMLReportType = "junit"
+	XMLReportNUnit      XMLReportType = "nunit"
+	XMLReportSurefire   XMLReportType = "surefire"
+	XMLReportTestNG     XMLReportType = "testng"
+	XMLReportMSTest     XMLReportType = "trx"
+	XMLReportVSTest     XMLReportType = "vstest"
+	XMLReportGoogleTest XMLReportType = "gtest"
+	XMLReportCTest      XMLReportType = "ctest"
+	XMLReportCheckstyle XMLReportType = "checkstyle"
+	XMLReportFindBugs   XMLReportType = "findBugs"
+	XMLReportPMD        XMLReportType = "pmd"
+)
+
+var xmlReportTypes = []XMLReportType{
+	XMLReportJUnit, XMLReportNUnit, XMLReportSurefire, XMLReportTestNG, XMLReportMSTest, XMLReportVSTest,
+	XMLReportGoogleTest, XMLReportCTest, XMLReportCheckstyle, XMLReportFindBugs, XMLReportPMD,
+}
+
+// FeatureXMLReportProcessingOptions represents the settings of a XML report processing build feature
+type FeatureXMLReportProcessingOptions struct {
+	// ReportType is the format of the reports. Required.
+	ReportType XMLReportType `prop:"xmlReportParsing.reportType"`
+	// Paths are the paths of the reports, relative to the checkout directory. Wildcards and +:/-: rules are supported. Required.
+	Paths []string `prop:"xmlReportParsing.reportDirs" separator:"\n"`
+	// Verbose enables verbose logging of the report processing
+	Verbose bool `prop:"xmlReportParsing.verboseOutput"`
+	// MaxErrors fails the build if the inspection reports have more errors, such as "0", or a parameter reference such as "%max.errors%".
+	// There is no limit if empty. Only applies to code inspection report types.
+	MaxErrors string `prop:"xmlReportParsing.max.errors"`
+	// MaxWarnings fails the build if the inspection reports have more warnings, in the same format as MaxErrors. Only applies to code inspection report types.
+	MaxWarnings string `prop:"xmlReportParsing.max.warnings"`
+}
+
+// FeatureXMLReportProcessing represents a XML report processing build feature, importing test and inspection reports produced by build tools. Implements BuildFeature interface
+type FeatureXMLReportProcessing struct {
+	buildFeatureBase
+	Options FeatureXMLReportProcessingOptions
+}
+
+// NewFeatureXMLReportProcessing returns a XML report processing build feature with the given options, after validating them
+func NewFeatureXMLReportProcessing(opt FeatureXMLReportProcessingOptions) (*FeatureXMLReportProcessing, error) {
+	if opt.ReportType == "" {
+		return nil, fmt.Errorf("ReportType is required")
+	}
+	if !containsString(xmlReportTypes, opt.ReportType) {
+		return nil, fmt.Errorf("invalid ReportType '%s'", opt.ReportType)
+	}
+	if len(opt.Paths) == 0 {
+		return nil, fmt.Errorf("Paths is required")
+	}
+	return &FeatureXMLReportProcessing{Options: opt}, nil
+}
+
+// Type returns the "xml-report-plugin", the keyed-type for this build feature instance
+func (f *FeatureXMLReportProcessing) Type() string {
+	return "xml-report-plugin"
+}
+
+// Properties returns a *Properties instance representing a serializable collection to be used.
+func (f *FeatureXMLReportProcessing) Properties() *Properties {
+	return serializeToProperties(&f.Options)
+}
+
+// MarshalJSON implements JSON serialization for FeatureXMLReportProcessing
+func (f *FeatureXMLReportProcessing) MarshalJSON() ([]byte, error) {
+	return f.marshalFeature(f.Type(), f.Properties())
+}
+
+// UnmarshalJSON implements JSON deserialization for FeatureXMLReportProcessing
+func (f *FeatureXMLReportProcessing) UnmarshalJSON(data []byte) error {
+	props, err := f.unmarshalFeature(f.Type(), data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalProperties(props, &f.Options)
+}
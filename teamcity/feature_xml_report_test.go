@@ -0,0 +1,56 @@
+package teamcity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureXMLReportProcessing_Validation(t *testing.T) {
+	_, err := teamcity.NewFeatureXMLReportProcessing(teamcity.FeatureXMLReportProcessingOptions{Paths: []string{"reports/*.xml"}})
+	assert.EqualError(t, err, "ReportType is required")
+
+	_, err = teamcity.NewFeatureXMLReportProcessing(teamcity.FeatureXMLReportProcessingOptions{ReportType: "xunit", Paths: []string{"reports/*.xml"}})
+	assert.EqualError(t, err, "invalid ReportType 'xunit'")
+
+	_, err = teamcity.NewFeatureXMLReportProcessing(teamcity.FeatureXMLReportProcessingOptions{ReportType: teamcity.XMLReportJUnit})
+	assert.EqualError(t, err, "Paths is required")
+}
+
+func TestFeatureXMLReportProcessing_Serialization(t *testing.T) {
+	require := require.New(t)
+	sut, err := teamcity.NewFeatureXMLReportProcessing(teamcity.FeatureXMLReportProcessingOptions{
+		ReportType: teamcity.XMLReportCheckstyle,
+		Paths:      []string{"+:build/checkstyle/*.xml", "-:build/checkstyle/generated.xml"},
+		Verbose:    true,
+		MaxErrors:  "0",
+	})
+	require.NoError(err)
+	sut.SetID("BUILD_EXT_1")
+
+	dt, err := json.Marshal(sut)
+	require.NoError(err)
+	assert.JSONEq(t, `{"id":"BUILD_EXT_1","type":"xml-report-plugin","disabled":false,"inherited":false,"properties":{"count":4,"property":[
+		{"name":"xmlReportParsing.reportType","value":"checkstyle"},
+		{"name":"xmlReportParsing.reportDirs","value":"+:build/checkstyle/*.xml\n-:build/checkstyle/generated.xml"},
+		{"name":"xmlReportParsing.verboseOutput","value":"true"},
+		{"name":"xmlReportParsing.max.errors","value":"0"}]}}`, string(dt))
+
+	var actual teamcity.FeatureXMLReportProcessing
+	require.NoError(actual.UnmarshalJSON(dt))
+	assert.Equal(t, "BUILD_EXT_1", actual.ID())
+	assert.Equal(t, sut.Options, actual.Options)
+}
+
+func TestFeatureXMLReportProcessing_ReadsParameterReferences(t *testing.T) {
+	var actual teamcity.FeatureXMLReportProcessing
+	require.NoError(t, actual.UnmarshalJSON([]byte(`{"id":"BUILD_EXT_1","type":"xml-report-plugin","properties":{"property":[
+		{"name":"xmlReportParsing.reportType","value":"pmd"},
+		{"name":"xmlReportParsing.reportDirs","value":"build/pmd.xml"},
+		{"name":"xmlReportParsing.max.errors","value":"%max.errors%"}]}}`)))
+	assert.Equal(t, "%max.errors%", actual.Options.MaxErrors)
+	assert.Equal(t, "", actual.Options.MaxWarnings)
+}
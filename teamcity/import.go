@@ -0,0 +1,360 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ImportOptions controls where and under which IDs a ProjectExport is recreated
+type ImportOptions struct {
+	// ParentProjectID is the project to import into. Defaults to the root project.
+	ParentProjectID string
+	// MapID returns the ID to use on the target server for a project, VCS root, template or build configuration of the document.
+	// References to these resources, including %dep.<id>.<name>% parameter references, are rewritten accordingly. Defaults to keeping IDs unchanged.
+	MapID func(id string) string
+}
+
+// Importer recreates a ProjectExport on a server
+type Importer struct {
+	client     *Client
+	restHelper *restHelper
+}
+
+// NewImporter returns an Importer writing to the server of the given client
+func NewImporter(client *Client) *Importer {
+	return &Importer{
+		client:     client,
+		restHelper: newRestHelper(client.HTTPClient, client.commonBase.New()),
+	}
+}
+
+// Import creates the projects, VCS roots, templates and build configurations of the document, returning the IDs they were created with, keyed by their IDs in the document.
+// Resources are created in dependency order, with snapshot and artifact dependencies added last, once all build configurations exist.
+// Import is not atomic: if it fails, the resources created so far are left in place.
+func (i *Importer) Import(doc *ProjectExport, opt ImportOptions) (map[string]string, error) {
+	if doc == nil || doc.Project == nil {
+		return nil, errors.New("export has no project")
+	}
+
+	mapID := opt.MapID
+	if mapID == nil {
+		mapID = func(id string) string { return id }
+	}
+	ids := make(map[string]string)
+	collectExportIDs(doc.Project, mapID, ids)
+
+	im := &projectImport{Importer: i, ids: ids}
+	projects := im.flatten(doc.Project, rootProjectID(opt.ParentProjectID), nil)
+
+	// Parameters are checked first, so that an invalid specification does not leave a partial import
+	for _, p := range projects {
+		if err := im.checkParameters(p.ExportedProject); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range projects {
+		if err := im.createProject(p); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range projects {
+		for _, root := range p.VcsRoots {
+			if err := im.createVcsRoot(p.ID, root); err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range p.Features {
+			if err := im.createProjectFeature(p.ID, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, p := range projects {
+		for _, bt := range p.Templates {
+			if err := im.createBuildType(p.ID, bt, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, p := range projects {
+		for _, bt := range p.BuildTypes {
+			if err := im.createBuildType(p.ID, bt, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, p := range projects {
+		for _, bt := range append(append([]*ExportedBuildType{}, p.Templates...), p.BuildTypes...) {
+			if err := im.createDependencies(bt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
+}
+
+func collectExportIDs(p *ExportedProject, mapID func(string) string, ids map[string]string) {
+	ids[p.ID] = mapID(p.ID)
+	for _, root := range p.VcsRoots {
+		ids[root.ID] = mapID(root.ID)
+	}
+	for _, bt := range p.Templates {
+		ids[bt.ID] = mapID(bt.ID)
+	}
+	for _, bt := range p.BuildTypes {
+		ids[bt.ID] = mapID(bt.ID)
+	}
+	for _, sub := range p.Projects {
+		collectExportIDs(sub, mapID, ids)
+	}
+}
+
+type projectImport struct {
+	*Importer
+	ids map[string]string
+}
+
+// importedProject is a project of the document along with its parent, so the tree can be created level by level
+type importedProject struct {
+	*ExportedProject
+	parentID string
+}
+
+func (im *projectImport) flatten(p *ExportedProject, parentID string, out []*importedProject) []*importedProject {
+	out = append(out, &importedProject{ExportedProject: p, parentID: parentID})
+	for _, sub := range p.Projects {
+		out = im.flatten(sub, p.ID, out)
+	}
+	return out
+}
+
+// mapID returns the new ID of a resource of the document, or the id itself for resources outside of it
+func (im *projectImport) mapID(id string) string {
+	if v, ok := im.ids[id]; ok {
+		return v
+	}
+	return id
+}
+
+func (im *projectImport) createProject(p *importedProject) error {
+	project, err := NewProject(p.Name, p.Description, im.mapID(p.parentID))
+	if err != nil {
+		return fmt.Errorf("project '%s': %s", p.ID, err)
+	}
+	project.ID = im.mapID(p.ID)
+	if project.Parameters, err = im.parameters(p.Parameters); err != nil {
+		return fmt.Errorf("project '%s': %s", p.ID, err)
+	}
+
+	if _, err := im.client.Projects.Create(project); err != nil {
+		return fmt.Errorf("error creating project '%s': %s", project.ID, err)
+	}
+	return nil
+}
+
+func (im *projectImport) createProjectFeature(projectID string, f *ExportedSetting) error {
+	var out settingItem
+	path := fmt.Sprintf("projects/%s/projectFeatures", LocatorID(im.mapID(projectID)))
+	if err := im.restHelper.post(path, im.settingItem(f, false), &out, "project feature"); err != nil {
+		return fmt.Errorf("error creating feature '%s' of project '%s': %s", f.ID, projectID, err)
+	}
+	return nil
+}
+
+func (im *projectImport) createVcsRoot(projectID string, root *ExportedVcsRoot) error {
+	dt := &vcsRootJSON{
+		ID:                        im.mapID(root.ID),
+		Name:                      root.Name,
+		VcsName:                   root.VcsName,
+		ModificationCheckInterval: root.ModificationCheckInterval,
+		Project:                   &ProjectReference{ID: im.mapID(projectID)},
+		Properties:                im.properties(root.Properties),
+	}
+
+	var out vcsRootJSON
+	if err := im.restHelper.post("vcs-roots", dt, &out, "vcs root"); err != nil {
+		return fmt.Errorf("error creating vcs root '%s': %s", dt.ID, err)
+	}
+	return nil
+}
+
+func (im *projectImport) createBuildType(projectID string, bt *ExportedBuildType, template bool) error {
+	parameters, err := im.parameters(bt.Parameters)
+	if err != nil {
+		return fmt.Errorf("build type '%s': %s", bt.ID, err)
+	}
+	dt := &rawBuildTypeJSON{
+		ID:           im.mapID(bt.ID),
+		Name:         bt.Name,
+		Description:  bt.Description,
+		ProjectID:    im.mapID(projectID),
+		TemplateFlag: NewBool(template),
+		Settings:     im.properties(bt.Settings),
+		Parameters:   parameters,
+		Steps:        &rawSteps{Items: im.settingItems(bt.Steps, false)},
+		Triggers:     &rawTriggers{Items: im.settingItems(bt.Triggers, false)},
+		Features:     &rawFeatures{Items: im.settingItems(bt.Features, false)},
+		AgentRequirements: &rawAgentRequirements{
+			Items: im.settingItems(bt.AgentRequirements, false),
+		},
+	}
+	if len(bt.Templates) > 0 {
+		dt.Templates = &Templates{Count: int32(len(bt.Templates))}
+		for _, t := range bt.Templates {
+			dt.Templates.Items = append(dt.Templates.Items, &BuildTypeReference{ID: im.mapID(t)})
+		}
+	}
+	if len(bt.VcsRoots) > 0 {
+		dt.Entries = &VcsRootEntries{Count: int32(len(bt.VcsRoots))}
+		for _, e := range bt.VcsRoots {
+			ref := &VcsRootReference{ID: im.mapID(e.VcsRootID)}
+			dt.Entries.Items = append(dt.Entries.Items, NewVcsRootEntryWithRules(ref, e.CheckoutRules))
+		}
+	}
+	if template {
+		// TeamCity does not accept a description when creating templates, so it is set afterwards
+		dt.Description = ""
+	}
+
+	var out BuildTypeReference
+	if err := im.restHelper.post("buildTypes", dt, &out, "build type"); err != nil {
+		return fmt.Errorf("error creating build type '%s': %s", dt.ID, err)
+	}
+	if template && bt.Description != "" {
+		path := fmt.Sprintf("buildTypes/%s/description", LocatorID(dt.ID))
+		if _, err := im.restHelper.putTextPlain(path, bt.Description, "build type description"); err != nil {
+			return fmt.Errorf("error setting description of template '%s': %s", dt.ID, err)
+		}
+	}
+	return nil
+}
+
+func (im *projectImport) createDependencies(bt *ExportedBuildType) error {
+	id := LocatorID(im.mapID(bt.ID))
+	for _, dep := range im.settingItems(bt.SnapshotDependencies, true) {
+		var out settingItem
+		if err := im.restHelper.post(fmt.Sprintf("buildTypes/%s/snapshot-dependencies", id), dep, &out, "snapshot dependency"); err != nil {
+			return fmt.Errorf("error adding snapshot dependency on '%s' to '%s': %s", dep.SourceBuildType.ID, bt.ID, err)
+		}
+	}
+	for _, dep := range im.settingItems(bt.ArtifactDependencies, true) {
+		var out settingItem
+		if err := im.restHelper.post(fmt.Sprintf("buildTypes/%s/artifact-dependencies", id), dep, &out, "artifact dependency"); err != nil {
+			return fmt.Errorf("error adding artifact dependency on '%s' to '%s': %s", dep.SourceBuildType.ID, bt.ID, err)
+		}
+	}
+	return nil
+}
+
+func (im *projectImport) settingItems(settings []*ExportedSetting, dependency bool) []*settingItem {
+	out := make([]*settingItem, len(settings))
+	for i, s := range settings {
+		out[i] = im.settingItem(s, dependency)
+	}
+	return out
+}
+
+// settingItem converts an exported setting back to its raw representation, rewriting references to resources of the document
+func (im *projectImport) settingItem(s *ExportedSetting, dependency bool) *settingItem {
+	out := &settingItem{
+		ID:         s.ID,
+		Name:       s.Name,
+		Type:       s.Type,
+		Disabled:   NewBool(s.Disabled),
+		Properties: im.properties(s.Properties),
+	}
+	if s.SourceBuildTypeID != "" {
+		out.SourceBuildType = &BuildTypeReference{ID: im.mapID(s.SourceBuildTypeID)}
+	}
+	if dependency {
+		// The id of a dependency is the id of its source, so let the server assign it
+		out.ID = ""
+	}
+	return out
+}
+
+// idProperties are the properties holding the id of a resource, such as the VCS root of versioned settings, a commit status publisher or a pull requests feature,
+// or the build configuration a finish build trigger depends on
+var idProperties = []string{"rootId", "vcsRootId", "dependsOn"}
+
+// depReference matches the build configuration id of %dep.<id>.<name>% parameter references
+var depReference = regexp.MustCompile(`%dep\.([^%.]+)\.`)
+
+// mapReferences rewrites %dep.<id>.<name>% references to build configurations of the document
+func (im *projectImport) mapReferences(value string) string {
+	return depReference.ReplaceAllStringFunc(value, func(m string) string {
+		id := depReference.FindStringSubmatch(m)[1]
+		return "%dep." + im.mapID(id) + "."
+	})
+}
+
+// properties converts exported properties back to Properties, rewriting references to resources of the document
+func (im *projectImport) properties(m map[string]string) *Properties {
+	out := NewPropertiesEmpty()
+	for _, k := range sortedKeys(m) {
+		out.Add(NewProperty(k, im.mapProperty(k, m[k])))
+	}
+	return out
+}
+
+// mapProperty rewrites the value of a property referring to resources of the document
+func (im *projectImport) mapProperty(name string, value string) string {
+	if containsString(idProperties, name) {
+		return im.mapID(value)
+	}
+	return im.mapReferences(value)
+}
+
+func (im *projectImport) parameters(m map[string]ExportedParameter) (*Parameters, error) {
+	out := NewParametersEmpty()
+	for _, k := range sortedParameterNames(m) {
+		prop := NewProperty(k, im.mapReferences(m[k].Value))
+		if m[k].Spec != "" {
+			prop.Type = &Type{RawValue: m[k].Spec}
+		}
+		param, err := parameterFromProperty(prop)
+		if err != nil {
+			return nil, err
+		}
+		if param.Spec != nil {
+			if err := param.Spec.Validate(); err != nil {
+				return nil, fmt.Errorf("parameter '%s': %s", k, err)
+			}
+		}
+		out.Add(param)
+	}
+	return out, nil
+}
+
+// checkParameters returns an error if a parameter of the project or of its templates and build configurations has an invalid specification
+func (im *projectImport) checkParameters(p *ExportedProject) error {
+	if _, err := im.parameters(p.Parameters); err != nil {
+		return fmt.Errorf("project '%s': %s", p.ID, err)
+	}
+	for _, bt := range append(append([]*ExportedBuildType{}, p.Templates...), p.BuildTypes...) {
+		if _, err := im.parameters(bt.Parameters); err != nil {
+			return fmt.Errorf("build type '%s': %s", bt.ID, err)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedParameterNames(m map[string]ExportedParameter) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,187 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dghubble/sling"
+)
+
+// InvestigationState represents the state of an investigation
+type InvestigationState string
+
+const (
+	// InvestigationStateTaken means someone is investigating the problem
+	InvestigationStateTaken InvestigationState = "TAKEN"
+	// InvestigationStateFixed means the problem was marked as fixed
+	InvestigationStateFixed InvestigationState = "FIXED"
+	// InvestigationStateGivenUp means the assignee gave up on the investigation
+	InvestigationStateGivenUp InvestigationState = "GIVEN_UP"
+)
+
+// Investigation assigns a person to look into failures of a build configuration, tests or build problems
+type Investigation struct {
+	ID         string             `json:"id,omitempty" xml:"id"`
+	State      InvestigationState `json:"state,omitempty" xml:"state"`
+	Href       string             `json:"href,omitempty" xml:"href"`
+	Assignee   *UserReference     `json:"assignee,omitempty"`
+	Assignment *Assignment        `json:"assignment,omitempty"`
+	Scope      *ProblemScope      `json:"scope,omitempty"`
+	Target     *ProblemTarget     `json:"target,omitempty"`
+	Resolution *Resolution        `json:"resolution,omitempty"`
+}
+
+type investigationsJSON struct {
+	Count int32            `json:"count,omitempty" xml:"count"`
+	Href  string           `json:"href,omitempty" xml:"href"`
+	Items []*Investigation `json:"investigation"`
+}
+
+// NewInvestigation returns an Investigation assigned to the user with given username.
+// Comment is optional. Use NewBuildTypeScope/NewProjectScope and NewAnyProblemTarget/NewTestTarget/NewProblemTarget to build scope and target.
+func NewInvestigation(assignee string, comment string, scope *ProblemScope, target *ProblemTarget, resolution *Resolution) (*Investigation, error) {
+	if assignee == "" {
+		return nil, errors.New("assignee is required")
+	}
+	if err := validateProblemScope(scope, target, resolution); err != nil {
+		return nil, err
+	}
+
+	return &Investigation{
+		State:      InvestigationStateTaken,
+		Assignee:   &UserReference{Username: assignee},
+		Assignment: &Assignment{Text: comment},
+		Scope:      scope,
+		Target:     target,
+		Resolution: resolution,
+	}, nil
+}
+
+// InvestigationLocator filters investigations. Zero values are omitted from the locator.
+type InvestigationLocator struct {
+	Assignee    string
+	BuildTypeID string
+	// ProjectID returns investigations affecting the project and its subprojects
+	ProjectID string
+	TestName  string
+	ProblemID string
+	State     InvestigationState
+}
+
+func (l InvestigationLocator) dimensions() locatorDimensions {
+	var d locatorDimensions
+	if l.Assignee != "" {
+		d.add("assignee", fmt.Sprintf("(username:%s)", l.Assignee))
+	}
+	if l.BuildTypeID != "" {
+		d.add("buildType", fmt.Sprintf("(id:%s)", l.BuildTypeID))
+	}
+	if l.ProjectID != "" {
+		d.add("affectedProject", fmt.Sprintf("(id:%s)", l.ProjectID))
+	}
+	if l.TestName != "" {
+		d.add("test", fmt.Sprintf("(name:%s)", locatorValue(l.TestName)))
+	}
+	if l.ProblemID != "" {
+		d.add("problem", fmt.Sprintf("(id:%s)", l.ProblemID))
+	}
+	d.add("state", string(l.State))
+	return d
+}
+
+// String returns the unescaped locator, for instance "assignee:(username:admin),state:TAKEN"
+func (l InvestigationLocator) String() string {
+	return l.dimensions().String()
+}
+
+// InvestigationService has operations for handling investigations
+type InvestigationService struct {
+	sling      *sling.Sling
+	httpClient *http.Client
+	restHelper *restHelper
+}
+
+func newInvestigationService(base *sling.Sling, httpClient *http.Client) *InvestigationService {
+	sling := base.Path("investigations/")
+	return &InvestigationService{
+		sling:      sling,
+		httpClient: httpClient,
+		restHelper: newRestHelper(httpClient, sling),
+	}
+}
+
+func (s *InvestigationService) fields() getFields {
+	return getFields{
+		Fields: "count,href,investigation($long)",
+	}
+}
+
+// Create assigns a new investigation
+func (s *InvestigationService) Create(investigation *Investigation) (*Investigation, error) {
+	if investigation == nil {
+		return nil, errors.New("investigation can't be nil")
+	}
+
+	var created Investigation
+	err := s.restHelper.post("", investigation, &created, "investigation")
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// List returns all investigations matching the locator
+func (s *InvestigationService) List(locator InvestigationLocator) ([]*Investigation, error) {
+	var out investigationsJSON
+	err := s.restHelper.getWithFields(locator.dimensions().query(), s.fields(), &out, "investigations")
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Items, nil
+}
+
+// GetByID returns an investigation by its id
+func (s *InvestigationService) GetByID(id string) (*Investigation, error) {
+	var out Investigation
+	err := s.restHelper.getWithFields(url.PathEscape(id), getFields{Fields: "$long"}, &out, "investigation")
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Update replaces an existing investigation, for instance to assign it to someone else or change its resolution
+func (s *InvestigationService) Update(investigation *Investigation) (*Investigation, error) {
+	if investigation == nil || investigation.ID == "" {
+		return nil, errors.New("investigation with an id is required")
+	}
+
+	var updated Investigation
+	err := s.restHelper.put(url.PathEscape(investigation.ID), investigation, &updated, "investigation")
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Resolve marks the investigation with given id as fixed
+func (s *InvestigationService) Resolve(id string) (*Investigation, error) {
+	current, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	current.State = InvestigationStateFixed
+	return s.Update(current)
+}
+
+// Delete removes an investigation by its id
+func (s *InvestigationService) Delete(id string) error {
+	return s.restHelper.delete(url.PathEscape(id), "investigation")
+}
@@ -0,0 +1,51 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvestigation_Lifecycle(t *testing.T) {
+	ctx := new(BuildTypeContext)
+	ctx.Setup(NewTc("TestInvestigation", t))
+	defer ctx.Teardown()
+	client := ctx.TC.Client
+
+	investigation, err := teamcity.NewInvestigation("admin", "on-call", teamcity.NewBuildTypeScope(ctx.BuildType.ID), teamcity.NewAnyProblemTarget(), teamcity.NewResolutionWhenFixed())
+	require.NoError(t, err)
+
+	created, err := client.Investigations.Create(investigation)
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, teamcity.InvestigationStateTaken, created.State)
+	assert.Equal(t, "admin", created.Assignee.Username)
+
+	actual, err := client.Investigations.List(teamcity.InvestigationLocator{BuildTypeID: ctx.BuildType.ID})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "on-call", actual[0].Assignment.Text)
+	assert.Equal(t, teamcity.ResolutionWhenFixed, actual[0].Resolution.Type)
+
+	resolved, err := client.Investigations.Resolve(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, teamcity.InvestigationStateFixed, resolved.State)
+
+	require.NoError(t, client.Investigations.Delete(created.ID))
+}
+
+func TestInvestigation_Invariants(t *testing.T) {
+	scope := teamcity.NewProjectScope("Project")
+	target := teamcity.NewTestTarget("pkg.TestFoo")
+
+	_, err := teamcity.NewInvestigation("", "", scope, target, teamcity.NewResolutionManually())
+	assert.EqualError(t, err, "assignee is required")
+
+	_, err = teamcity.NewInvestigation("admin", "", nil, target, teamcity.NewResolutionManually())
+	assert.EqualError(t, err, "scope is required")
+
+	_, err = teamcity.NewInvestigation("admin", "", scope, target, &teamcity.Resolution{Type: teamcity.ResolutionAtTime})
+	assert.EqualError(t, err, "resolution time is required for 'atTime' resolution")
+}
@@ -0,0 +1,277 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// LintSeverity is the severity of a LintFinding. Values match SARIF levels.
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityNote    LintSeverity = "note"
+)
+
+// LintLocation identifies the resource a finding is about. Empty fields don't apply.
+type LintLocation struct {
+	ProjectID   string `json:"projectId,omitempty"`
+	BuildTypeID string `json:"buildTypeId,omitempty"`
+	VcsRootID   string `json:"vcsRootId,omitempty"`
+	// SettingID is the id of a step, trigger, feature or dependency
+	SettingID string `json:"settingId,omitempty"`
+	Property  string `json:"property,omitempty"`
+}
+
+// String returns the location as a path, such as "Proj/Proj_Build/RUNNER_1/script.content"
+func (l LintLocation) String() string {
+	var parts []string
+	for _, p := range []string{l.ProjectID, l.BuildTypeID, l.VcsRootID, l.SettingID, l.Property} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// LintFinding is a problem reported by a LintRule
+type LintFinding struct {
+	RuleID   string       `json:"ruleId"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	Location LintLocation `json:"location"`
+}
+
+// LintRule checks the configuration of a project tree. Implement it to add custom rules to a Linter.
+type LintRule interface {
+	// ID is a unique, stable identifier of the rule, such as "unused-vcs-root"
+	ID() string
+	// Description explains what the rule checks
+	Description() string
+	// Severity is the severity of the findings of the rule
+	Severity() LintSeverity
+	// Check returns the findings of the rule. RuleID and Severity of the findings are set by the Linter.
+	Check(ctx *LintContext) []*LintFinding
+}
+
+// LintContext gives rules access to a project tree, with indexes to resolve references within it
+type LintContext struct {
+	// Root is the linted project
+	Root *ExportedProject
+
+	projects   []*ExportedProject
+	parents    map[string]*ExportedProject
+	buildTypes map[string]*ExportedBuildType
+	owners     map[string]*ExportedProject
+}
+
+func newLintContext(root *ExportedProject) *LintContext {
+	ctx := &LintContext{
+		Root:       root,
+		parents:    make(map[string]*ExportedProject),
+		buildTypes: make(map[string]*ExportedBuildType),
+		owners:     make(map[string]*ExportedProject),
+	}
+	var walk func(p *ExportedProject)
+	walk = func(p *ExportedProject) {
+		ctx.projects = append(ctx.projects, p)
+		for _, bt := range append(append([]*ExportedBuildType{}, p.Templates...), p.BuildTypes...) {
+			ctx.buildTypes[bt.ID] = bt
+			ctx.owners[bt.ID] = p
+		}
+		for _, sub := range p.Projects {
+			ctx.parents[sub.ID] = p
+			walk(sub)
+		}
+	}
+	walk(root)
+	return ctx
+}
+
+// Projects returns the projects of the tree, the root first
+func (ctx *LintContext) Projects() []*ExportedProject {
+	return ctx.projects
+}
+
+// BuildType returns the build configuration or template with given id, or nil if it is not part of the tree
+func (ctx *LintContext) BuildType(id string) *ExportedBuildType {
+	return ctx.buildTypes[id]
+}
+
+// Owner returns the project defining the build configuration or template with given id
+func (ctx *LintContext) Owner(buildTypeID string) *ExportedProject {
+	return ctx.owners[buildTypeID]
+}
+
+// Parent returns the parent of the project with given id, or nil for the root of the tree
+func (ctx *LintContext) Parent(projectID string) *ExportedProject {
+	return ctx.parents[projectID]
+}
+
+// EachBuildType calls fn for each build configuration and template of the tree, along with the project defining it
+func (ctx *LintContext) EachBuildType(fn func(p *ExportedProject, bt *ExportedBuildType, template bool)) {
+	for _, p := range ctx.projects {
+		for _, bt := range p.Templates {
+			fn(p, bt, true)
+		}
+		for _, bt := range p.BuildTypes {
+			fn(p, bt, false)
+		}
+	}
+}
+
+// Linter checks project trees against a set of rules
+type Linter struct {
+	rules []LintRule
+}
+
+// NewLinter returns a Linter with the given rules, or with DefaultLintRules if none is given
+func NewLinter(rules ...LintRule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultLintRules()
+	}
+	return &Linter{rules: rules}
+}
+
+// Rules returns the rules of the linter
+func (l *Linter) Rules() []LintRule {
+	return l.rules
+}
+
+// Lint checks the project of the document, such as returned by Exporter.Export or DecodeProjectExport
+func (l *Linter) Lint(doc *ProjectExport) *LintReport {
+	report := &LintReport{Rules: l.rules, Findings: []*LintFinding{}}
+	if doc == nil || doc.Project == nil {
+		return report
+	}
+	ctx := newLintContext(doc.Project)
+	for _, rule := range l.rules {
+		for _, f := range rule.Check(ctx) {
+			f.RuleID = rule.ID()
+			f.Severity = rule.Severity()
+			report.Findings = append(report.Findings, f)
+		}
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Location.String() < report.Findings[j].Location.String()
+	})
+	return report
+}
+
+// LintReport holds the findings of a Linter
+type LintReport struct {
+	Rules    []LintRule
+	Findings []*LintFinding
+}
+
+// HasSeverity returns true if the report has findings of the given severity
+func (r *LintReport) HasSeverity(severity LintSeverity) bool {
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the findings as a JSON object with a "findings" array
+func (r *LintReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Findings []*LintFinding `json:"findings"`
+	}{r.Findings})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver sarifDriver `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level LintSeverity `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     LintSeverity    `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log. Locations are logical, as paths of project, build configuration, setting and property ids.
+// If artifactURI is not empty, such as the path of the linted export document, it is also set as the physical location of every result.
+func (r *LintReport) WriteSARIF(w io.Writer, artifactURI string) error {
+	run := sarifRun{Results: make([]sarifResult, len(r.Findings))}
+	run.Tool.Driver = sarifDriver{
+		Name:           "go-teamcity",
+		InformationURI: "https://github.com/cvbarros/go-teamcity",
+		Rules:          make([]sarifRule, len(r.Rules)),
+	}
+	for i, rule := range r.Rules {
+		run.Tool.Driver.Rules[i].ID = rule.ID()
+		run.Tool.Driver.Rules[i].ShortDescription.Text = rule.Description()
+		run.Tool.Driver.Rules[i].DefaultConfiguration.Level = rule.Severity()
+	}
+	for i, f := range r.Findings {
+		loc := sarifLocation{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Location.String()}}}
+		if artifactURI != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{}
+			loc.PhysicalLocation.ArtifactLocation.URI = artifactURI
+		}
+		run.Results[i] = sarifResult{
+			RuleID:    f.RuleID,
+			Level:     f.Severity,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,204 @@
+package teamcity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// IDs of the built-in lint rules
+const (
+	LintRuleVcsTriggerBranchFilter     = "vcs-trigger-without-branch-filter"
+	LintRuleArtifactDependencyRevision = "artifact-dependency-on-last-finished-in-chain"
+	LintRuleHardCodedSecret            = "hard-coded-secret"
+	LintRuleUnusedVcsRoot              = "unused-vcs-root"
+	LintRuleTemplateStepOverride       = "template-step-overridden"
+)
+
+// lintRule is a LintRule defined by a check function
+type lintRule struct {
+	id          string
+	description string
+	severity    LintSeverity
+	check       func(ctx *LintContext) []*LintFinding
+}
+
+func (r *lintRule) ID() string                            { return r.id }
+func (r *lintRule) Description() string                   { return r.description }
+func (r *lintRule) Severity() LintSeverity                { return r.severity }
+func (r *lintRule) Check(ctx *LintContext) []*LintFinding { return r.check(ctx) }
+
+// DefaultLintRules returns the built-in lint rules
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		&lintRule{
+			id:          LintRuleVcsTriggerBranchFilter,
+			description: "VCS triggers should have a branch filter, so that builds are not triggered for every branch",
+			severity:    LintSeverityWarning,
+			check:       lintVcsTriggerBranchFilter,
+		},
+		&lintRule{
+			id:          LintRuleArtifactDependencyRevision,
+			description: "Artifact dependencies of build configurations in a build chain should use builds from the same chain, not the last finished build",
+			severity:    LintSeverityWarning,
+			check:       lintArtifactDependencyRevision,
+		},
+		&lintRule{
+			id:          LintRuleHardCodedSecret,
+			description: "Secrets should be stored in password parameters and referenced from build steps, not written in them",
+			severity:    LintSeverityError,
+			check:       lintHardCodedSecrets,
+		},
+		&lintRule{
+			id:          LintRuleUnusedVcsRoot,
+			description: "VCS roots should be attached to a build configuration or template",
+			severity:    LintSeverityNote,
+			check:       lintUnusedVcsRoots,
+		},
+		&lintRule{
+			id:          LintRuleTemplateStepOverride,
+			description: "Build configurations should not redefine the steps of their templates",
+			severity:    LintSeverityWarning,
+			check:       lintTemplateStepOverrides,
+		},
+	}
+}
+
+func enabledSettings(settings []*ExportedSetting) []*ExportedSetting {
+	var out []*ExportedSetting
+	for _, s := range settings {
+		if !s.Disabled {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func lintVcsTriggerBranchFilter(ctx *LintContext) []*LintFinding {
+	var out []*LintFinding
+	ctx.EachBuildType(func(p *ExportedProject, bt *ExportedBuildType, _ bool) {
+		for _, t := range enabledSettings(bt.Triggers) {
+			if t.Type == BuildTriggerVcs && t.Properties["branchFilter"] == "" {
+				out = append(out, &LintFinding{
+					Message:  fmt.Sprintf("VCS trigger '%s' of '%s' has no branch filter and triggers builds for all branches", t.ID, bt.ID),
+					Location: LintLocation{ProjectID: p.ID, BuildTypeID: bt.ID, SettingID: t.ID},
+				})
+			}
+		}
+	})
+	return out
+}
+
+func lintArtifactDependencyRevision(ctx *LintContext) []*LintFinding {
+	var out []*LintFinding
+	ctx.EachBuildType(func(p *ExportedProject, bt *ExportedBuildType, _ bool) {
+		if len(bt.SnapshotDependencies) == 0 {
+			return
+		}
+		for _, d := range enabledSettings(bt.ArtifactDependencies) {
+			if d.Properties["revisionName"] == string(LatestFinishedBuild) {
+				out = append(out, &LintFinding{
+					Message: fmt.Sprintf("artifact dependency of '%s' on '%s' uses the last finished build, which may not belong to the same build chain; use '%s' instead",
+						bt.ID, d.SourceBuildTypeID, BuildFromSameChain),
+					Location: LintLocation{ProjectID: p.ID, BuildTypeID: bt.ID, SettingID: d.ID, Property: "revisionName"},
+				})
+			}
+		}
+	})
+	return out
+}
+
+// secretPatterns match values that look like secrets. Assignments of parameter references (%name%) or environment variables ($NAME) are not matched.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)\b["']?\s*[:=]\s*["']?[^\s"'%$]{4,}`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+}
+
+func lintHardCodedSecrets(ctx *LintContext) []*LintFinding {
+	var out []*LintFinding
+	ctx.EachBuildType(func(p *ExportedProject, bt *ExportedBuildType, _ bool) {
+		for _, step := range bt.Steps {
+			for _, name := range sortedKeys(step.Properties) {
+				for _, pattern := range secretPatterns {
+					if pattern.MatchString(step.Properties[name]) {
+						out = append(out, &LintFinding{
+							Message:  fmt.Sprintf("step '%s' of '%s' seems to contain a secret in '%s'; store it in a password parameter and reference it instead", step.ID, bt.ID, name),
+							Location: LintLocation{ProjectID: p.ID, BuildTypeID: bt.ID, SettingID: step.ID, Property: name},
+						})
+						break
+					}
+				}
+			}
+		}
+	})
+	return out
+}
+
+func lintUnusedVcsRoots(ctx *LintContext) []*LintFinding {
+	used := make(map[string]bool)
+	ctx.EachBuildType(func(_ *ExportedProject, bt *ExportedBuildType, _ bool) {
+		for _, e := range bt.VcsRoots {
+			used[e.VcsRootID] = true
+		}
+	})
+	for _, p := range ctx.Projects() {
+		// Versioned settings reference the VCS root holding the settings
+		for _, f := range p.Features {
+			if id := f.Properties["rootId"]; id != "" {
+				used[id] = true
+			}
+		}
+	}
+
+	var out []*LintFinding
+	for _, p := range ctx.Projects() {
+		for _, root := range p.VcsRoots {
+			if !used[root.ID] {
+				out = append(out, &LintFinding{
+					Message:  fmt.Sprintf("VCS root '%s' is not used by any build configuration or template", root.ID),
+					Location: LintLocation{ProjectID: p.ID, VcsRootID: root.ID},
+				})
+			}
+		}
+	}
+	return out
+}
+
+func lintTemplateStepOverrides(ctx *LintContext) []*LintFinding {
+	var out []*LintFinding
+	ctx.EachBuildType(func(p *ExportedProject, bt *ExportedBuildType, template bool) {
+		if template {
+			return
+		}
+		for _, templateID := range bt.Templates {
+			t := ctx.BuildType(templateID)
+			if t == nil {
+				continue
+			}
+			ids := make(map[string]bool)
+			names := make(map[string]bool)
+			for _, s := range t.Steps {
+				ids[s.ID] = true
+				if s.Name != "" {
+					names[s.Name] = true
+				}
+			}
+			var overridden []string
+			for _, s := range bt.Steps {
+				if ids[s.ID] || names[s.Name] {
+					overridden = append(overridden, s.ID)
+				}
+			}
+			sort.Strings(overridden)
+			for _, id := range overridden {
+				out = append(out, &LintFinding{
+					Message:  fmt.Sprintf("step '%s' of '%s' redefines a step of template '%s'", id, bt.ID, templateID),
+					Location: LintLocation{ProjectID: p.ID, BuildTypeID: bt.ID, SettingID: id},
+				})
+			}
+		}
+	})
+	return out
+}
@@ -0,0 +1,111 @@
+package teamcity
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLintTestDocument() *ProjectExport {
+	return &ProjectExport{
+		Version: ProjectExportVersion,
+		Project: &ExportedProject{
+			ID: "Proj",
+			Features: []*ExportedSetting{
+				{ID: "PROJECT_EXT_1", Type: "versionedSettings", Properties: map[string]string{"rootId": "Proj_Settings"}},
+			},
+			VcsRoots: []*ExportedVcsRoot{{ID: "Proj_Git"}, {ID: "Proj_Old"}, {ID: "Proj_Settings"}},
+			Templates: []*ExportedBuildType{{
+				ID:    "Proj_Tpl",
+				Steps: []*ExportedSetting{{ID: "RUNNER_1", Name: "build", Type: "simpleRunner"}},
+			}},
+			BuildTypes: []*ExportedBuildType{{
+				ID:        "Proj_Release",
+				Templates: []string{"Proj_Tpl"},
+				VcsRoots:  []*ExportedVcsRootEntry{{VcsRootID: "Proj_Git"}},
+				Steps: []*ExportedSetting{
+					{ID: "RUNNER_2", Name: "build", Type: "simpleRunner"},
+					{ID: "RUNNER_3", Name: "publish", Type: "simpleRunner", Properties: map[string]string{
+						"script.content": "curl -u admin:%secret.password% https://example.com\ndocker login --password=hunter22",
+					}},
+					{ID: "RUNNER_4", Name: "upload", Type: "simpleRunner", Properties: map[string]string{
+						"script.content": "TOKEN=$UPLOAD_TOKEN ./upload.sh",
+					}},
+				},
+				Triggers: []*ExportedSetting{
+					{ID: "TRIGGER_1", Type: BuildTriggerVcs},
+					{ID: "TRIGGER_2", Type: BuildTriggerVcs, Properties: map[string]string{"branchFilter": "+:<default>"}},
+					{ID: "TRIGGER_3", Type: BuildTriggerVcs, Disabled: true},
+				},
+				SnapshotDependencies: []*ExportedSetting{{ID: "Proj_Lib", SourceBuildTypeID: "Proj_Lib"}},
+				ArtifactDependencies: []*ExportedSetting{
+					{ID: "ARTIFACT_1", SourceBuildTypeID: "Proj_Lib", Properties: map[string]string{"revisionName": "lastFinished"}},
+					{ID: "ARTIFACT_2", SourceBuildTypeID: "Proj_Lib", Properties: map[string]string{"revisionName": "sameChainOrLastFinished"}},
+				},
+			}},
+		},
+	}
+}
+
+func Test_LinterDefaultRules(t *testing.T) {
+	report := NewLinter().Lint(newLintTestDocument())
+
+	var actual []string
+	for _, f := range report.Findings {
+		actual = append(actual, string(f.Severity)+" "+f.RuleID+" "+f.Location.String())
+	}
+	assert.Equal(t, []string{
+		"note unused-vcs-root Proj/Proj_Old",
+		"warning artifact-dependency-on-last-finished-in-chain Proj/Proj_Release/ARTIFACT_1/revisionName",
+		"warning template-step-overridden Proj/Proj_Release/RUNNER_2",
+		"error hard-coded-secret Proj/Proj_Release/RUNNER_3/script.content",
+		"warning vcs-trigger-without-branch-filter Proj/Proj_Release/TRIGGER_1",
+	}, actual)
+	assert.True(t, report.HasSeverity(LintSeverityError))
+}
+
+type lintRuleFunc func(ctx *LintContext) []*LintFinding
+
+func (f lintRuleFunc) ID() string                            { return "custom" }
+func (f lintRuleFunc) Description() string                   { return "Custom rule" }
+func (f lintRuleFunc) Severity() LintSeverity                { return LintSeverityNote }
+func (f lintRuleFunc) Check(ctx *LintContext) []*LintFinding { return f(ctx) }
+
+func Test_LinterReports(t *testing.T) {
+	require := require.New(t)
+	sut := NewLinter(lintRuleFunc(func(ctx *LintContext) []*LintFinding {
+		return []*LintFinding{{Message: "checked " + ctx.Root.ID, Location: LintLocation{ProjectID: ctx.Root.ID}}}
+	}))
+	report := sut.Lint(newLintTestDocument())
+
+	var out bytes.Buffer
+	require.NoError(report.WriteJSON(&out))
+	require.JSONEq(`{"findings":[{"ruleId":"custom","severity":"note","message":"checked Proj","location":{"projectId":"Proj"}}]}`, out.String())
+
+	out.Reset()
+	require.NoError(report.WriteSARIF(&out, "export.yaml"))
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []sarifResult `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(json.Unmarshal(out.Bytes(), &sarif))
+	require.Equal("2.1.0", sarif.Version)
+	require.Equal("custom", sarif.Runs[0].Tool.Driver.Rules[0].ID)
+	result := sarif.Runs[0].Results[0]
+	require.Equal(LintSeverityNote, result.Level)
+	require.Equal("checked Proj", result.Message.Text)
+	require.Equal("export.yaml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal("Proj", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
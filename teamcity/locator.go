@@ -1,8 +1,10 @@
 package teamcity
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // Locator represents a arbitraty locator to be used when querying resources, such as id:, type:, or key:
@@ -39,6 +41,73 @@ func LocatorType(id string) Locator {
 	return Locator(url.QueryEscape("type:") + id)
 }
 
+// LocatorBuildNumber creates a locator for a Build by its build configuration id and build number
+func LocatorBuildNumber(buildTypeID string, number string) Locator {
+	return Locator(url.QueryEscape(fmt.Sprintf("buildType:(id:%s),number:%s", buildTypeID, locatorValue(number))))
+}
+
 func (l Locator) String() string {
 	return string(l)
 }
+
+// locatorDimensions builds a multi-dimensional locator such as "build:(id:1),status:FAILURE".
+// Unlike Locator values, the result is not escaped, since it is meant to be sent as a "locator" query parameter.
+type locatorDimensions []string
+
+func (d *locatorDimensions) add(name string, value string) {
+	if value != "" {
+		*d = append(*d, fmt.Sprintf("%s:%s", name, value))
+	}
+}
+
+func (d *locatorDimensions) addBool(name string, value *bool) {
+	if value != nil {
+		d.add(name, fmt.Sprintf("%t", *value))
+	}
+}
+
+func (d *locatorDimensions) addInt(name string, value int) {
+	if value != 0 {
+		d.add(name, fmt.Sprintf("%d", value))
+	}
+}
+
+func (d locatorDimensions) String() string {
+	return strings.Join(d, ",")
+}
+
+// query returns the dimensions as a "?locator=" query string to append to a resource path
+func (d locatorDimensions) query() string {
+	if len(d) == 0 {
+		return ""
+	}
+	return "?locator=" + url.QueryEscape(d.String())
+}
+
+// locatorValue wraps values containing locator syntax characters in parentheses, so they are matched literally.
+// Values with unbalanced parentheses cannot be wrapped, so they are sent base64 encoded instead.
+func locatorValue(v string) string {
+	if !strings.ContainsAny(v, ",:()") {
+		return v
+	}
+	if !balancedParentheses(v) {
+		return "$base64:" + base64.URLEncoding.EncodeToString([]byte(v))
+	}
+	return "(" + v + ")"
+}
+
+func balancedParentheses(v string) bool {
+	depth := 0
+	for _, c := range v {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return false
+			}
+			depth--
+		}
+	}
+	return depth == 0
+}
@@ -19,3 +19,10 @@ func Test_LocatorId(t *testing.T) {
 
 	assert.Equal(t, "id%3A_Root", actual)
 }
+
+func Test_LocatorBuildNumber(t *testing.T) {
+	sut := LocatorBuildNumber("Release_Build", "1.2.3")
+	actual := sut.String()
+
+	assert.Equal(t, "buildType%3A%28id%3ARelease_Build%29%2Cnumber%3A1.2.3", actual)
+}
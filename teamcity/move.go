@@ -0,0 +1,196 @@
+package teamcity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MoveConflictError is returned when a project or build configuration cannot be moved to the destination project
+type MoveConflictError struct {
+	// Resource is the kind of the moved resource, "project" or "build type"
+	Resource string
+	// ID of the moved resource
+	ID string
+	// Destination is the id of the project the resource was moved to
+	Destination string
+	// IntoItself is true when a project is moved under itself or one of its subprojects
+	IntoItself bool
+	// VcsRootIDs are the VCS roots used by the moved build configurations that would not be visible from the destination
+	VcsRootIDs []string
+	// TemplateIDs are the templates used by the moved build configurations that would not be visible from the destination
+	TemplateIDs []string
+	// Err is the error returned by the server when it refused the move
+	Err error
+}
+
+func (e *MoveConflictError) Error() string {
+	var reasons []string
+	if e.IntoItself {
+		reasons = append(reasons, "the destination is the project itself or one of its subprojects")
+	}
+	if len(e.VcsRootIDs) > 0 {
+		reasons = append(reasons, fmt.Sprintf("VCS roots '%s' would not be visible", strings.Join(e.VcsRootIDs, "', '")))
+	}
+	if len(e.TemplateIDs) > 0 {
+		reasons = append(reasons, fmt.Sprintf("templates '%s' would not be visible", strings.Join(e.TemplateIDs, "', '")))
+	}
+	if e.Err != nil {
+		reasons = append(reasons, e.Err.Error())
+	}
+	return fmt.Sprintf("cannot move %s '%s' to project '%s': %s", e.Resource, e.ID, e.Destination, strings.Join(reasons, ", "))
+}
+
+func (e *MoveConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Move moves the project with given id under another parent project, along with its subprojects, and returns the updated project.
+// A *MoveConflictError is returned if the destination is the project itself or one of its subprojects, if build configurations of the project use
+// VCS roots or templates that would not be visible from the destination, or if the server refuses the move.
+func (s *ProjectService) Move(id string, parentProjectID string) (*Project, error) {
+	current, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Moving to the same parent would make TeamCity copy the project instead
+	if current.ParentProjectID == parentProjectID {
+		return current, nil
+	}
+
+	conflict := &MoveConflictError{Resource: "project", ID: id, Destination: parentProjectID}
+	visible, err := projectAncestry(s.commonHelper, parentProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if visible[id] {
+		conflict.IntoItself = true
+		return nil, conflict
+	}
+
+	// Resources of the moved project and its subprojects move along with it
+	var d locatorDimensions
+	d.add("affectedProject", fmt.Sprintf("(id:%s)", id))
+	var tree struct {
+		Items []*ProjectReference `json:"project"`
+	}
+	if err := s.commonHelper.getWithFields("projects"+d.query(), getFields{Fields: "project(id)"}, &tree, "subprojects"); err != nil {
+		return nil, err
+	}
+	visible[id] = true
+	for _, p := range tree.Items {
+		visible[p.ID] = true
+	}
+
+	d.add("templateFlag", "any")
+	if err := invisibleReferences(s.commonHelper, d, visible, conflict); err != nil {
+		return nil, err
+	}
+	if len(conflict.VcsRootIDs) > 0 || len(conflict.TemplateIDs) > 0 {
+		return nil, conflict
+	}
+
+	var parent ProjectReference
+	err = s.restHelper.put(LocatorID(id).String()+"/parentProject", &ProjectReference{ID: parentProjectID}, &parent, "parent project")
+	if err != nil {
+		return nil, moveError(conflict, err)
+	}
+	return s.GetByID(id)
+}
+
+// Move moves the build configuration or template with given id to another project, and returns the updated build configuration.
+// A *MoveConflictError is returned if it uses VCS roots or templates that would not be visible from the destination, or if the server refuses the move.
+func (s *BuildTypeService) Move(id string, projectID string) (*BuildType, error) {
+	conflict := &MoveConflictError{Resource: "build type", ID: id, Destination: projectID}
+	visible, err := projectAncestry(s.commonHelper, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var d locatorDimensions
+	d.add("id", id)
+	d.add("templateFlag", "any")
+	if err := invisibleReferences(s.commonHelper, d, visible, conflict); err != nil {
+		return nil, err
+	}
+	if len(conflict.VcsRootIDs) > 0 || len(conflict.TemplateIDs) > 0 {
+		return nil, conflict
+	}
+
+	var project ProjectReference
+	err = s.restHelper.put(LocatorID(id).String()+"/project", &ProjectReference{ID: projectID}, &project, "build type project")
+	if err != nil {
+		return nil, moveError(conflict, err)
+	}
+	return s.GetByID(id)
+}
+
+// moveError returns the conflict for errors of the server refusing a move, and err for other failures
+func moveError(conflict *MoveConflictError, err error) error {
+	if isStatusError(err, 400) || isStatusError(err, 403) || isStatusError(err, 409) {
+		conflict.Err = err
+		return conflict
+	}
+	return err
+}
+
+// projectAncestry returns the ids of the project and its ancestors, up to the root project
+func projectAncestry(r *restHelper, projectID string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for id := projectID; id != ""; {
+		var parent struct {
+			ParentProjectID string `json:"parentProjectId"`
+		}
+		if err := r.getWithFields("projects/"+LocatorID(id).String(), getFields{Fields: "id,parentProjectId"}, &parent, "project"); err != nil {
+			return nil, err
+		}
+		out[id] = true
+		id = parent.ParentProjectID
+	}
+	return out, nil
+}
+
+// invisibleReferences adds to the conflict the VCS roots and templates used by the build configurations matching the locator, which are not owned by a visible project
+func invisibleReferences(r *restHelper, d locatorDimensions, visible map[string]bool, conflict *MoveConflictError) error {
+	var out struct {
+		Items []*struct {
+			Templates *Templates      `json:"templates"`
+			Entries   *VcsRootEntries `json:"vcs-root-entries"`
+		} `json:"buildType"`
+	}
+	fields := "buildType(templates(buildType(id,projectId)),vcs-root-entries(vcs-root-entry(vcs-root(id,project(id)))))"
+	if err := r.getWithFields("buildTypes"+d.query(), getFields{Fields: fields}, &out, "build types"); err != nil {
+		return err
+	}
+
+	roots := make(map[string]bool)
+	templates := make(map[string]bool)
+	for _, bt := range out.Items {
+		if bt.Templates != nil {
+			for _, t := range bt.Templates.Items {
+				if !visible[t.ProjectID] {
+					templates[t.ID] = true
+				}
+			}
+		}
+		if bt.Entries != nil {
+			for _, e := range bt.Entries.Items {
+				if e.VcsRoot != nil && e.VcsRoot.Project != nil && !visible[e.VcsRoot.Project.ID] {
+					roots[e.VcsRoot.ID] = true
+				}
+			}
+		}
+	}
+	conflict.VcsRootIDs = sortedSet(roots)
+	conflict.TemplateIDs = sortedSet(templates)
+	return nil
+}
+
+func sortedSet(m map[string]bool) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,99 @@
+package teamcity
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingServer records and refuses the requests other than GET to the paths in failures with the given status, and serves the others with recordingServer
+type failingServer struct {
+	*recordingServer
+	failures map[string]int
+}
+
+func newFailingServer(t *testing.T, responses map[string]string) (*Client, *failingServer) {
+	_, recording := newRecordingServer(t, responses)
+	fake := &failingServer{recordingServer: recording}
+	return newTestClient(t, fake), fake
+}
+
+func (f *failingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, ok := f.failures[r.URL.EscapedPath()]; ok && r.Method != "GET" {
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.requests = append(f.requests, recordedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Body: string(body)})
+		f.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte("refused"))
+		return
+	}
+	f.recordingServer.ServeHTTP(w, r)
+}
+
+var moveResponses = map[string]string{
+	"/httpAuth/app/rest/projects/id%3A_Root":    `{"id":"_Root"}`,
+	"/httpAuth/app/rest/projects/id%3AProj":     `{"id":"Proj","name":"Project","parentProjectId":"_Root","parameters":{}}`,
+	"/httpAuth/app/rest/projects/id%3AProj_Sub": `{"id":"Proj_Sub","name":"Sub","parentProjectId":"Proj","parameters":{}}`,
+	"/httpAuth/app/rest/projects/id%3AOther":    `{"id":"Other","name":"Other","parentProjectId":"_Root","parameters":{}}`,
+	"/httpAuth/app/rest/buildTypes/Proj_Sub_Build": `{"id":"Proj_Sub_Build","name":"Build","projectId":"Proj_Sub",
+		"settings":{},"parameters":{},"vcs-root-entries":{},"steps":{}}`,
+	"/httpAuth/app/rest/buildTypes?locator=id:Proj_Sub_Build,templateFlag:any": `{"buildType":[{
+		"templates":{"buildType":[{"id":"Proj_Tpl","projectId":"Proj"}]},
+		"vcs-root-entries":{"vcs-root-entry":[{"vcs-root":{"id":"Root_Git","project":{"id":"_Root"}}},{"vcs-root":{"id":"Proj_Git","project":{"id":"Proj"}}}]}}]}`,
+	"/httpAuth/app/rest/projects?locator=affectedProject:(id:Proj_Sub)": `{"project":[{"id":"Proj_Sub"},{"id":"Proj_Sub_Deep"}]}`,
+	"/httpAuth/app/rest/buildTypes?locator=affectedProject:(id:Proj_Sub),templateFlag:any": `{"buildType":[{
+		"vcs-root-entries":{"vcs-root-entry":[{"vcs-root":{"id":"Proj_Sub_Git","project":{"id":"Proj_Sub_Deep"}}}]}}]}`,
+}
+
+func Test_BuildTypeMove(t *testing.T) {
+	require := require.New(t)
+	client, fake := newRecordingServer(t, moveResponses)
+
+	bt, err := client.BuildTypes.Move("Proj_Sub_Build", "Proj_Sub")
+	require.NoError(err)
+	require.Equal("Proj_Sub", bt.ProjectID)
+	require.Equal([]recordedRequest{{
+		Method: "PUT",
+		Path:   "/httpAuth/app/rest/buildTypes/id%3AProj_Sub_Build/project",
+		Body:   "{\"id\":\"Proj_Sub\"}\n",
+	}}, fake.requests)
+}
+
+func Test_BuildTypeMoveReportsInvisibleReferences(t *testing.T) {
+	require := require.New(t)
+	client, fake := newRecordingServer(t, moveResponses)
+
+	_, err := client.BuildTypes.Move("Proj_Sub_Build", "Other")
+	var conflict *MoveConflictError
+	require.True(errors.As(err, &conflict))
+	require.Equal([]string{"Proj_Git"}, conflict.VcsRootIDs)
+	require.Equal([]string{"Proj_Tpl"}, conflict.TemplateIDs)
+	require.EqualError(err, "cannot move build type 'Proj_Sub_Build' to project 'Other': VCS roots 'Proj_Git' would not be visible, templates 'Proj_Tpl' would not be visible")
+	require.Empty(fake.requests)
+}
+
+func Test_ProjectMove(t *testing.T) {
+	require := require.New(t)
+	client, fake := newFailingServer(t, moveResponses)
+
+	_, err := client.Projects.Move("Proj", "Proj_Sub")
+	require.EqualError(err, "cannot move project 'Proj' to project 'Proj_Sub': the destination is the project itself or one of its subprojects")
+
+	// VCS roots of the subprojects move along with the project
+	fake.failures = map[string]int{"/httpAuth/app/rest/projects/id%3AProj_Sub/parentProject": 400}
+	_, err = client.Projects.Move("Proj_Sub", "Other")
+	var conflict *MoveConflictError
+	require.True(errors.As(err, &conflict))
+	require.Empty(conflict.VcsRootIDs)
+	require.EqualError(conflict.Err, "Error '400' when performing 'PUT' operation - parent project: refused")
+
+	fake.failures = nil
+	project, err := client.Projects.Move("Proj_Sub", "Other")
+	require.NoError(err)
+	require.Equal("Proj_Sub", project.ID)
+	require.Len(fake.requests, 2)
+}
@@ -0,0 +1,128 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dghubble/sling"
+)
+
+// Mute silences failures of tests or build problems within a scope, so they don't fail builds
+type Mute struct {
+	ID         int            `json:"id,omitempty" xml:"id"`
+	Href       string         `json:"href,omitempty" xml:"href"`
+	Assignment *Assignment    `json:"assignment,omitempty"`
+	Scope      *ProblemScope  `json:"scope,omitempty"`
+	Target     *ProblemTarget `json:"target,omitempty"`
+	Resolution *Resolution    `json:"resolution,omitempty"`
+}
+
+type mutesJSON struct {
+	Count int32   `json:"count,omitempty" xml:"count"`
+	Href  string  `json:"href,omitempty" xml:"href"`
+	Items []*Mute `json:"mute"`
+}
+
+// NewMute returns a Mute for the given tests or build problems. Comment is optional.
+// Use NewResolutionAtTime for mutes that expire.
+func NewMute(comment string, scope *ProblemScope, target *ProblemTarget, resolution *Resolution) (*Mute, error) {
+	if err := validateProblemScope(scope, target, resolution); err != nil {
+		return nil, err
+	}
+	if target.AnyProblem {
+		return nil, errors.New("mutes must target specific tests or build problems")
+	}
+
+	return &Mute{
+		Assignment: &Assignment{Text: comment},
+		Scope:      scope,
+		Target:     target,
+		Resolution: resolution,
+	}, nil
+}
+
+// MuteLocator filters mutes. Zero values are omitted from the locator.
+type MuteLocator struct {
+	// ProjectID returns mutes affecting the project and its subprojects
+	ProjectID string
+	TestName  string
+	ProblemID string
+}
+
+func (l MuteLocator) dimensions() locatorDimensions {
+	var d locatorDimensions
+	if l.ProjectID != "" {
+		d.add("affectedProject", fmt.Sprintf("(id:%s)", l.ProjectID))
+	}
+	if l.TestName != "" {
+		d.add("test", fmt.Sprintf("(name:%s)", locatorValue(l.TestName)))
+	}
+	if l.ProblemID != "" {
+		d.add("problem", fmt.Sprintf("(id:%s)", l.ProblemID))
+	}
+	return d
+}
+
+// String returns the unescaped locator, for instance "affectedProject:(id:Root)"
+func (l MuteLocator) String() string {
+	return l.dimensions().String()
+}
+
+// MuteService has operations for muting and unmuting tests and build problems
+type MuteService struct {
+	sling      *sling.Sling
+	httpClient *http.Client
+	restHelper *restHelper
+}
+
+func newMuteService(base *sling.Sling, httpClient *http.Client) *MuteService {
+	sling := base.Path("mutes/")
+	return &MuteService{
+		sling:      sling,
+		httpClient: httpClient,
+		restHelper: newRestHelper(httpClient, sling),
+	}
+}
+
+// Create mutes the tests or build problems described by mute
+func (s *MuteService) Create(mute *Mute) (*Mute, error) {
+	if mute == nil {
+		return nil, errors.New("mute can't be nil")
+	}
+
+	var created Mute
+	err := s.restHelper.post("", mute, &created, "mute")
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// List returns all mutes matching the locator
+func (s *MuteService) List(locator MuteLocator) ([]*Mute, error) {
+	var out mutesJSON
+	err := s.restHelper.getWithFields(locator.dimensions().query(), getFields{Fields: "count,href,mute($long)"}, &out, "mutes")
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Items, nil
+}
+
+// GetByID returns a mute by its id
+func (s *MuteService) GetByID(id int) (*Mute, error) {
+	var out Mute
+	err := s.restHelper.getWithFields(LocatorIDInt(id).String(), getFields{Fields: "$long"}, &out, "mute")
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Unmute removes the mute with given id
+func (s *MuteService) Unmute(id int) error {
+	return s.restHelper.delete(LocatorIDInt(id).String(), "mute")
+}
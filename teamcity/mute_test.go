@@ -0,0 +1,67 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MuteSerialize(t *testing.T) {
+	require := require.New(t)
+
+	expiry := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	sut, err := NewMute("flaky", NewBuildTypeScope("Bt1"), NewTestTarget("pkg.TestFoo"), NewResolutionAtTime(expiry))
+	require.NoError(err)
+
+	actual, err := json.Marshal(sut)
+	require.NoError(err)
+	require.JSONEq(`{
+		"assignment": {"text": "flaky"},
+		"scope": {"buildTypes": {"count": 1, "buildType": [{"id": "Bt1"}]}},
+		"target": {"tests": {"count": 1, "test": [{"name": "pkg.TestFoo"}]}},
+		"resolution": {"type": "atTime", "time": "20200501T120000+0000"}
+	}`, string(actual))
+}
+
+func Test_MuteDeserialize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var sut Mute
+	require.NoError(json.Unmarshal([]byte(muteJSON), &sut))
+
+	assert.Equal(12, sut.ID)
+	assert.Equal("admin", sut.Assignment.User.Username)
+	assert.Equal("Project", sut.Scope.Project.ID)
+	assert.Equal("-1234", sut.Target.Problems.Items[0].ID)
+	assert.Equal(ResolutionWhenFixed, sut.Resolution.Type)
+}
+
+func Test_MuteInvariants(t *testing.T) {
+	_, err := NewMute("", NewProjectScope("Project"), NewAnyProblemTarget(), NewResolutionManually())
+	assert.EqualError(t, err, "mutes must target specific tests or build problems")
+
+	_, err = NewMute("", NewProjectScope("Project"), nil, NewResolutionManually())
+	assert.EqualError(t, err, "target is required")
+}
+
+const muteJSON = `
+{
+	"id": 12,
+	"assignment": {
+		"user": {"id": 1, "username": "admin"},
+		"timestamp": "20200301T093015+0000",
+		"text": "known issue"
+	},
+	"scope": {
+		"project": {"id": "Project", "name": "Project"}
+	},
+	"target": {
+		"problems": {"problem": [{"id": "-1234", "type": "TC_EXIT_CODE"}]}
+	},
+	"resolution": {"type": "whenFixed"}
+}
+`
@@ -41,6 +41,9 @@ type Parameter struct {
 	Value string `json:"value" xml:"value"`
 
 	Type string `json:"-"`
+
+	// Spec is how the parameter is edited and validated. Parameters without a specification are text fields.
+	Spec *ParameterSpec `json:"-"`
 }
 
 // NewParametersEmpty returns an empty collection of Parameters
@@ -76,6 +79,26 @@ func NewParameter(t string, name string, value string) (*Parameter, error) {
 	}, nil
 }
 
+// NewParameterWithSpec creates a new instance of a parameter with the given type and specification, such as a password or a select parameter
+func NewParameterWithSpec(t string, name string, value string, spec *ParameterSpec) (*Parameter, error) {
+	out, err := NewParameter(t, name, value)
+	if err != nil {
+		return nil, err
+	}
+	if spec != nil {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	out.Spec = spec
+	return out, nil
+}
+
+// IsPassword returns true for password parameters, whose values are never returned by the server
+func (p *Parameter) IsPassword() bool {
+	return p.Spec != nil && p.Spec.Type == ParameterSpecPassword
+}
+
 // MarshalJSON implements JSON serialization for Parameter
 func (p *Parameter) MarshalJSON() ([]byte, error) {
 	out := p.Property()
@@ -89,24 +112,43 @@ func (p *Parameter) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	out, err := parameterFromProperty(&aux)
+	if err != nil {
+		return err
+	}
+	*p = *out
+	return nil
+}
+
+// parameterFromProperty converts a Property to a Parameter, deriving its type from the "system." or "env." name prefix
+func parameterFromProperty(prop *Property) (*Parameter, error) {
 	var name, paramType string
-	if strings.HasPrefix(aux.Name, "system.") {
-		name = strings.TrimPrefix(aux.Name, "system.")
+	if strings.HasPrefix(prop.Name, "system.") {
+		name = strings.TrimPrefix(prop.Name, "system.")
 		paramType = ParameterTypes.System
-	} else if strings.HasPrefix(aux.Name, "env.") {
-		name = strings.TrimPrefix(aux.Name, "env.")
+	} else if strings.HasPrefix(prop.Name, "env.") {
+		name = strings.TrimPrefix(prop.Name, "env.")
 		paramType = ParameterTypes.EnvironmentVariable
 	} else {
-		name = aux.Name
+		name = prop.Name
 		paramType = ParameterTypes.Configuration
 	}
-	p.Name = name
-	if aux.Inherited != nil {
-		p.Inherited = *aux.Inherited
+	out := &Parameter{
+		Name:  name,
+		Value: prop.Value,
+		Type:  paramType,
 	}
-	p.Value = aux.Value
-	p.Type = paramType
-	return nil
+	if prop.Inherited != nil {
+		out.Inherited = *prop.Inherited
+	}
+	if prop.Type != nil && prop.Type.RawValue != "" {
+		spec, err := ParseParameterSpec(prop.Type.RawValue)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %s", prop.Name, err)
+		}
+		out.Spec = spec
+	}
+	return out, nil
 }
 
 // Properties convert a Parameters collection to a Properties collection
@@ -128,6 +170,9 @@ func (p *Parameter) Property() *Property {
 	if p.Inherited {
 		out.Inherited = NewBool(p.Inherited)
 	}
+	if p.Spec != nil {
+		out.Type = &Type{RawValue: p.Spec.String()}
+	}
 	return out
 }
 
@@ -147,9 +192,12 @@ func (p *Parameters) AddOrReplaceValue(t string, n string, v string) {
 	p.Add(param)
 }
 
-// AddOrReplaceParameter will update a parameter value if another parameter with the same name exists. It won't replace the Parameter struct within the Parameters collection.
+// AddOrReplaceParameter will update a parameter value and specification if another parameter with the same name exists. It won't replace the Parameter struct within the Parameters collection.
 func (p *Parameters) AddOrReplaceParameter(param *Parameter) {
 	p.AddOrReplaceValue(param.Type, param.Name, param.Value)
+	if added, ok := p.GetOk(param.Type, param.Name); ok {
+		added.Spec = param.Spec
+	}
 }
 
 // Add a new parameter to this collection
@@ -203,6 +251,81 @@ func (p *Parameters) GetOk(t string, n string) (out *Parameter, ok bool) {
 	return nil, false
 }
 
+// hasUnsetPasswords returns true if some password parameters have no value, such as when read from the server
+func (p *Parameters) hasUnsetPasswords() bool {
+	for _, item := range p.Items {
+		if item.IsPassword() && item.Value == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// putParameters replaces the parameters at path, such as "id:Project/parameters", with params.
+// Since the server never returns the values of password parameters, password parameters without a value keep their current value:
+// the parameters are then updated one by one with putParameter, instead of replacing the whole collection at once, and inherited ones are left alone.
+func putParameters(r *restHelper, path string, params *Parameters, ownerDescription string) error {
+	if params == nil {
+		params = NewParametersEmpty()
+	}
+	if !params.hasUnsetPasswords() {
+		var out Parameters
+		return r.put(path, params, &out, "parameters of "+ownerDescription)
+	}
+
+	var current Parameters
+	if err := r.get(path, &current, "parameters of "+ownerDescription); err != nil {
+		return err
+	}
+	for _, item := range params.Items {
+		if item.Inherited {
+			continue
+		}
+		existing, _ := current.GetOk(item.Type, item.Name)
+		if _, err := putParameter(r, path+"/"+parameterPath(item.Type, item.Name), item, existing, ownerDescription); err != nil {
+			return err
+		}
+	}
+	for _, item := range current.Items {
+		if item.Inherited {
+			continue
+		}
+		if _, ok := params.GetOk(item.Type, item.Name); !ok {
+			if err := r.delete(path+"/"+parameterPath(item.Type, item.Name), fmt.Sprintf("parameter '%s' of %s", item.Name, ownerDescription)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// putParameter creates or updates the parameter p at path, given the existing parameter with the same name, if any, and returns it as stored.
+// A password parameter without a value only has its specification updated, since the server never returns the values of password parameters,
+// and an error is returned if the existing one is inherited, as its value would be overridden by an empty one.
+func putParameter(r *restHelper, path string, p *Parameter, existing *Parameter, ownerDescription string) (*Parameter, error) {
+	resourceDescription := fmt.Sprintf("parameter '%s' of %s", p.Name, ownerDescription)
+	if p.IsPassword() && p.Value == "" && existing != nil {
+		if existing.Inherited {
+			return nil, fmt.Errorf("cannot set inherited password parameter '%s' of %s without a value, as it would override the inherited value", p.Name, ownerDescription)
+		}
+		if existing.Spec == nil || existing.Spec.String() != p.Spec.String() {
+			var spec Type
+			if err := r.put(path+"/type", &Type{RawValue: p.Spec.String()}, &spec, resourceDescription); err != nil {
+				return nil, err
+			}
+		}
+		out := *existing
+		out.Spec = p.Spec
+		return &out, nil
+	}
+
+	var out Parameter
+	if err := r.put(path, p, &out, resourceDescription); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 var paramPrefixByType = map[string]string{
 	string(ParameterTypes.Configuration):       "",
 	string(ParameterTypes.System):              "system.",
@@ -0,0 +1,479 @@
+package teamcity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ParameterProblemKind is the kind of a ParameterProblem
+type ParameterProblemKind string
+
+const (
+	// ParameterProblemUndefined is a reference to a parameter that is not defined
+	ParameterProblemUndefined ParameterProblemKind = "undefined"
+	// ParameterProblemCycle is a parameter that references itself, directly or through other parameters
+	ParameterProblemCycle ParameterProblemKind = "cycle"
+)
+
+// ParameterUsageKind is the kind of place a parameter reference is found in
+type ParameterUsageKind string
+
+const (
+	ParameterUsageParameter          ParameterUsageKind = "parameter"
+	ParameterUsageSetting            ParameterUsageKind = "setting"
+	ParameterUsageStep               ParameterUsageKind = "step"
+	ParameterUsageTrigger            ParameterUsageKind = "trigger"
+	ParameterUsageFeature            ParameterUsageKind = "feature"
+	ParameterUsageSnapshotDependency ParameterUsageKind = "snapshotDependency"
+	ParameterUsageArtifactDependency ParameterUsageKind = "artifactDependency"
+	ParameterUsageAgentRequirement   ParameterUsageKind = "agentRequirement"
+	ParameterUsageVcsRoot            ParameterUsageKind = "vcsRoot"
+)
+
+// ParameterUsage is a %name% reference found in a parameter, setting or property value
+type ParameterUsage struct {
+	Kind ParameterUsageKind
+	// ID is the name of the parameter or setting, or the id of the step, trigger, feature, dependency, agent requirement or VCS root
+	ID string
+	// Property is the name of the property holding the reference, empty for parameters and settings
+	Property string
+	// Reference is the name of the referenced parameter
+	Reference string
+}
+
+func (u *ParameterUsage) String() string {
+	if u.Property == "" {
+		return fmt.Sprintf("%s '%s'", u.Kind, u.ID)
+	}
+	return fmt.Sprintf("%s '%s' property '%s'", u.Kind, u.ID, u.Property)
+}
+
+// ParameterProblem is an undefined reference or a reference cycle
+type ParameterProblem struct {
+	Kind ParameterProblemKind
+	// Usage is the reference with the problem
+	Usage *ParameterUsage
+	// Cycle lists the parameters of a cycle, starting and ending with the same one
+	Cycle []string
+}
+
+func (p *ParameterProblem) String() string {
+	if p.Kind == ParameterProblemCycle {
+		return fmt.Sprintf("%s: reference cycle %s", p.Usage, strings.Join(p.Cycle, " -> "))
+	}
+	return fmt.Sprintf("%s: undefined parameter '%s'", p.Usage, p.Usage.Reference)
+}
+
+// predefinedParameterPrefixes are the prefixes of parameters provided by the server and the agents when a build runs
+var predefinedParameterPrefixes = []string{"agent.", "build.", "env.BUILD_VCS_NUMBER_", "system.agent.", "system.build.", "system.teamcity.", "teamcity.", "vcsroot."}
+
+// predefinedEnvironmentVariables are the environment variables TeamCity sets when a build runs
+var predefinedEnvironmentVariables = map[string]bool{
+	"env.BUILD_IS_PERSONAL":              true,
+	"env.BUILD_NUMBER":                   true,
+	"env.BUILD_VCS_NUMBER":               true,
+	"env.TEAMCITY_BUILDCONF_NAME":        true,
+	"env.TEAMCITY_BUILD_PROPERTIES_FILE": true,
+	"env.TEAMCITY_CAPTURE_ENV":           true,
+	"env.TEAMCITY_GIT_PATH":              true,
+	"env.TEAMCITY_GIT_VERSION":           true,
+	"env.TEAMCITY_PROJECT_NAME":          true,
+	"env.TEAMCITY_VERSION":               true,
+}
+
+// IsPredefinedParameter returns true for parameters provided by the server or the agents when a build runs, such as "build.number", "teamcity.build.id" or "env.BUILD_NUMBER".
+// Other environment variables of the agents, such as "env.PATH", vary between agents and are not predefined: set ParameterResolver.Predefined to accept them.
+func IsPredefinedParameter(name string) bool {
+	if predefinedEnvironmentVariables[name] {
+		return true
+	}
+	for _, prefix := range predefinedParameterPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParameterResolver expands %name% references between parameters
+type ParameterResolver struct {
+	values map[string]string
+	// dependencies holds the parameters of each dependency, which are resolved in the scope of the dependency and are not checked
+	dependencies map[string]map[string]string
+	// Predefined returns true for parameters that are defined when a build runs, and are not reported as undefined. Defaults to IsPredefinedParameter.
+	Predefined func(name string) bool
+}
+
+// NewParameterResolver returns a resolver for the parameters of the given scopes, from the outermost to the innermost, such as the root project down to a build configuration.
+// Parameters of a scope override the ones of the same name in the previous scopes.
+func NewParameterResolver(scopes ...*Parameters) *ParameterResolver {
+	r := &ParameterResolver{
+		values:       make(map[string]string),
+		dependencies: make(map[string]map[string]string),
+		Predefined:   IsPredefinedParameter,
+	}
+	for _, scope := range scopes {
+		if scope == nil {
+			continue
+		}
+		for _, p := range scope.Items {
+			prop := p.Property()
+			r.values[prop.Name] = prop.Value
+		}
+	}
+	return r
+}
+
+// Set defines or overrides a parameter
+func (r *ParameterResolver) Set(name string, value string) {
+	r.values[name] = value
+}
+
+// AddDependency defines the parameters of a dependency, which are referenced as %dep.<buildTypeID>.<name>%
+func (r *ParameterResolver) AddDependency(buildTypeID string, parameters *Parameters) {
+	values := make(map[string]string)
+	if parameters != nil {
+		for _, p := range parameters.Items {
+			prop := p.Property()
+			values[prop.Name] = prop.Value
+		}
+	}
+	r.dependencies[buildTypeID] = values
+}
+
+// lookup returns the value of a parameter, or of a parameter of a dependency
+func (r *ParameterResolver) lookup(name string) (string, bool) {
+	if v, ok := r.values[name]; ok {
+		return v, true
+	}
+	for id, values := range r.dependencies {
+		if rest := strings.TrimPrefix(name, "dep."+id+"."); rest != name {
+			v, ok := values[rest]
+			return v, ok
+		}
+	}
+	return "", false
+}
+
+// Names returns the names of the defined parameters, excluding the ones of dependencies, sorted
+func (r *ParameterResolver) Names() []string {
+	out := make([]string, 0, len(r.values))
+	for name := range r.values {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Defined returns true if the parameter is defined or predefined
+func (r *ParameterResolver) Defined(name string) bool {
+	if _, ok := r.lookup(name); ok {
+		return true
+	}
+	if r.Predefined != nil && r.Predefined(name) {
+		return true
+	}
+	// Dependencies also provide their predefined parameters, such as %dep.<id>.build.number%
+	for id := range r.dependencies {
+		if rest := strings.TrimPrefix(name, "dep."+id+"."); rest != name {
+			return r.Predefined != nil && r.Predefined(rest)
+		}
+	}
+	return false
+}
+
+// Resolve expands the references of value. References to undefined or predefined parameters, and references that are part of a cycle, are kept as they are.
+// Escaped percent signs (%%) are kept as well.
+func (r *ParameterResolver) Resolve(value string) string {
+	return r.expand(value, nil)
+}
+
+// ResolveParameter returns the expanded value of a parameter, and false if it is not defined
+func (r *ParameterResolver) ResolveParameter(name string) (string, bool) {
+	value, ok := r.lookup(name)
+	if !ok {
+		return "", false
+	}
+	return r.expand(value, []string{name}), true
+}
+
+func (r *ParameterResolver) expand(value string, stack []string) string {
+	var out strings.Builder
+	for _, token := range parseParameterValue(value) {
+		if token.reference == "" || inStack(stack, token.reference) {
+			out.WriteString(token.text)
+			continue
+		}
+		v, ok := r.lookup(token.reference)
+		if !ok {
+			out.WriteString(token.text)
+			continue
+		}
+		out.WriteString(r.expand(v, append(stack, token.reference)))
+	}
+	return out.String()
+}
+
+// Check returns the undefined references and the reference cycles of the parameters, sorted by parameter name
+func (r *ParameterResolver) Check() []*ParameterProblem {
+	var out []*ParameterProblem
+	for _, name := range r.Names() {
+		for _, ref := range ParameterReferences(r.values[name]) {
+			if !r.Defined(ref) {
+				usage := &ParameterUsage{Kind: ParameterUsageParameter, ID: name, Reference: ref}
+				out = append(out, &ParameterProblem{Kind: ParameterProblemUndefined, Usage: usage})
+			}
+		}
+	}
+	return append(out, r.cycles()...)
+}
+
+// CheckValue returns the undefined references of a value used in the given place
+func (r *ParameterResolver) CheckValue(kind ParameterUsageKind, id string, property string, value string) []*ParameterProblem {
+	var out []*ParameterProblem
+	for _, ref := range ParameterReferences(value) {
+		if !r.Defined(ref) {
+			usage := &ParameterUsage{Kind: kind, ID: id, Property: property, Reference: ref}
+			out = append(out, &ParameterProblem{Kind: ParameterProblemUndefined, Usage: usage})
+		}
+	}
+	return out
+}
+
+// cycles reports each reference cycle once, starting with the parameter of the cycle that comes first by name
+func (r *ParameterResolver) cycles() []*ParameterProblem {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	seen := make(map[string]bool)
+	var out []*ParameterProblem
+
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, ref := range ParameterReferences(r.values[name]) {
+			if _, ok := r.values[ref]; !ok {
+				continue
+			}
+			switch state[ref] {
+			case visiting:
+				cycle := rotateCycle(path[indexOf(path, ref):])
+				key := strings.Join(cycle, "\x00")
+				if !seen[key] {
+					seen[key] = true
+					usage := &ParameterUsage{Kind: ParameterUsageParameter, ID: cycle[0], Reference: cycle[1%len(cycle)]}
+					out = append(out, &ParameterProblem{Kind: ParameterProblemCycle, Usage: usage, Cycle: append(cycle, cycle[0])})
+				}
+			case 0:
+				visit(ref, path)
+			}
+		}
+		state[name] = done
+	}
+	for _, name := range r.Names() {
+		if state[name] == 0 {
+			visit(name, nil)
+		}
+	}
+	return out
+}
+
+// rotateCycle returns the cycle starting with its smallest name
+func rotateCycle(cycle []string) []string {
+	first := 0
+	for i, name := range cycle {
+		if name < cycle[first] {
+			first = i
+		}
+	}
+	return append(append([]string{}, cycle[first:]...), cycle[:first]...)
+}
+
+func indexOf(items []string, item string) int {
+	for i, v := range items {
+		if v == item {
+			return i
+		}
+	}
+	return -1
+}
+
+func inStack(stack []string, name string) bool {
+	return indexOf(stack, name) >= 0
+}
+
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// parameterToken is a literal text, or a reference along with its text
+type parameterToken struct {
+	text      string
+	reference string
+}
+
+// parseParameterValue splits the value into literal text and %name% references
+func parseParameterValue(value string) []parameterToken {
+	var out []parameterToken
+	var literal strings.Builder
+	for i := 0; i < len(value); {
+		if value[i] != '%' {
+			literal.WriteByte(value[i])
+			i++
+			continue
+		}
+		if strings.HasPrefix(value[i:], "%%") {
+			literal.WriteString("%%")
+			i += 2
+			continue
+		}
+		end := strings.IndexByte(value[i+1:], '%')
+		if end < 0 || !parameterNamePattern.MatchString(value[i+1:i+1+end]) {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+		if literal.Len() > 0 {
+			out = append(out, parameterToken{text: literal.String()})
+			literal.Reset()
+		}
+		name := value[i+1 : i+1+end]
+		out = append(out, parameterToken{text: "%" + name + "%", reference: name})
+		i += end + 2
+	}
+	if literal.Len() > 0 {
+		out = append(out, parameterToken{text: literal.String()})
+	}
+	return out
+}
+
+// ParameterReferences returns the names of the parameters referenced by value, in order of appearance and without duplicates
+func ParameterReferences(value string) []string {
+	var out []string
+	for _, token := range parseParameterValue(value) {
+		if token.reference != "" && indexOf(out, token.reference) < 0 {
+			out = append(out, token.reference)
+		}
+	}
+	return out
+}
+
+// ParameterAnalysis holds the parameter references of a build configuration, along with the problems found in them
+type ParameterAnalysis struct {
+	// Resolver resolves the parameters available to the build configuration, including the ones of its dependencies
+	Resolver *ParameterResolver
+	// Usages are all the references found in the parameters, settings, steps, triggers, features, dependencies, agent requirements and VCS roots
+	Usages []*ParameterUsage
+	// Problems are the undefined references and reference cycles
+	Problems []*ParameterProblem
+}
+
+// UsagesOf returns the places referencing the parameter with given name
+func (a *ParameterAnalysis) UsagesOf(name string) []*ParameterUsage {
+	var out []*ParameterUsage
+	for _, u := range a.Usages {
+		if u.Reference == name {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// AnalyzeParameters retrieves the build configuration with given id, with the parameters it inherits from its projects and templates,
+// the parameters of its dependencies and its VCS roots, and checks every parameter reference they contain.
+func (s *BuildTypeService) AnalyzeParameters(id string) (*ParameterAnalysis, error) {
+	bt, err := s.getRaw(id)
+	if err != nil {
+		return nil, err
+	}
+	out := &ParameterAnalysis{Resolver: NewParameterResolver(bt.Parameters)}
+
+	var dependencies []*settingItem
+	if bt.SnapshotDependencies != nil {
+		dependencies = append(dependencies, bt.SnapshotDependencies.Items...)
+	}
+	if bt.ArtifactDependencies != nil {
+		dependencies = append(dependencies, bt.ArtifactDependencies.Items...)
+	}
+	for _, dep := range dependencies {
+		if dep.SourceBuildType == nil || out.Resolver.dependencies[dep.SourceBuildType.ID] != nil {
+			continue
+		}
+		source, err := s.getRaw(dep.SourceBuildType.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading dependency '%s' of build type '%s': %s", dep.SourceBuildType.ID, id, err)
+		}
+		out.Resolver.AddDependency(source.ID, source.Parameters)
+	}
+
+	for _, name := range out.Resolver.Names() {
+		out.addUsages(ParameterUsageParameter, name, "", out.Resolver.values[name])
+	}
+	out.Problems = out.Resolver.Check()
+
+	check := func(kind ParameterUsageKind, id string, property string, value string) {
+		out.addUsages(kind, id, property, value)
+		out.Problems = append(out.Problems, out.Resolver.CheckValue(kind, id, property, value)...)
+	}
+	if bt.Settings != nil {
+		for _, p := range bt.Settings.Items {
+			check(ParameterUsageSetting, p.Name, "", p.Value)
+		}
+	}
+	items := func(kind ParameterUsageKind, items []*settingItem) {
+		for _, item := range items {
+			if item.Properties == nil {
+				continue
+			}
+			for _, p := range item.Properties.Items {
+				check(kind, item.ID, p.Name, p.Value)
+			}
+		}
+	}
+	if bt.Steps != nil {
+		items(ParameterUsageStep, bt.Steps.Items)
+	}
+	if bt.Triggers != nil {
+		items(ParameterUsageTrigger, bt.Triggers.Items)
+	}
+	if bt.Features != nil {
+		items(ParameterUsageFeature, bt.Features.Items)
+	}
+	if bt.SnapshotDependencies != nil {
+		items(ParameterUsageSnapshotDependency, bt.SnapshotDependencies.Items)
+	}
+	if bt.ArtifactDependencies != nil {
+		items(ParameterUsageArtifactDependency, bt.ArtifactDependencies.Items)
+	}
+	if bt.AgentRequirements != nil {
+		items(ParameterUsageAgentRequirement, bt.AgentRequirements.Items)
+	}
+
+	if bt.Entries != nil {
+		for _, e := range bt.Entries.Items {
+			if e.VcsRoot == nil {
+				continue
+			}
+			var root vcsRootJSON
+			if err := s.commonHelper.get("vcs-roots/"+LocatorID(e.VcsRoot.ID).String(), &root, "vcs root"); err != nil {
+				return nil, fmt.Errorf("error reading VCS root '%s' of build type '%s': %s", e.VcsRoot.ID, id, err)
+			}
+			if root.Properties == nil {
+				continue
+			}
+			for _, p := range root.Properties.Items {
+				check(ParameterUsageVcsRoot, root.ID, p.Name, p.Value)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (a *ParameterAnalysis) addUsages(kind ParameterUsageKind, id string, property string, value string) {
+	for _, ref := range ParameterReferences(value) {
+		a.Usages = append(a.Usages, &ParameterUsage{Kind: kind, ID: id, Property: property, Reference: ref})
+	}
+}
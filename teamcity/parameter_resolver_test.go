@@ -0,0 +1,99 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParameterReferences(t *testing.T) {
+	assert.Equal(t, []string{"a", "env.B", "dep.Proj_Lib.version"}, ParameterReferences("%a%-%env.B% 100%% %not a ref% %a% %dep.Proj_Lib.version%"))
+	assert.Empty(t, ParameterReferences("50% done"))
+}
+
+func Test_ParameterResolverResolve(t *testing.T) {
+	project := NewParametersEmpty()
+	project.AddOrReplaceValue(ParameterTypes.Configuration, "version", "1.0")
+	project.AddOrReplaceValue(ParameterTypes.Configuration, "image", "app:%version%")
+	buildType := NewParametersEmpty()
+	buildType.AddOrReplaceValue(ParameterTypes.Configuration, "version", "%major%.1")
+	buildType.AddOrReplaceValue(ParameterTypes.Configuration, "major", "2")
+	buildType.AddOrReplaceValue(ParameterTypes.EnvironmentVariable, "IMAGE", "%image%")
+
+	sut := NewParameterResolver(project, buildType)
+	sut.AddDependency("Proj_Lib", NewParameters(&Parameter{Type: ParameterTypes.Configuration, Name: "tag", Value: "lib"}))
+
+	value, ok := sut.ResolveParameter("env.IMAGE")
+	assert.True(t, ok)
+	assert.Equal(t, "app:2.1", value)
+	assert.Equal(t, "lib-%build.number%-100%%", sut.Resolve("%dep.Proj_Lib.tag%-%build.number%-100%%"))
+	assert.Empty(t, sut.Check())
+}
+
+func Test_ParameterResolverCheck(t *testing.T) {
+	sut := NewParameterResolver()
+	sut.Set("a", "%b%")
+	sut.Set("b", "%c%/%missing%")
+	sut.Set("c", "%a%")
+	sut.Set("self", "x%self%")
+	sut.Set("dep", "%dep.Other.x% %dep.Known.build.number% %dep.Known.x%")
+	sut.AddDependency("Known", nil)
+
+	var problems []string
+	for _, p := range sut.Check() {
+		problems = append(problems, p.String())
+	}
+	assert.Equal(t, []string{
+		"parameter 'b': undefined parameter 'missing'",
+		"parameter 'dep': undefined parameter 'dep.Other.x'",
+		"parameter 'dep': undefined parameter 'dep.Known.x'",
+		"parameter 'a': reference cycle a -> b -> c -> a",
+		"parameter 'self': reference cycle self -> self",
+	}, problems)
+	assert.Equal(t, "x%self%", sut.Resolve("%self%"))
+}
+
+func Test_BuildTypeAnalyzeParameters(t *testing.T) {
+	require := require.New(t)
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build": `{"id":"Proj_Build",
+			"parameters":{"property":[{"name":"branch","value":"main","inherited":true},{"name":"env.TAG","value":"%dep.Proj_Lib.version%"}]},
+			"settings":{"property":[{"name":"artifactRules","value":"out/%branch% => out.zip"}]},
+			"steps":{"step":[{"id":"RUNNER_1","type":"simpleRunner","properties":{"property":[{"name":"script.content","value":"make TAG=%env.TAG% %typo%"}]}}]},
+			"features":{"feature":[{"id":"BUILD_EXT_1","type":"golang","properties":{"property":[{"name":"test.format","value":"json"}]}}]},
+			"snapshot-dependencies":{"snapshot-dependency":[{"id":"Proj_Lib","source-buildType":{"id":"Proj_Lib"}}]},
+			"vcs-root-entries":{"vcs-root-entry":[{"vcs-root":{"id":"Proj_Git"}}]}}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Lib": `{"id":"Proj_Lib","parameters":{"property":[{"name":"version","value":"%unknown.here%"}]}}`,
+		"/httpAuth/app/rest/vcs-roots/id%3AProj_Git":  `{"id":"Proj_Git","properties":{"property":[{"name":"branch","value":"refs/heads/%branch%"}]}}`,
+	})
+
+	actual, err := client.BuildTypes.AnalyzeParameters("Proj_Build")
+	require.NoError(err)
+	require.Equal([]*ParameterUsage{
+		{Kind: ParameterUsageSetting, ID: "artifactRules", Reference: "branch"},
+		{Kind: ParameterUsageVcsRoot, ID: "Proj_Git", Property: "branch", Reference: "branch"},
+	}, actual.UsagesOf("branch"))
+	require.Len(actual.UsagesOf("env.TAG"), 1)
+	require.Len(actual.Problems, 1)
+	require.Equal("step 'RUNNER_1' property 'script.content': undefined parameter 'typo'", actual.Problems[0].String())
+
+	tag, _ := actual.Resolver.ResolveParameter("env.TAG")
+	require.Equal("%unknown.here%", tag)
+}
+
+func Test_IsPredefinedParameter(t *testing.T) {
+	assert.True(t, IsPredefinedParameter("build.number"))
+	assert.True(t, IsPredefinedParameter("env.BUILD_NUMBER"))
+	assert.True(t, IsPredefinedParameter("env.BUILD_VCS_NUMBER_Proj_Git"))
+	assert.False(t, IsPredefinedParameter("env.DEPLOY_TOKEN"))
+
+	sut := NewParameterResolver()
+	sut.Set("script", "%env.DEPLOY_TOKEN% %env.PATH%")
+	require.Len(t, sut.Check(), 2)
+
+	sut.Predefined = func(name string) bool { return name == "env.PATH" || IsPredefinedParameter(name) }
+	problems := sut.Check()
+	require.Len(t, problems, 1)
+	assert.Equal(t, "parameter 'script': undefined parameter 'env.DEPLOY_TOKEN'", problems[0].String())
+}
@@ -0,0 +1,83 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dghubble/sling"
+)
+
+// ParameterService provides operations for managing the parameters of a project or build configuration one at a time,
+// without updating the whole project or build configuration.
+type ParameterService struct {
+	// OwnerDescription is the project or build configuration of the parameters, such as "project 'Proj'"
+	OwnerDescription string
+	httpClient       *http.Client
+	base             *sling.Sling
+	restHelper       *restHelper
+}
+
+func newParameterService(ownerPath string, ownerDescription string, c *http.Client, base *sling.Sling) *ParameterService {
+	sling := base.New().Path(ownerPath + "/parameters/")
+	return &ParameterService{
+		OwnerDescription: ownerDescription,
+		httpClient:       c,
+		base:             sling,
+		restHelper:       newRestHelper(c, sling),
+	}
+}
+
+// GetAll returns the parameters defined in the project or build configuration, and the ones it inherits, with Inherited set.
+// Use Parameters.NonInherited to get its own parameters only.
+func (s *ParameterService) GetAll() (*Parameters, error) {
+	var out Parameters
+	if err := s.restHelper.get("", &out, "parameters of "+s.OwnerDescription); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetByName returns the parameter with given type and name, which may be inherited
+func (s *ParameterService) GetByName(t string, name string) (*Parameter, error) {
+	var out Parameter
+	if err := s.restHelper.get(parameterPath(t, name), &out, fmt.Sprintf("parameter '%s' of %s", name, s.OwnerDescription)); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Set creates or updates a parameter, including its specification, and returns it as stored.
+// Setting an inherited parameter overrides its value in this project or build configuration.
+// Since the server never returns the values of password parameters, only the specification of an existing password parameter is updated if it has no value,
+// and an error is returned for an inherited one, whose value would be overridden by an empty one.
+func (s *ParameterService) Set(p *Parameter) (*Parameter, error) {
+	if p == nil {
+		return nil, fmt.Errorf("p can't be nil")
+	}
+	if p.Spec != nil {
+		if err := p.Spec.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var existing *Parameter
+	if p.IsPassword() && p.Value == "" {
+		var err error
+		existing, err = s.GetByName(p.Type, p.Name)
+		if err != nil && !isNotFoundError(err) {
+			return nil, err
+		}
+	}
+	return putParameter(s.restHelper, parameterPath(p.Type, p.Name), p, existing, s.OwnerDescription)
+}
+
+// Delete removes a parameter defined in the project or build configuration.
+// Deleting a parameter overriding an inherited one restores the inherited value.
+func (s *ParameterService) Delete(t string, name string) error {
+	return s.restHelper.delete(parameterPath(t, name), fmt.Sprintf("parameter '%s' of %s", name, s.OwnerDescription))
+}
+
+// parameterPath returns the path of a parameter, relative to the parameters of its project or build configuration
+func parameterPath(t string, name string) string {
+	return url.PathEscape(paramPrefixByType[t] + name)
+}
@@ -0,0 +1,64 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParameterService(t *testing.T) {
+	require := require.New(t)
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters": `{"count":2,"property":[
+			{"name":"version","value":"1.0"},
+			{"name":"env.REGION","value":"eu","inherited":true}]}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/env.REGION":     `{"name":"env.REGION","value":"eu","inherited":true}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/env.TOKEN":      `{"name":"env.TOKEN","value":"","type":{"rawValue":"password"}}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/env.DEPLOY_KEY": `{"name":"env.DEPLOY_KEY","value":"","inherited":true,"type":{"rawValue":"password"}}`,
+	})
+	sut := client.BuildTypeParameterService("Proj_Build")
+
+	all, err := sut.GetAll()
+	require.NoError(err)
+	require.Equal(int32(2), all.Count)
+	own := all.NonInherited()
+	require.Len(own.Items, 1)
+	assert.Equal(t, "version", own.Items[0].Name)
+
+	region, err := sut.GetByName(ParameterTypes.EnvironmentVariable, "REGION")
+	require.NoError(err)
+	assert.True(t, region.Inherited)
+	assert.Equal(t, "eu", region.Value)
+
+	_, err = sut.GetByName(ParameterTypes.Configuration, "missing")
+	assert.Error(t, err)
+
+	config, err := NewParameterWithSpec(ParameterTypes.System, "config", "debug", &ParameterSpec{
+		Type:    ParameterSpecSelect,
+		Options: []ParameterSpecOption{{Value: "debug"}, {Value: "release"}},
+	})
+	require.NoError(err)
+	actual, err := sut.Set(config)
+	require.NoError(err)
+	assert.Equal(t, config, actual)
+
+	token, err := NewParameterWithSpec(ParameterTypes.EnvironmentVariable, "TOKEN", "", &ParameterSpec{Type: ParameterSpecPassword, Display: ParameterDisplayHidden})
+	require.NoError(err)
+	_, err = sut.Set(token)
+	require.NoError(err)
+
+	// Setting an inherited password without a value would override the inherited secret
+	deployKey, err := NewParameterWithSpec(ParameterTypes.EnvironmentVariable, "DEPLOY_KEY", "", &ParameterSpec{Type: ParameterSpecPassword})
+	require.NoError(err)
+	_, err = sut.Set(deployKey)
+	assert.EqualError(t, err, "cannot set inherited password parameter 'DEPLOY_KEY' of build type 'Proj_Build' without a value, as it would override the inherited value")
+
+	require.NoError(sut.Delete(ParameterTypes.Configuration, "version"))
+
+	assert.Equal(t, []recordedRequest{
+		{Method: "PUT", Path: "/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/system.config", Body: `{"name":"system.config","type":{"rawValue":"select data_1='debug' data_2='release'"},"value":"debug"}` + "\n"},
+		{Method: "PUT", Path: "/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/env.TOKEN/type", Body: `{"rawValue":"password display='hidden'"}` + "\n"},
+		{Method: "DELETE", Path: "/httpAuth/app/rest/buildTypes/id%3AProj_Build/parameters/version"},
+	}, fake.requests)
+}
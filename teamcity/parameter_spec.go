@@ -0,0 +1,254 @@
+package teamcity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParameterSpecType is the control used to edit a parameter with a ParameterSpec
+type ParameterSpecType = string
+
+const (
+	// ParameterSpecText is a text field, optionally validated
+	ParameterSpecText ParameterSpecType = "text"
+	// ParameterSpecPassword is a password field. The server never returns the values of password parameters.
+	ParameterSpecPassword ParameterSpecType = "password"
+	// ParameterSpecCheckbox is a checkbox, with a value for each state
+	ParameterSpecCheckbox ParameterSpecType = "checkbox"
+	// ParameterSpecSelect is a list of options
+	ParameterSpecSelect ParameterSpecType = "select"
+)
+
+// ParameterDisplay is how a parameter is displayed in the dialog to run a custom build
+type ParameterDisplay = string
+
+const (
+	// ParameterDisplayNormal displays the parameter, with its current value
+	ParameterDisplayNormal ParameterDisplay = "normal"
+	// ParameterDisplayHidden does not display the parameter
+	ParameterDisplayHidden ParameterDisplay = "hidden"
+	// ParameterDisplayPrompt displays the parameter and requires a value before running any build
+	ParameterDisplayPrompt ParameterDisplay = "prompt"
+)
+
+// ParameterValidationMode is how the value of a text parameter is validated
+type ParameterValidationMode = string
+
+const (
+	// ParameterValidationAny accepts any value
+	ParameterValidationAny ParameterValidationMode = "any"
+	// ParameterValidationNotEmpty requires a value
+	ParameterValidationNotEmpty ParameterValidationMode = "not_empty"
+	// ParameterValidationRegex requires a value matching a Java regular expression
+	ParameterValidationRegex ParameterValidationMode = "regex"
+)
+
+// ParameterSpecOption is an option of a select parameter
+type ParameterSpecOption struct {
+	// Label is displayed instead of Value if not empty
+	Label string
+	Value string
+}
+
+// ParameterSpec represents the specification of a parameter, which is how it is edited and validated.
+// It is the rawValue of the parameter type, such as "text label='Version' validationMode='regex' regexp='\d+'".
+type ParameterSpec struct {
+	Type ParameterSpecType
+	// Label is displayed instead of the parameter name
+	Label string
+	// Description is displayed below the parameter
+	Description string
+	// Display defaults to ParameterDisplayNormal
+	Display  ParameterDisplay
+	ReadOnly bool
+
+	// ValidationMode of text parameters
+	ValidationMode ParameterValidationMode
+	// Regex the value of text parameters must match if ValidationMode is ParameterValidationRegex
+	Regex string
+	// ValidationMessage is displayed when the value of a text parameter is not valid
+	ValidationMessage string
+
+	// CheckedValue is the value of checkbox parameters when checked
+	CheckedValue string
+	// UncheckedValue is the value of checkbox parameters when unchecked
+	UncheckedValue string
+
+	// Options of select parameters
+	Options []ParameterSpecOption
+	// Multiple allows selecting several options, joined by ValueSeparator
+	Multiple       bool
+	ValueSeparator string
+
+	// Attributes holds the other attributes of the specification, kept as is
+	Attributes map[string]string
+}
+
+// ParseParameterSpec parses the raw specification of a parameter, as returned in the rawValue of its type
+func ParseParameterSpec(raw string) (*ParameterSpec, error) {
+	raw = strings.TrimSpace(raw)
+	typeEnd := strings.IndexByte(raw, ' ')
+	if typeEnd < 0 {
+		typeEnd = len(raw)
+	}
+	out := &ParameterSpec{Type: raw[:typeEnd]}
+	if out.Type == "" {
+		return nil, fmt.Errorf("parameter specification has no type")
+	}
+
+	options := make(map[int]*ParameterSpecOption)
+	option := func(key string, prefix string) (*ParameterSpecOption, bool) {
+		index, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if !strings.HasPrefix(key, prefix) || err != nil {
+			return nil, false
+		}
+		if _, ok := options[index]; !ok {
+			options[index] = &ParameterSpecOption{}
+		}
+		return options[index], true
+	}
+
+	rest := strings.TrimLeft(raw[typeEnd:], " ")
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid attribute in parameter specification: %q", raw)
+		}
+		key := strings.TrimSpace(rest[:eq])
+		value, remaining, err := readServiceMessageValue(rest[eq+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of attribute '%s' in parameter specification: %q", key, raw)
+		}
+		rest = strings.TrimLeft(remaining, " ")
+
+		switch key {
+		case "label":
+			out.Label = value
+		case "description":
+			out.Description = value
+		case "display":
+			out.Display = value
+		case "readOnly":
+			out.ReadOnly = value == "true"
+		case "validationMode":
+			out.ValidationMode = value
+		case "regexp":
+			out.Regex = value
+		case "validationMessage":
+			out.ValidationMessage = value
+		case "checkedValue":
+			out.CheckedValue = value
+		case "uncheckedValue":
+			out.UncheckedValue = value
+		case "multiple":
+			out.Multiple = value == "true"
+		case "valueSeparator":
+			out.ValueSeparator = value
+		default:
+			if o, ok := option(key, "data_"); ok {
+				o.Value = value
+			} else if o, ok := option(key, "label_"); ok {
+				o.Label = value
+			} else {
+				if out.Attributes == nil {
+					out.Attributes = make(map[string]string)
+				}
+				out.Attributes[key] = value
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(options))
+	for i := range options {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		out.Options = append(out.Options, *options[i])
+	}
+	return out, nil
+}
+
+// String returns the raw specification, to be sent as the rawValue of the parameter type
+func (s *ParameterSpec) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.Type)
+	attr := func(key string, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, " %s='%s'", key, escapeParameterSpecValue(value))
+		}
+	}
+
+	attr("label", s.Label)
+	attr("description", s.Description)
+	attr("display", s.Display)
+	if s.ReadOnly {
+		attr("readOnly", "true")
+	}
+	attr("validationMode", s.ValidationMode)
+	attr("regexp", s.Regex)
+	attr("validationMessage", s.ValidationMessage)
+	attr("checkedValue", s.CheckedValue)
+	attr("uncheckedValue", s.UncheckedValue)
+	for i, o := range s.Options {
+		attr(fmt.Sprintf("label_%d", i+1), o.Label)
+		attr(fmt.Sprintf("data_%d", i+1), o.Value)
+	}
+	if s.Multiple {
+		attr("multiple", "true")
+	}
+	attr("valueSeparator", s.ValueSeparator)
+
+	keys := make([]string, 0, len(s.Attributes))
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attr(k, s.Attributes[k])
+	}
+	return sb.String()
+}
+
+// Validate returns an error if the specification is not accepted by the server
+func (s *ParameterSpec) Validate() error {
+	if s.Type == "" {
+		return fmt.Errorf("Type is required")
+	}
+	if s.Display != "" && !containsString([]string{ParameterDisplayNormal, ParameterDisplayHidden, ParameterDisplayPrompt}, s.Display) {
+		return fmt.Errorf("invalid Display '%s'", s.Display)
+	}
+	switch s.Type {
+	case ParameterSpecText:
+		if s.ValidationMode != "" && !containsString([]string{ParameterValidationAny, ParameterValidationNotEmpty, ParameterValidationRegex}, s.ValidationMode) {
+			return fmt.Errorf("invalid ValidationMode '%s'", s.ValidationMode)
+		}
+		if s.ValidationMode == ParameterValidationRegex && s.Regex == "" {
+			return fmt.Errorf("Regex is required for ValidationMode '%s'", s.ValidationMode)
+		}
+	case ParameterSpecSelect:
+		if len(s.Options) == 0 {
+			return fmt.Errorf("Options are required for Type '%s'", s.Type)
+		}
+		for i, o := range s.Options {
+			if o.Value == "" {
+				return fmt.Errorf("Value of option %d is required", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// escapeParameterSpecValue escapes a value with the '|' escape sequences used by TeamCity
+func escapeParameterSpecValue(v string) string {
+	return strings.NewReplacer(
+		"|", "||",
+		"'", "|'",
+		"\n", "|n",
+		"\r", "|r",
+		"[", "|[",
+		"]", "|]",
+	).Replace(v)
+}
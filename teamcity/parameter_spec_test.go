@@ -0,0 +1,130 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseParameterSpec(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected ParameterSpec
+	}{
+		{
+			"password display='hidden'",
+			ParameterSpec{Type: ParameterSpecPassword, Display: ParameterDisplayHidden},
+		},
+		{
+			"checkbox label='Deploy' checkedValue='true' uncheckedValue='false'",
+			ParameterSpec{Type: ParameterSpecCheckbox, Label: "Deploy", CheckedValue: "true", UncheckedValue: "false"},
+		},
+		{
+			"select display='prompt' label_1='Debug' data_1='debug' data_2='release' multiple='true' valueSeparator=','",
+			ParameterSpec{
+				Type:           ParameterSpecSelect,
+				Display:        ParameterDisplayPrompt,
+				Options:        []ParameterSpecOption{{Label: "Debug", Value: "debug"}, {Value: "release"}},
+				Multiple:       true,
+				ValueSeparator: ",",
+			},
+		},
+		{
+			`text description='Semantic |'version|'' validationMode='regex' regexp='^\d+\.\d+$' validationMessage='Use |[major|].|[minor|]' readOnly='true' custom='x'`,
+			ParameterSpec{
+				Type:              ParameterSpecText,
+				Description:       "Semantic 'version'",
+				ReadOnly:          true,
+				ValidationMode:    ParameterValidationRegex,
+				Regex:             `^\d+\.\d+$`,
+				ValidationMessage: "Use [major].[minor]",
+				Attributes:        map[string]string{"custom": "x"},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual, err := ParseParameterSpec(c.raw)
+		require.NoError(t, err, c.raw)
+		assert.Equal(t, c.expected, *actual, c.raw)
+
+		again, err := ParseParameterSpec(actual.String())
+		require.NoError(t, err, actual.String())
+		assert.Equal(t, actual, again)
+	}
+
+	_, err := ParseParameterSpec("text label='unterminated")
+	assert.EqualError(t, err, `invalid value of attribute 'label' in parameter specification: "text label='unterminated"`)
+	_, err = ParseParameterSpec("")
+	assert.EqualError(t, err, "parameter specification has no type")
+}
+
+func Test_ParameterSpecString(t *testing.T) {
+	sut := ParameterSpec{
+		Type:              ParameterSpecText,
+		Label:             "Version",
+		ValidationMode:    ParameterValidationRegex,
+		Regex:             `\d+`,
+		ValidationMessage: "Digits|only",
+	}
+	assert.Equal(t, `text label='Version' validationMode='regex' regexp='\d+' validationMessage='Digits||only'`, sut.String())
+}
+
+func Test_ParameterSpecValidate(t *testing.T) {
+	_, err := NewParameterWithSpec(ParameterTypes.Configuration, "version", "", &ParameterSpec{Type: ParameterSpecText, ValidationMode: ParameterValidationRegex})
+	assert.EqualError(t, err, "Regex is required for ValidationMode 'regex'")
+	_, err = NewParameterWithSpec(ParameterTypes.Configuration, "config", "", &ParameterSpec{Type: ParameterSpecSelect})
+	assert.EqualError(t, err, "Options are required for Type 'select'")
+	_, err = NewParameterWithSpec(ParameterTypes.Configuration, "config", "", &ParameterSpec{Type: ParameterSpecText, Display: "visible"})
+	assert.EqualError(t, err, "invalid Display 'visible'")
+}
+
+func Test_ParameterSpecSerialization(t *testing.T) {
+	var actual Parameter
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"env.TOKEN","value":"","type":{"rawValue":"password display='hidden'"}}`), &actual))
+	assert.True(t, actual.IsPassword())
+	assert.Equal(t, ParameterTypes.EnvironmentVariable, actual.Type)
+
+	dt, err := json.Marshal(&actual)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"env.TOKEN","type":{"rawValue":"password display='hidden'"},"value":""}`, string(dt))
+
+	params := NewParametersEmpty()
+	params.AddOrReplaceParameter(&actual)
+	assert.Equal(t, actual.Spec, params.Items[0].Spec)
+}
+
+func Test_ProjectUpdateKeepsPasswordValues(t *testing.T) {
+	require := require.New(t)
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/projects/uuid%3Au1": `{"id":"Proj","name":"Proj","uuid":"u1","parentProjectId":"_Root","parameters":{"property":[]}}`,
+		"/httpAuth/app/rest/projects/Proj/parameters": `{"property":[
+			{"name":"env.TOKEN","value":"","type":{"rawValue":"password"}},
+			{"name":"stale","value":"x"},
+			{"name":"inherited","value":"x","inherited":true},
+			{"name":"env.DEPLOY_KEY","value":"","inherited":true,"type":{"rawValue":"password"}}]}`,
+	})
+
+	token, err := NewParameterWithSpec(ParameterTypes.EnvironmentVariable, "TOKEN", "", &ParameterSpec{Type: ParameterSpecPassword, Display: ParameterDisplayHidden})
+	require.NoError(err)
+	version, err := NewParameter(ParameterTypes.Configuration, "version", "1.0")
+	require.NoError(err)
+	// Inherited parameters read back from the server are not turned into own ones
+	inherited := &Parameter{Type: ParameterTypes.Configuration, Name: "inherited", Value: "x", Inherited: true}
+	project := &Project{ID: "Proj", Name: "Proj", UUID: "u1", ParentProjectID: "_Root", Parameters: NewParameters(token, version, inherited)}
+
+	_, err = client.Projects.Update(project)
+	require.NoError(err)
+	assert.Equal(t, []recordedRequest{
+		{Method: "PUT", Path: "/httpAuth/app/rest/projects/Proj/parameters/env.TOKEN/type", Body: `{"rawValue":"password display='hidden'"}` + "\n"},
+		{Method: "PUT", Path: "/httpAuth/app/rest/projects/Proj/parameters/version", Body: `{"name":"version","value":"1.0"}` + "\n"},
+		{Method: "DELETE", Path: "/httpAuth/app/rest/projects/Proj/parameters/stale"},
+	}, fake.requests)
+
+	deployKey, err := NewParameterWithSpec(ParameterTypes.EnvironmentVariable, "DEPLOY_KEY", "", &ParameterSpec{Type: ParameterSpecPassword})
+	require.NoError(err)
+	project.Parameters = NewParameters(token, deployKey)
+	_, err = client.Projects.Update(project)
+	assert.EqualError(t, err, "cannot set inherited password parameter 'DEPLOY_KEY' of project 'Proj' without a value, as it would override the inherited value")
+}
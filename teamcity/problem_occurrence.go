@@ -0,0 +1,141 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dghubble/sling"
+)
+
+// ProblemReference identifies a build problem across builds
+type ProblemReference struct {
+	ID       string `json:"id,omitempty" xml:"id"`
+	Type     string `json:"type,omitempty" xml:"type"`
+	Identity string `json:"identity,omitempty" xml:"identity"`
+	Href     string `json:"href,omitempty" xml:"href"`
+}
+
+// ProblemOccurrence is a build problem reported for a build, such as a failed step or a non-zero exit code
+type ProblemOccurrence struct {
+	ID string `json:"id,omitempty" xml:"id"`
+	// Type is the problem type, for instance "TC_EXIT_CODE", "TC_FAILED_TESTS" or "TC_COMPILATION_ERROR"
+	Type           string `json:"type,omitempty" xml:"type"`
+	Identity       string `json:"identity,omitempty" xml:"identity"`
+	Details        string `json:"details,omitempty" xml:"details"`
+	AdditionalData string `json:"additionalData,omitempty" xml:"additionalData"`
+
+	Muted                 bool `json:"muted,omitempty" xml:"muted"`
+	CurrentlyMuted        bool `json:"currentlyMuted,omitempty" xml:"currentlyMuted"`
+	CurrentlyInvestigated bool `json:"currentlyInvestigated,omitempty" xml:"currentlyInvestigated"`
+	NewFailure            bool `json:"newFailure,omitempty" xml:"newFailure"`
+
+	Href    string            `json:"href,omitempty" xml:"href"`
+	Build   *BuildReference   `json:"build,omitempty"`
+	Problem *ProblemReference `json:"problem,omitempty"`
+}
+
+type problemOccurrencesJSON struct {
+	Count int32                `json:"count,omitempty" xml:"count"`
+	Href  string               `json:"href,omitempty" xml:"href"`
+	Items []*ProblemOccurrence `json:"problemOccurrence"`
+}
+
+// ProblemOccurrenceLocator filters build problem occurrences. At least one of BuildID, ProblemID or CurrentlyFailing must be set.
+// Zero values are omitted from the locator.
+type ProblemOccurrenceLocator struct {
+	BuildID   int
+	ProblemID string
+	// ProjectID returns occurrences affecting the project and its subprojects. Use with CurrentlyFailing or CurrentlyMuted.
+	ProjectID string
+
+	CurrentlyFailing      *bool
+	CurrentlyInvestigated *bool
+	CurrentlyMuted        *bool
+	Muted                 *bool
+
+	// Count limits the number of occurrences returned. If zero, all occurrences are returned.
+	Count int
+	Start int
+}
+
+func (l ProblemOccurrenceLocator) dimensions() locatorDimensions {
+	var d locatorDimensions
+	if l.BuildID != 0 {
+		d.add("build", fmt.Sprintf("(id:%d)", l.BuildID))
+	}
+	if l.ProblemID != "" {
+		d.add("problem", fmt.Sprintf("(id:%s)", l.ProblemID))
+	}
+	if l.ProjectID != "" {
+		d.add("affectedProject", fmt.Sprintf("(id:%s)", l.ProjectID))
+	}
+	d.addBool("currentlyFailing", l.CurrentlyFailing)
+	d.addBool("currentlyInvestigated", l.CurrentlyInvestigated)
+	d.addBool("currentlyMuted", l.CurrentlyMuted)
+	d.addBool("muted", l.Muted)
+	d.addInt("count", l.Count)
+	d.addInt("start", l.Start)
+	return d
+}
+
+// String returns the unescaped locator, for instance "build:(id:1)"
+func (l ProblemOccurrenceLocator) String() string {
+	return l.dimensions().String()
+}
+
+// ProblemOccurrenceService has operations for querying build problems
+type ProblemOccurrenceService struct {
+	sling      *sling.Sling
+	httpClient *http.Client
+	restHelper *restHelper
+}
+
+func newProblemOccurrenceService(base *sling.Sling, httpClient *http.Client) *ProblemOccurrenceService {
+	sling := base.Path("problemOccurrences/")
+	return &ProblemOccurrenceService{
+		sling:      sling,
+		httpClient: httpClient,
+		restHelper: newRestHelper(httpClient, sling),
+	}
+}
+
+func (s *ProblemOccurrenceService) fields() getFields {
+	return getFields{
+		Fields: fmt.Sprintf("count,href,problemOccurrence(id,type,identity,details,additionalData,muted,currentlyMuted,currentlyInvestigated,newFailure,href,build(%s),problem(id,type,identity,href))", buildReferenceFields),
+	}
+}
+
+// List returns the build problem occurrences matching the locator, requesting further pages until all are returned unless locator.Count is set.
+func (s *ProblemOccurrenceService) List(locator ProblemOccurrenceLocator) ([]*ProblemOccurrence, error) {
+	if locator.Count > 0 {
+		return s.page(locator)
+	}
+
+	var out []*ProblemOccurrence
+	locator.Count = listPageSize
+	for {
+		page, err := s.page(locator)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < listPageSize {
+			return out, nil
+		}
+		locator.Start += listPageSize
+	}
+}
+
+func (s *ProblemOccurrenceService) page(locator ProblemOccurrenceLocator) ([]*ProblemOccurrence, error) {
+	var out problemOccurrencesJSON
+	err := s.restHelper.getWithFields(locator.dimensions().query(), s.fields(), &out, "problem occurrences")
+	if err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// ListByBuild returns all build problems reported for the build with given id
+func (s *ProblemOccurrenceService) ListByBuild(buildID int) ([]*ProblemOccurrence, error) {
+	return s.List(ProblemOccurrenceLocator{BuildID: buildID})
+}
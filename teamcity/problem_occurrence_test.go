@@ -0,0 +1,55 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProblemOccurrenceLocator(t *testing.T) {
+	assert.Equal(t, "build:(id:42)", ProblemOccurrenceLocator{BuildID: 42}.String())
+	assert.Equal(t, "affectedProject:(id:Root),currentlyFailing:true,count:10",
+		ProblemOccurrenceLocator{ProjectID: "Root", CurrentlyFailing: NewTrue(), Count: 10}.String())
+}
+
+func Test_ProblemOccurrenceDeserialize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var sut problemOccurrencesJSON
+	require.NoError(json.Unmarshal([]byte(problemOccurrencesJSONFixture), &sut))
+	require.Len(sut.Items, 1)
+
+	actual := sut.Items[0]
+	assert.Equal("TC_EXIT_CODE", actual.Type)
+	assert.Equal("Process exited with code 1", actual.Details)
+	assert.True(actual.CurrentlyInvestigated)
+	assert.Equal(42, actual.Build.ID)
+	assert.Equal("-1234", actual.Problem.ID)
+}
+
+const problemOccurrencesJSONFixture = `
+{
+	"count": 1,
+	"problemOccurrence": [
+		{
+			"id": "problem:(id:-1234),build:(id:42)",
+			"type": "TC_EXIT_CODE",
+			"identity": "simpleRunner1",
+			"details": "Process exited with code 1",
+			"currentlyInvestigated": true,
+			"build": {
+				"id": 42,
+				"buildTypeId": "Bt1"
+			},
+			"problem": {
+				"id": "-1234",
+				"type": "TC_EXIT_CODE",
+				"identity": "simpleRunner1"
+			}
+		}
+	]
+}
+`
@@ -0,0 +1,134 @@
+package teamcity
+
+import (
+	"errors"
+	"time"
+)
+
+// UserReference contains basic information about a TeamCity user
+type UserReference struct {
+	ID       int    `json:"id,omitempty" xml:"id"`
+	Username string `json:"username,omitempty" xml:"username"`
+	Name     string `json:"name,omitempty" xml:"name"`
+	Href     string `json:"href,omitempty" xml:"href"`
+}
+
+// ResolutionType represents when an investigation or mute is automatically removed
+type ResolutionType string
+
+const (
+	// ResolutionWhenFixed removes the investigation or mute once the problem is fixed
+	ResolutionWhenFixed ResolutionType = "whenFixed"
+	// ResolutionManually keeps the investigation or mute until it is removed by a user
+	ResolutionManually ResolutionType = "manually"
+	// ResolutionAtTime removes the investigation or mute at a specific time
+	ResolutionAtTime ResolutionType = "atTime"
+)
+
+// Resolution describes when an investigation or mute is removed. Time is only set for ResolutionAtTime, in TimeLayout format.
+type Resolution struct {
+	Type ResolutionType `json:"type,omitempty" xml:"type"`
+	Time string         `json:"time,omitempty" xml:"time"`
+}
+
+// NewResolutionWhenFixed returns a Resolution that is applied once the problem is fixed
+func NewResolutionWhenFixed() *Resolution {
+	return &Resolution{Type: ResolutionWhenFixed}
+}
+
+// NewResolutionManually returns a Resolution that requires manual removal
+func NewResolutionManually() *Resolution {
+	return &Resolution{Type: ResolutionManually}
+}
+
+// NewResolutionAtTime returns a Resolution that expires at the given time
+func NewResolutionAtTime(t time.Time) *Resolution {
+	return &Resolution{Type: ResolutionAtTime, Time: t.Format(TimeLayout)}
+}
+
+// Assignment holds the comment and author of an investigation or mute
+type Assignment struct {
+	User      *UserReference `json:"user,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty" xml:"timestamp"`
+	Text      string         `json:"text,omitempty" xml:"text"`
+}
+
+// ProblemScope restricts an investigation or mute to a project or a set of build configurations
+type ProblemScope struct {
+	Project    *ProjectReference    `json:"project,omitempty"`
+	BuildTypes *BuildTypeReferences `json:"buildTypes,omitempty"`
+}
+
+// NewProjectScope returns a ProblemScope for the project with given id, including its subprojects
+func NewProjectScope(projectID string) *ProblemScope {
+	return &ProblemScope{Project: &ProjectReference{ID: projectID}}
+}
+
+// NewBuildTypeScope returns a ProblemScope for the build configurations with given ids
+func NewBuildTypeScope(buildTypeIDs ...string) *ProblemScope {
+	refs := make([]*BuildTypeReference, len(buildTypeIDs))
+	for i, id := range buildTypeIDs {
+		refs[i] = &BuildTypeReference{ID: id}
+	}
+	return &ProblemScope{
+		BuildTypes: &BuildTypeReferences{Count: int32(len(refs)), Items: refs},
+	}
+}
+
+// TestReferences represents a collection of *TestReference
+type TestReferences struct {
+	Count int32            `json:"count,omitempty" xml:"count"`
+	Items []*TestReference `json:"test"`
+}
+
+// ProblemReferences represents a collection of *ProblemReference
+type ProblemReferences struct {
+	Count int32               `json:"count,omitempty" xml:"count"`
+	Items []*ProblemReference `json:"problem"`
+}
+
+// ProblemTarget is what an investigation or mute applies to: any problem of a build configuration, specific tests or specific build problems
+type ProblemTarget struct {
+	AnyProblem bool               `json:"anyProblem,omitempty" xml:"anyProblem"`
+	Tests      *TestReferences    `json:"tests,omitempty"`
+	Problems   *ProblemReferences `json:"problems,omitempty"`
+}
+
+// NewAnyProblemTarget returns a ProblemTarget for all problems of the build configurations in scope. It can only be used for investigations.
+func NewAnyProblemTarget() *ProblemTarget {
+	return &ProblemTarget{AnyProblem: true}
+}
+
+// NewTestTarget returns a ProblemTarget for the tests with given names
+func NewTestTarget(testNames ...string) *ProblemTarget {
+	refs := make([]*TestReference, len(testNames))
+	for i, name := range testNames {
+		refs[i] = &TestReference{Name: name}
+	}
+	return &ProblemTarget{Tests: &TestReferences{Count: int32(len(refs)), Items: refs}}
+}
+
+// NewProblemTarget returns a ProblemTarget for the build problems with given ids
+func NewProblemTarget(problemIDs ...string) *ProblemTarget {
+	refs := make([]*ProblemReference, len(problemIDs))
+	for i, id := range problemIDs {
+		refs[i] = &ProblemReference{ID: id}
+	}
+	return &ProblemTarget{Problems: &ProblemReferences{Count: int32(len(refs)), Items: refs}}
+}
+
+func validateProblemScope(scope *ProblemScope, target *ProblemTarget, resolution *Resolution) error {
+	if scope == nil || (scope.Project == nil && scope.BuildTypes == nil) {
+		return errors.New("scope is required")
+	}
+	if target == nil || (!target.AnyProblem && target.Tests == nil && target.Problems == nil) {
+		return errors.New("target is required")
+	}
+	if resolution == nil || resolution.Type == "" {
+		return errors.New("resolution is required")
+	}
+	if resolution.Type == ResolutionAtTime && resolution.Time == "" {
+		return errors.New("resolution time is required for 'atTime' resolution")
+	}
+	return nil
+}
@@ -37,6 +37,8 @@ type ProjectService struct {
 	sling      *sling.Sling
 	httpClient *http.Client
 	restHelper *restHelper
+	// commonHelper lists the subprojects, build types and VCS roots of a project tree, which are not under projects/, when checking a move
+	commonHelper *restHelper
 }
 
 // NewProject returns an instance of a Project. A non-empty name is required.
@@ -85,11 +87,13 @@ func (p *Project) Locator() Locator {
 }
 
 func newProjectService(base *sling.Sling, client *http.Client) *ProjectService {
+	common := newRestHelper(client, base.New())
 	sling := base.Path("projects/")
 	return &ProjectService{
-		sling:      sling,
-		httpClient: client,
-		restHelper: newRestHelper(client, sling),
+		sling:        sling,
+		httpClient:   client,
+		restHelper:   newRestHelper(client, sling),
+		commonHelper: common,
 	}
 }
 
@@ -211,8 +215,7 @@ func (s *ProjectService) updateProject(locator Locator, project *Project, isCrea
 
 	//Update Parameters
 	if project.Parameters.Count > 0 {
-		var parameters *Parameters
-		err = s.restHelper.put(project.ID+"/parameters", project.Parameters, &parameters, "project parameters")
+		err = putParameters(s.restHelper, project.ID+"/parameters", project.Parameters, fmt.Sprintf("project '%s'", project.ID))
 		if err != nil {
 			return nil, err
 		}
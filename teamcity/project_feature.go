@@ -8,6 +8,7 @@ import (
 )
 
 // The ProjectFeature interface represents the different types of features that can be added to a project.
+// Features of other types can be read by registering them with RegisterProjectFeatureType.
 type ProjectFeature interface {
 	ID() string
 	SetID(value string)
@@ -151,10 +152,5 @@ func (s *ProjectFeatureService) Update(feature ProjectFeature) (ProjectFeature,
 }
 
 func (s *ProjectFeatureService) parseProjectFeatureJSONResponse(feature projectFeatureJSON) (ProjectFeature, error) {
-	switch feature.Type {
-	case "versionedSettings":
-		return loadProjectFeatureVersionedSettings(s.ProjectID, feature)
-	default:
-		return nil, fmt.Errorf("Unknown project feature type %q", feature.Type)
-	}
+	return decodeProjectFeature(s.ProjectID, feature)
 }
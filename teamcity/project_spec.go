@@ -0,0 +1,89 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProjectSpec describes the desired state of a project, its VCS roots, build configurations and subprojects.
+// It is the input to Reconciler.Plan. Resources are matched with the server by their IDs, which are therefore required.
+type ProjectSpec struct {
+	// Project holds the ID, name, description, parent and parameters of the project
+	Project *Project
+	// VcsRoots are the VCS roots defined in the project
+	VcsRoots []VcsRoot
+	// BuildTypes are the build configurations and templates defined in the project
+	BuildTypes []*BuildTypeSpec
+	// Projects are the subprojects, whose ParentProjectID must be the ID of this project
+	Projects []*ProjectSpec
+}
+
+// BuildTypeSpec describes the desired state of a build configuration or template, along with the settings managed by separate services
+type BuildTypeSpec struct {
+	// BuildType holds the ID, name, description, options, steps, parameters, templates and VCS root entries of the build configuration
+	BuildType            *BuildType
+	Triggers             []Trigger
+	Features             []BuildFeature
+	SnapshotDependencies []*SnapshotDependency
+	ArtifactDependencies []*ArtifactDependency
+}
+
+// NewProjectSpec returns a ProjectSpec for the given project, with no VCS roots, build configurations or subprojects
+func NewProjectSpec(project *Project) *ProjectSpec {
+	return &ProjectSpec{
+		Project: project,
+	}
+}
+
+// NewBuildTypeSpec returns a BuildTypeSpec for the given build configuration, with no triggers, features or dependencies
+func NewBuildTypeSpec(buildType *BuildType) *BuildTypeSpec {
+	return &BuildTypeSpec{
+		BuildType: buildType,
+	}
+}
+
+// Validate checks that the spec and all its descendants have IDs and consistent project references
+func (s *ProjectSpec) Validate() error {
+	return s.validate(nil)
+}
+
+func (s *ProjectSpec) validate(parent *Project) error {
+	if s.Project == nil {
+		return errors.New("project is required")
+	}
+	id := s.Project.ID
+	if id == "" {
+		return fmt.Errorf("project '%s' must have an ID", s.Project.Name)
+	}
+	if parent != nil && s.Project.ParentProjectID != parent.ID {
+		return fmt.Errorf("project '%s' must have '%s' as ParentProjectID", id, parent.ID)
+	}
+
+	for _, root := range s.VcsRoots {
+		if root.GetID() == "" {
+			return fmt.Errorf("vcs root '%s' in project '%s' must have an ID", root.Name(), id)
+		}
+		if root.ProjectID() != id {
+			return fmt.Errorf("vcs root '%s' must have '%s' as project ID", root.GetID(), id)
+		}
+	}
+
+	for _, bt := range s.BuildTypes {
+		if bt.BuildType == nil {
+			return fmt.Errorf("build type in project '%s' is required", id)
+		}
+		if bt.BuildType.ID == "" {
+			return fmt.Errorf("build type '%s' in project '%s' must have an ID", bt.BuildType.Name, id)
+		}
+		if bt.BuildType.ProjectID != id {
+			return fmt.Errorf("build type '%s' must have '%s' as project ID", bt.BuildType.ID, id)
+		}
+	}
+
+	for _, sub := range s.Projects {
+		if err := sub.validate(s.Project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
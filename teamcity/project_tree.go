@@ -0,0 +1,57 @@
+package teamcity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// The functions below list the direct contents of a project, sorted by id, to walk a project tree
+
+func listSubprojectIDs(r *restHelper, projectID string) ([]string, error) {
+	var out struct {
+		Items []*ProjectReference `json:"project"`
+	}
+	var d locatorDimensions
+	d.add("parentProject", fmt.Sprintf("(id:%s)", projectID))
+	if err := r.getWithFields("projects"+d.query(), getFields{Fields: "project(id)"}, &out, "subprojects"); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(out.Items))
+	for i, item := range out.Items {
+		ids[i] = item.ID
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// listBuildTypes returns the id and template flag of the build configurations and templates defined in the project, excluding subprojects
+func listBuildTypes(r *restHelper, projectID string) ([]*rawBuildTypeJSON, error) {
+	var out struct {
+		Items []*rawBuildTypeJSON `json:"buildType"`
+	}
+	var d locatorDimensions
+	d.add("project", fmt.Sprintf("(id:%s)", projectID))
+	d.add("templateFlag", "any")
+	if err := r.getWithFields("buildTypes"+d.query(), getFields{Fields: "buildType(id,templateFlag)"}, &out, "build types"); err != nil {
+		return nil, err
+	}
+	sort.Slice(out.Items, func(i, j int) bool { return out.Items[i].ID < out.Items[j].ID })
+	return out.Items, nil
+}
+
+func listVcsRootIDs(r *restHelper, projectID string) ([]string, error) {
+	var out struct {
+		Items []*vcsRootJSON `json:"vcs-root"`
+	}
+	var d locatorDimensions
+	d.add("project", fmt.Sprintf("(id:%s)", projectID))
+	if err := r.getWithFields("vcs-roots"+d.query(), getFields{Fields: "vcs-root(id)"}, &out, "vcs roots"); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(out.Items))
+	for i, item := range out.Items {
+		ids[i] = item.ID
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -2,9 +2,6 @@ package teamcity
 
 import (
 	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
 )
 
 const (
@@ -150,78 +147,18 @@ func (p *Properties) Map() map[string]string {
 	return out
 }
 
+// fillStructFromProperties sets the fields of data tagged with `prop`, leaving the fields of invalid values unchanged. Use UnmarshalProperties to handle errors.
 func fillStructFromProperties(data interface{}, p *Properties) {
-	if p == nil {
-		return
-	}
-	t := reflect.TypeOf(data).Elem()
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		if v, ok := f.Tag.Lookup("prop"); ok {
-			sf := reflect.ValueOf(data).Elem().Field(i)
-			if pv, pok := p.GetOk(v); pok {
-				switch sf.Kind() {
-				case reflect.Uint:
-					bv, _ := strconv.ParseUint(pv, 10, 0)
-					sf.SetUint(bv)
-				case reflect.Int:
-					bv, _ := strconv.ParseInt(pv, 10, 0)
-					sf.SetInt(bv)
-				case reflect.Bool:
-					bv, _ := strconv.ParseBool(pv)
-					sf.SetBool(bv)
-				case reflect.String:
-					sf.SetString(pv)
-				case reflect.Slice:
-					var sep string
-					sep, ok = f.Tag.Lookup("separator")
-					if !ok {
-						sep = defaultPropertySliceSeparator
-					}
-					sVal := reflect.ValueOf(strings.Split(pv, sep))
-					sf.Set(sVal)
-				default:
-					//TODO: Panic if cannot set value
-					continue
-				}
-			}
-		}
-	}
+	_ = unmarshalProperties(p, data, unmarshalLenient)
 }
 
+// serializeToProperties returns the fields of data tagged with `prop` as properties.
+// It is meant for the options types of this package, whose tags are covered by tests, so it panics on an invalid tag rather than sending an empty property set.
+// Use MarshalProperties to handle errors.
 func serializeToProperties(data interface{}) *Properties {
-	props := NewPropertiesEmpty()
-	t := reflect.TypeOf(data).Elem()
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		if v, ok := f.Tag.Lookup("prop"); ok {
-			pv := reflect.ValueOf(data).Elem().Field(i)
-			switch pv.Kind() {
-			case reflect.Slice:
-				var sep string
-				sep, ok = f.Tag.Lookup("separator")
-				if !ok {
-					sep = defaultPropertySliceSeparator
-				}
-				pVal := strings.Join(pv.Interface().([]string), sep)
-				props.AddOrReplaceValue(v, pVal)
-			case reflect.Bool:
-				pVal := pv.Bool()
-				_, force := f.Tag.Lookup("force")
-				if pVal || force {
-					props.AddOrReplaceValue(v, strconv.FormatBool(pVal))
-				}
-			case reflect.Int:
-				props.AddOrReplaceValue(v, fmt.Sprint(pv.Int()))
-			case reflect.Uint:
-				props.AddOrReplaceValue(v, fmt.Sprint(pv.Uint()))
-			default:
-				pVal := pv.String()
-				if pVal != "" {
-					props.AddOrReplaceValue(v, pVal)
-				}
-			}
-		}
+	props, err := MarshalProperties(data)
+	if err != nil {
+		panic(fmt.Sprintf("cannot serialize %T to properties: %s", data, err))
 	}
 	return props
 }
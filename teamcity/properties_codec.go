@@ -0,0 +1,384 @@
+package teamcity
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalProperties converts the fields of a struct, or of a pointer to a struct, tagged with `prop:"name"` to properties.
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration, types implementing encoding.TextMarshaler,
+// slices of these, written joined by the value of the `separator` tag ("\r\n" by default), and pointers to these, omitted when nil.
+// A struct or pointer to struct field is nested: its tagged fields are written with the name of the field tag as prefix, such as `prop:"docker."`.
+//
+// Unexported fields are ignored, even if tagged.
+// Empty strings and false booleans are omitted, as TeamCity treats missing properties as their default; add the `force` tag to always write them.
+// Other values are always written. The options following the name in the prop tag change this behavior:
+//
+//	omitempty      omits zero values
+//	unit=<unit>    writes a time.Duration as a number of "ms", "s" (the default), "m" or "h"
+//	default=<v>    is the value read by UnmarshalProperties when the property is missing. It must be the last option.
+func MarshalProperties(v interface{}) (*Properties, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("cannot marshal nil %s to properties", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %s to properties, a struct is required", rv.Type())
+	}
+
+	props := NewPropertiesEmpty()
+	if err := marshalStructProperties(rv, "", props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// UnmarshalProperties sets the fields of the struct pointed to by v from properties, using the tags described in MarshalProperties.
+// Fields of missing properties are left unchanged, unless their tag has a default option. Properties not mapped to any field are ignored.
+func UnmarshalProperties(p *Properties, v interface{}) error {
+	return unmarshalProperties(p, v, unmarshalDefault)
+}
+
+// UnmarshalPropertiesStrict is like UnmarshalProperties, but returns an error if some properties are not mapped to any field
+func UnmarshalPropertiesStrict(p *Properties, v interface{}) error {
+	return unmarshalProperties(p, v, unmarshalStrict)
+}
+
+// propTag is a parsed `prop` field tag
+type propTag struct {
+	name       string
+	omitEmpty  bool
+	force      bool
+	unit       time.Duration
+	separator  string
+	def        string
+	hasDefault bool
+}
+
+func parsePropTag(f reflect.StructField) (*propTag, bool, error) {
+	v, ok := f.Tag.Lookup("prop")
+	// Unexported fields can neither be read nor set through reflection
+	if !ok || f.PkgPath != "" {
+		return nil, false, nil
+	}
+
+	tag := &propTag{unit: time.Second, separator: defaultPropertySliceSeparator}
+	if sep, ok := f.Tag.Lookup("separator"); ok {
+		tag.separator = sep
+	}
+	_, tag.force = f.Tag.Lookup("force")
+
+	parts := strings.Split(v, ",")
+	tag.name = parts[0]
+	for i := 1; i < len(parts); i++ {
+		opt := parts[i]
+		switch {
+		case opt == "omitempty":
+			tag.omitEmpty = true
+		case strings.HasPrefix(opt, "unit="):
+			switch strings.TrimPrefix(opt, "unit=") {
+			case "ms":
+				tag.unit = time.Millisecond
+			case "s":
+				tag.unit = time.Second
+			case "m":
+				tag.unit = time.Minute
+			case "h":
+				tag.unit = time.Hour
+			default:
+				return nil, false, fmt.Errorf("invalid unit option '%s' of field %s", opt, f.Name)
+			}
+		case strings.HasPrefix(opt, "default="):
+			tag.def = strings.TrimPrefix(strings.Join(parts[i:], ","), "default=")
+			tag.hasDefault = true
+			i = len(parts)
+		default:
+			return nil, false, fmt.Errorf("unknown option '%s' of field %s", opt, f.Name)
+		}
+	}
+	return tag, true, nil
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isNestedStruct returns true for struct types that are not encoded as a single value
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textMarshalerType) && !reflect.PtrTo(t).Implements(textUnmarshalType)
+}
+
+func marshalStructProperties(rv reflect.Value, prefix string, props *Properties) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok, err := parsePropTag(f)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		name := prefix + tag.name
+		fv := rv.Field(i)
+
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			if isNestedStruct(fv.Type().Elem()) {
+				if err := marshalStructProperties(fv.Elem(), name, props); err != nil {
+					return err
+				}
+				continue
+			}
+			value, err := formatProperty(fv.Elem(), tag)
+			if err != nil {
+				return fmt.Errorf("cannot marshal property '%s': %s", name, err)
+			}
+			props.AddOrReplaceValue(name, value)
+			continue
+		}
+		if isNestedStruct(fv.Type()) {
+			if err := marshalStructProperties(fv, name, props); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.omitEmpty && fv.IsZero() {
+			continue
+		}
+		value, err := formatProperty(fv, tag)
+		if err != nil {
+			return fmt.Errorf("cannot marshal property '%s': %s", name, err)
+		}
+		if !tag.force && (fv.Kind() == reflect.Bool && !fv.Bool() || fv.Kind() != reflect.Slice && value == "") {
+			continue
+		}
+		props.AddOrReplaceValue(name, value)
+	}
+	return nil
+}
+
+func formatProperty(v reflect.Value, tag *propTag) (string, error) {
+	if v.Type() == durationType {
+		return strconv.FormatInt(int64(time.Duration(v.Int())/tag.unit), 10), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			if v.Index(i).Kind() == reflect.Slice {
+				return "", fmt.Errorf("unsupported type %s", v.Type())
+			}
+			item, err := formatProperty(v.Index(i), tag)
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return strings.Join(items, tag.separator), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// unmarshalMode controls how unmarshalProperties handles properties it cannot set a field from
+type unmarshalMode int
+
+const (
+	// unmarshalDefault ignores properties not mapped to any field, and returns an error for invalid values
+	unmarshalDefault unmarshalMode = iota
+	// unmarshalStrict also returns an error for properties not mapped to any field
+	unmarshalStrict
+	// unmarshalLenient ignores properties not mapped to any field, and leaves the fields of invalid values unchanged
+	unmarshalLenient
+)
+
+// unmarshalProperties sets the fields of the struct pointed to by v from properties, handling the properties it cannot set a field from according to mode
+func unmarshalProperties(p *Properties, v interface{}, mode unmarshalMode) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal properties into %T, a non-nil pointer to a struct is required", v)
+	}
+	if p == nil {
+		p = NewPropertiesEmpty()
+	}
+
+	values := p.Map()
+	known := make(map[string]bool)
+	if _, err := unmarshalStructProperties(values, rv.Elem(), "", known, mode); err != nil {
+		return err
+	}
+
+	if mode == unmarshalStrict {
+		for _, item := range p.Items {
+			if !known[item.Name] {
+				return fmt.Errorf("unknown property '%s'", item.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalStructProperties sets the fields of rv from values, and returns true if any field was set
+func unmarshalStructProperties(values map[string]string, rv reflect.Value, prefix string, known map[string]bool, mode unmarshalMode) (bool, error) {
+	set := false
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok, err := parsePropTag(f)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		name := prefix + tag.name
+		fv := rv.Field(i)
+
+		if fv.Kind() == reflect.Ptr && isNestedStruct(fv.Type().Elem()) {
+			nested := reflect.New(fv.Type().Elem())
+			if !fv.IsNil() {
+				nested.Elem().Set(fv.Elem())
+			}
+			nestedSet, err := unmarshalStructProperties(values, nested.Elem(), name, known, mode)
+			if err != nil {
+				return false, err
+			}
+			if nestedSet {
+				fv.Set(nested)
+				set = true
+			}
+			continue
+		}
+		if isNestedStruct(fv.Type()) {
+			nestedSet, err := unmarshalStructProperties(values, fv, name, known, mode)
+			if err != nil {
+				return false, err
+			}
+			set = set || nestedSet
+			continue
+		}
+
+		known[name] = true
+		value, ok := values[name]
+		if !ok {
+			if !tag.hasDefault {
+				continue
+			}
+			value = tag.def
+		}
+
+		// The value is parsed into a new variable, so that the field is left unchanged if it is invalid
+		elemType := fv.Type()
+		if fv.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		target := reflect.New(elemType).Elem()
+		if err := parseProperty(value, target, tag); err != nil {
+			if mode == unmarshalLenient {
+				continue
+			}
+			return false, fmt.Errorf("cannot unmarshal property '%s': %s", name, err)
+		}
+		if fv.Kind() == reflect.Ptr {
+			fv.Set(target.Addr())
+		} else {
+			fv.Set(target)
+		}
+		set = true
+	}
+	return set, nil
+}
+
+func parseProperty(value string, v reflect.Value, tag *propTag) error {
+	if v.Type() == durationType {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			v.SetInt(n * int64(tag.unit))
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration '%s'", value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean '%s'", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer '%s'", value)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer '%s'", value)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Slice {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		items := strings.Split(value, tag.separator)
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := parseProperty(item, out.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package teamcity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPluginMode string
+
+func (m *testPluginMode) UnmarshalText(text []byte) error {
+	switch v := testPluginMode(text); v {
+	case "fast", "safe":
+		*m = v
+		return nil
+	}
+	return fmt.Errorf("invalid mode '%s'", text)
+}
+
+func (m testPluginMode) MarshalText() ([]byte, error) {
+	return []byte(m), nil
+}
+
+type testPluginDocker struct {
+	Image string `prop:"image"`
+	Pull  bool   `prop:"pull" force:""`
+}
+
+type testPluginSettings struct {
+	Name     string            `prop:"name"`
+	Comment  string            `prop:"comment"`
+	Retries  int               `prop:"retries,omitempty"`
+	Ratio    float64           `prop:"ratio,omitempty"`
+	Timeout  time.Duration     `prop:"timeout,unit=m"`
+	Delay    time.Duration     `prop:"delay,omitempty"`
+	Mode     testPluginMode    `prop:"mode,default=safe"`
+	Parallel *bool             `prop:"parallel"`
+	Ports    []int             `prop:"ports" separator:","`
+	Docker   testPluginDocker  `prop:"docker."`
+	Cache    *testPluginDocker `prop:"cache."`
+	Ignored  string
+}
+
+func Test_MarshalProperties(t *testing.T) {
+	parallel := false
+	sut := &testPluginSettings{
+		Name:     "plugin",
+		Timeout:  90 * time.Minute,
+		Mode:     "fast",
+		Parallel: &parallel,
+		Ports:    []int{80, 443},
+		Docker:   testPluginDocker{Image: "golang"},
+		Ignored:  "ignored",
+	}
+
+	actual, err := MarshalProperties(sut)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":         "plugin",
+		"timeout":      "90",
+		"mode":         "fast",
+		"parallel":     "false",
+		"ports":        "80,443",
+		"docker.image": "golang",
+		"docker.pull":  "false",
+	}, actual.Map())
+}
+
+func Test_UnmarshalProperties(t *testing.T) {
+	require := require.New(t)
+	p := NewProperties(
+		NewProperty("name", "plugin"),
+		NewProperty("retries", "3"),
+		NewProperty("timeout", "2"),
+		NewProperty("delay", "1m30s"),
+		NewProperty("parallel", "true"),
+		NewProperty("ports", "80,443"),
+		NewProperty("cache.image", "redis"),
+		NewProperty("unknown", "value"),
+	)
+
+	var actual testPluginSettings
+	require.NoError(UnmarshalProperties(p, &actual))
+	parallel := true
+	assert.Equal(t, testPluginSettings{
+		Name:     "plugin",
+		Retries:  3,
+		Timeout:  2 * time.Minute,
+		Delay:    90 * time.Second,
+		Mode:     "safe",
+		Parallel: &parallel,
+		Ports:    []int{80, 443},
+		Cache:    &testPluginDocker{Image: "redis"},
+	}, actual)
+
+	roundTrip, err := MarshalProperties(&actual)
+	require.NoError(err)
+	var again testPluginSettings
+	require.NoError(UnmarshalProperties(roundTrip, &again))
+	assert.Equal(t, actual, again)
+
+	err = UnmarshalPropertiesStrict(p, &testPluginSettings{})
+	assert.EqualError(t, err, "unknown property 'unknown'")
+}
+
+func Test_UnmarshalPropertiesErrors(t *testing.T) {
+	var sut testPluginSettings
+	assert.EqualError(t, UnmarshalProperties(NewProperties(NewProperty("retries", "three")), &sut),
+		"cannot unmarshal property 'retries': invalid integer 'three'")
+	assert.EqualError(t, UnmarshalProperties(NewProperties(NewProperty("mode", "slow")), &sut),
+		"cannot unmarshal property 'mode': invalid mode 'slow'")
+	assert.EqualError(t, UnmarshalProperties(NewPropertiesEmpty(), sut),
+		"cannot unmarshal properties into teamcity.testPluginSettings, a non-nil pointer to a struct is required")
+
+	var unsupported struct {
+		Values map[string]string `prop:"values"`
+	}
+	assert.EqualError(t, UnmarshalProperties(NewProperties(NewProperty("values", "a")), &unsupported),
+		"cannot unmarshal property 'values': unsupported type map[string]string")
+	_, err := MarshalProperties(&unsupported)
+	assert.EqualError(t, err, "cannot marshal property 'values': unsupported type map[string]string")
+}
+
+func Test_PropertiesSkipUnexportedFields(t *testing.T) {
+	require := require.New(t)
+	type settings struct {
+		Name    string         `prop:"name"`
+		mode    testPluginMode `prop:"mode"`
+		enabled bool           `prop:"enabled"`
+	}
+
+	sut := settings{Name: "plugin", mode: "fast", enabled: true}
+	actual, err := MarshalProperties(&sut)
+	require.NoError(err)
+	assert.Equal(t, map[string]string{"name": "plugin"}, actual.Map())
+
+	var out settings
+	require.NoError(UnmarshalProperties(NewProperties(NewProperty("name", "plugin"), NewProperty("enabled", "true")), &out))
+	assert.Equal(t, settings{Name: "plugin"}, out)
+
+	var vcs TriggerVcsOptions
+	require.NoError(UnmarshalProperties(NewProperties(NewProperty("branch", "+:*")), &vcs))
+}
+
+func Test_FillStructFromPropertiesSkipsInvalidValues(t *testing.T) {
+	sut := testPluginSettings{Retries: 1}
+	fillStructFromProperties(&sut, NewProperties(
+		NewProperty("retries", "x"),
+		NewProperty("mode", "slow"),
+		NewProperty("name", "plugin"),
+		NewProperty("ports", "80,http"),
+		NewProperty("cache.image", "redis"),
+	))
+	assert.Equal(t, testPluginSettings{
+		Name:    "plugin",
+		Retries: 1,
+		Cache:   &testPluginDocker{Image: "redis"},
+	}, sut)
+}
+
+// Test_OptionsMarshalProperties checks the tags of the options types serialized with serializeToProperties
+func Test_OptionsMarshalProperties(t *testing.T) {
+	options := []interface{}{
+		&BuildTypeOptions{},
+		&TriggerSchedule{},
+		&TriggerScheduleOptions{},
+		&FeatureXMLReportProcessingOptions{},
+		&FeatureSwabraOptions{},
+		&FeatureSSHAgentOptions{},
+		&FeaturePullRequestsOptions{},
+		&FeatureAutoMergeOptions{},
+		&FeatureFileContentReplacerOptions{},
+		&FailureConditionOnMetricOptions{},
+		&FailureConditionOnTextOptions{},
+		&StatusPublisherAzureDevOpsOptions{},
+		&StatusPublisherBitbucketCloudOptions{},
+		&StatusPublisherBitbucketServerOptions{},
+		&StatusPublisherGerritOptions{},
+		&StatusPublisherGitlabOptions{},
+		&StatusPublisherSpaceOptions{},
+	}
+	for _, opt := range options {
+		_, err := MarshalProperties(opt)
+		assert.NoError(t, err, "%T", opt)
+	}
+}
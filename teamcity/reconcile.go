@@ -0,0 +1,679 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PlanAction is the kind of change a PlanOperation makes
+type PlanAction string
+
+const (
+	// PlanActionCreate creates a resource that does not exist on the server
+	PlanActionCreate PlanAction = "create"
+	// PlanActionUpdate changes an existing resource in-place
+	PlanActionUpdate PlanAction = "update"
+	// PlanActionDelete removes a resource that is not part of the desired state
+	PlanActionDelete PlanAction = "delete"
+)
+
+// PlanResource is the kind of resource a PlanOperation changes
+type PlanResource string
+
+const (
+	PlanResourceProject            PlanResource = "project"
+	PlanResourceVcsRoot            PlanResource = "vcsRoot"
+	PlanResourceBuildType          PlanResource = "buildType"
+	PlanResourceVcsRootEntry       PlanResource = "vcsRootEntry"
+	PlanResourceTrigger            PlanResource = "trigger"
+	PlanResourceFeature            PlanResource = "feature"
+	PlanResourceSnapshotDependency PlanResource = "snapshotDependency"
+	PlanResourceArtifactDependency PlanResource = "artifactDependency"
+)
+
+// Operations are applied in this order, so resources exist before they are referenced and references are removed before what they refer to
+const (
+	phaseProject = iota
+	phaseVcsRoot
+	phaseTemplate
+	phaseBuildType
+	phaseRemoveSetting
+	phaseAddSetting
+	phaseDeleteBuildType
+	phaseDeleteTemplate
+	phaseDeleteVcsRoot
+	phaseDeleteProject
+)
+
+// PlanOperation is a single change computed by Reconciler.Plan
+type PlanOperation struct {
+	Action   PlanAction
+	Resource PlanResource
+	// ID identifies the resource. Settings of a build configuration are identified as "<buildTypeID>/<id>",
+	// where id is the type of the setting if it was not created yet.
+	ID string
+	// Reason explains why the operation is needed
+	Reason string
+
+	phase int
+	apply func() error
+}
+
+func (o *PlanOperation) String() string {
+	return fmt.Sprintf("%s %s '%s': %s", o.Action, o.Resource, o.ID, o.Reason)
+}
+
+// Plan is the ordered list of operations that brings the server to the desired state of a ProjectSpec
+type Plan struct {
+	Operations []*PlanOperation
+}
+
+// Empty returns true if the server already matches the desired state
+func (p *Plan) Empty() bool {
+	return len(p.Operations) == 0
+}
+
+// String returns a human-readable preview of the plan, one operation per line
+func (p *Plan) String() string {
+	lines := make([]string, len(p.Operations))
+	for i, op := range p.Operations {
+		lines[i] = op.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Reconciler computes and applies the changes needed to make the server match a ProjectSpec.
+// Resources are matched by ID. Triggers, features and dependencies without an ID are matched by type and properties.
+// Settings of triggers, features and dependencies cannot be changed in-place, so they are deleted and created again.
+// Templates attached to a build configuration are only set when it is created, and build configurations are not moved between projects.
+type Reconciler struct {
+	client     *Client
+	restHelper *restHelper
+}
+
+// NewReconciler returns a Reconciler working on the server of the given client
+func NewReconciler(client *Client) *Reconciler {
+	return &Reconciler{
+		client:     client,
+		restHelper: newRestHelper(client.HTTPClient, client.commonBase.New()),
+	}
+}
+
+// Plan compares the spec with the server and returns the operations needed to reconcile them, without changing anything.
+// Resources in the projects of the spec that are not part of it are planned for deletion.
+func (r *Reconciler) Plan(spec *ProjectSpec) (*Plan, error) {
+	if spec == nil {
+		return nil, errors.New("spec is required")
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &reconcilePlanner{
+		Reconciler: r,
+		plan:       &Plan{},
+		projects:   make(map[string]bool),
+		vcsRoots:   make(map[string]bool),
+		buildTypes: make(map[string]bool),
+	}
+	p.collectIDs(spec)
+	if err := p.planProject(spec); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(p.plan.Operations, func(i, j int) bool {
+		return p.plan.Operations[i].phase < p.plan.Operations[j].phase
+	})
+	return p.plan, nil
+}
+
+// Apply runs the operations of the plan in order, stopping at the first failure.
+// Operations are not atomic, so a failed apply leaves the server partially reconciled; planning again shows what is left.
+func (r *Reconciler) Apply(plan *Plan) error {
+	for i, op := range plan.Operations {
+		if err := op.apply(); err != nil {
+			return fmt.Errorf("applied %d of %d operations, failed to %s %s '%s': %s", i, len(plan.Operations), op.Action, op.Resource, op.ID, err)
+		}
+	}
+	return nil
+}
+
+type reconcilePlanner struct {
+	*Reconciler
+	plan *Plan
+
+	// IDs anywhere in the spec, so resources moved to another project of the spec are not deleted
+	projects   map[string]bool
+	vcsRoots   map[string]bool
+	buildTypes map[string]bool
+}
+
+func (p *reconcilePlanner) collectIDs(spec *ProjectSpec) {
+	p.projects[spec.Project.ID] = true
+	for _, root := range spec.VcsRoots {
+		p.vcsRoots[root.GetID()] = true
+	}
+	for _, bt := range spec.BuildTypes {
+		p.buildTypes[bt.BuildType.ID] = true
+	}
+	for _, sub := range spec.Projects {
+		p.collectIDs(sub)
+	}
+}
+
+func (p *reconcilePlanner) add(phase int, action PlanAction, resource PlanResource, id string, reason string, apply func() error) {
+	p.plan.Operations = append(p.plan.Operations, &PlanOperation{
+		Action:   action,
+		Resource: resource,
+		ID:       id,
+		Reason:   reason,
+		phase:    phase,
+		apply:    apply,
+	})
+}
+
+func (p *reconcilePlanner) planProject(spec *ProjectSpec) error {
+	project := spec.Project
+	live, err := p.client.Projects.GetByID(project.ID)
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+
+	if live == nil {
+		p.add(phaseProject, PlanActionCreate, PlanResourceProject, project.ID, "not found on server", func() error {
+			_, err := p.client.Projects.Create(project)
+			return err
+		})
+	} else if changed := p.projectChanges(project, live); len(changed) > 0 {
+		p.add(phaseProject, PlanActionUpdate, PlanResourceProject, project.ID, changesReason(changed), func() error {
+			return p.updateProject(project, live)
+		})
+	}
+
+	for _, root := range spec.VcsRoots {
+		if err := p.planVcsRoot(root); err != nil {
+			return err
+		}
+	}
+	for _, bt := range spec.BuildTypes {
+		if err := p.planBuildType(bt); err != nil {
+			return err
+		}
+	}
+	for _, sub := range spec.Projects {
+		if err := p.planProject(sub); err != nil {
+			return err
+		}
+	}
+
+	if live != nil {
+		return p.planProjectDeletions(spec)
+	}
+	return nil
+}
+
+func (p *reconcilePlanner) projectChanges(project *Project, live *Project) []string {
+	var changed []string
+	if project.Name != live.Name {
+		changed = append(changed, "name")
+	}
+	if project.Description != live.Description {
+		changed = append(changed, "description")
+	}
+	if rootProjectID(project.ParentProjectID) != rootProjectID(live.ParentProjectID) {
+		changed = append(changed, "parent project")
+	}
+	if parametersSignature(project.Parameters) != parametersSignature(live.Parameters) {
+		changed = append(changed, "parameters")
+	}
+	return changed
+}
+
+func (p *reconcilePlanner) updateProject(project *Project, live *Project) error {
+	update := *project
+	update.UUID = live.UUID
+	if update.Parameters == nil {
+		update.Parameters = NewParametersEmpty()
+	}
+	if _, err := p.client.Projects.Update(&update); err != nil {
+		return err
+	}
+
+	// Update only sends parameters if there are any, so removing all of them is done separately
+	if update.Parameters.Count == 0 && live.Parameters != nil && live.Parameters.Count > 0 {
+		var out Parameters
+		return p.restHelper.put("projects/"+LocatorID(project.ID).String()+"/parameters", update.Parameters, &out, "project parameters")
+	}
+	return nil
+}
+
+func (p *reconcilePlanner) planProjectDeletions(spec *ProjectSpec) error {
+	id := spec.Project.ID
+
+	subprojects, err := listSubprojectIDs(p.restHelper, id)
+	if err != nil {
+		return err
+	}
+	for _, subID := range subprojects {
+		if p.projects[subID] {
+			continue
+		}
+		subID := subID
+		p.add(phaseDeleteProject, PlanActionDelete, PlanResourceProject, subID, fmt.Sprintf("subproject of '%s' not in desired state", id), func() error {
+			return p.client.Projects.DeleteLocator(LocatorID(subID))
+		})
+	}
+
+	buildTypes, err := listBuildTypes(p.restHelper, id)
+	if err != nil {
+		return err
+	}
+	for _, bt := range buildTypes {
+		if p.buildTypes[bt.ID] {
+			continue
+		}
+		btID := bt.ID
+		phase := phaseDeleteBuildType
+		if bt.TemplateFlag != nil && *bt.TemplateFlag {
+			phase = phaseDeleteTemplate
+		}
+		p.add(phase, PlanActionDelete, PlanResourceBuildType, btID, fmt.Sprintf("build type in '%s' not in desired state", id), func() error {
+			return p.client.BuildTypes.Delete(btID)
+		})
+	}
+
+	vcsRoots, err := listVcsRootIDs(p.restHelper, id)
+	if err != nil {
+		return err
+	}
+	for _, rootID := range vcsRoots {
+		if p.vcsRoots[rootID] {
+			continue
+		}
+		rootID := rootID
+		p.add(phaseDeleteVcsRoot, PlanActionDelete, PlanResourceVcsRoot, rootID, fmt.Sprintf("vcs root in '%s' not in desired state", id), func() error {
+			return p.client.VcsRoots.Delete(rootID)
+		})
+	}
+	return nil
+}
+
+func (p *reconcilePlanner) planVcsRoot(root VcsRoot) error {
+	var live vcsRootJSON
+	err := p.restHelper.get("vcs-roots/"+LocatorID(root.GetID()).String(), &live, "vcs root")
+	if err != nil {
+		if !isNotFoundError(err) {
+			return err
+		}
+		p.add(phaseVcsRoot, PlanActionCreate, PlanResourceVcsRoot, root.GetID(), "not found on server", func() error {
+			_, err := p.client.VcsRoots.Create(root.ProjectID(), root)
+			return err
+		})
+		return nil
+	}
+
+	var changed []string
+	if root.Name() != live.Name {
+		changed = append(changed, "name")
+	}
+	if live.Project != nil && root.ProjectID() != live.Project.ID {
+		changed = append(changed, "project")
+	}
+	if v := root.ModificationCheckInterval(); v != nil && *v != live.ModificationCheckInterval {
+		changed = append(changed, "modification check interval")
+	}
+	if propertiesSignature(root.Properties()) != propertiesSignature(live.Properties) {
+		changed = append(changed, "properties")
+	}
+	if len(changed) > 0 {
+		p.add(phaseVcsRoot, PlanActionUpdate, PlanResourceVcsRoot, root.GetID(), changesReason(changed), func() error {
+			_, err := p.client.VcsRoots.Update(root)
+			return err
+		})
+	}
+	return nil
+}
+
+func (p *reconcilePlanner) planBuildType(spec *BuildTypeSpec) error {
+	bt := spec.BuildType
+	phase := phaseBuildType
+	if bt.IsTemplate {
+		phase = phaseTemplate
+	}
+
+	var live *rawBuildTypeJSON
+	var out rawBuildTypeJSON
+	err := p.restHelper.get("buildTypes/"+LocatorID(bt.ID).String(), &out, "build type")
+	if err != nil {
+		if !isNotFoundError(err) {
+			return err
+		}
+		p.add(phase, PlanActionCreate, PlanResourceBuildType, bt.ID, "not found on server", func() error {
+			_, err := p.client.BuildTypes.Create(bt)
+			return err
+		})
+		live = &rawBuildTypeJSON{}
+	} else {
+		live = &out
+		if err := p.planBuildTypeUpdate(phase, bt, live); err != nil {
+			return err
+		}
+	}
+
+	p.planVcsRootEntries(bt, live)
+
+	var liveItems []*settingItem
+	if live.Triggers != nil {
+		liveItems = live.Triggers.Items
+	}
+	triggers := make([]interface{}, len(spec.Triggers))
+	for i, t := range spec.Triggers {
+		triggers[i] = t
+	}
+	err = p.planSettings(bt.ID, PlanResourceTrigger, triggers, liveItems, func(v interface{}) error {
+		_, err := p.client.TriggerService(bt.ID).AddTrigger(v.(Trigger))
+		return err
+	}, func(id string) error {
+		return p.client.TriggerService(bt.ID).Delete(id)
+	})
+	if err != nil {
+		return err
+	}
+
+	liveItems = nil
+	if live.Features != nil {
+		liveItems = live.Features.Items
+	}
+	features := make([]interface{}, len(spec.Features))
+	for i, f := range spec.Features {
+		features[i] = f
+	}
+	err = p.planSettings(bt.ID, PlanResourceFeature, features, liveItems, func(v interface{}) error {
+		_, err := p.client.BuildFeatureService(bt.ID).Create(v.(BuildFeature))
+		return err
+	}, func(id string) error {
+		return p.client.BuildFeatureService(bt.ID).Delete(id)
+	})
+	if err != nil {
+		return err
+	}
+
+	liveItems = nil
+	if live.SnapshotDependencies != nil {
+		liveItems = live.SnapshotDependencies.Items
+	}
+	snapshots := make([]interface{}, len(spec.SnapshotDependencies))
+	for i, dep := range spec.SnapshotDependencies {
+		snapshots[i] = dep
+	}
+	err = p.planSettings(bt.ID, PlanResourceSnapshotDependency, snapshots, liveItems, func(v interface{}) error {
+		_, err := p.client.DependencyService(bt.ID).AddSnapshotDependency(v.(*SnapshotDependency))
+		return err
+	}, func(id string) error {
+		return p.client.DependencyService(bt.ID).DeleteSnapshot(id)
+	})
+	if err != nil {
+		return err
+	}
+
+	liveItems = nil
+	if live.ArtifactDependencies != nil {
+		liveItems = live.ArtifactDependencies.Items
+	}
+	artifacts := make([]interface{}, len(spec.ArtifactDependencies))
+	for i, dep := range spec.ArtifactDependencies {
+		artifacts[i] = dep
+	}
+	return p.planSettings(bt.ID, PlanResourceArtifactDependency, artifacts, liveItems, func(v interface{}) error {
+		_, err := p.client.DependencyService(bt.ID).AddArtifactDependency(v.(*ArtifactDependency))
+		return err
+	}, func(id string) error {
+		return p.client.DependencyService(bt.ID).DeleteArtifact(id)
+	})
+}
+
+func (p *reconcilePlanner) planBuildTypeUpdate(phase int, bt *BuildType, live *rawBuildTypeJSON) error {
+	var changed []string
+	if bt.Name != live.Name {
+		changed = append(changed, "name")
+	}
+	if bt.Description != live.Description {
+		changed = append(changed, "description")
+	}
+
+	isTemplate := live.TemplateFlag != nil && *live.TemplateFlag
+	if buildTypeSettingsSignature(bt.Options) != buildTypeSettingsSignature(live.Settings.buildTypeOptions(isTemplate)) {
+		changed = append(changed, "settings")
+	}
+	if parametersSignature(bt.Parameters) != parametersSignature(live.Parameters) {
+		changed = append(changed, "parameters")
+	}
+
+	steps, err := buildTypeStepItems(bt)
+	if err != nil {
+		return err
+	}
+	var liveSteps []*settingItem
+	if live.Steps != nil {
+		for _, s := range live.Steps.Items {
+			if !s.inherited() {
+				liveSteps = append(liveSteps, s)
+			}
+		}
+	}
+	if settingsSignature(steps) != settingsSignature(liveSteps) {
+		changed = append(changed, "steps")
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	p.add(phase, PlanActionUpdate, PlanResourceBuildType, bt.ID, changesReason(changed), func() error {
+		update := *bt
+		if update.Parameters == nil {
+			update.Parameters = NewParametersEmpty()
+		}
+		if _, err := p.client.BuildTypes.Update(&update); err != nil {
+			return err
+		}
+		// Update only sends steps if there are any, so removing all of them is done separately
+		if len(bt.Steps) == 0 {
+			for _, s := range liveSteps {
+				if err := p.client.BuildTypes.DeleteStep(bt.ID, s.ID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+func (p *reconcilePlanner) planVcsRootEntries(bt *BuildType, live *rawBuildTypeJSON) {
+	liveRules := make(map[string]string)
+	if live.Entries != nil {
+		for _, e := range live.Entries.Items {
+			if e.Inherited != nil && *e.Inherited || e.VcsRoot == nil {
+				continue
+			}
+			liveRules[e.VcsRoot.ID] = e.CheckoutRules
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, e := range bt.VcsRootEntries {
+		entry := e
+		rootID := entry.VcsRoot.ID
+		wanted[rootID] = true
+		id := bt.ID + "/" + rootID
+
+		rules, ok := liveRules[rootID]
+		if ok && rules == entry.CheckoutRules {
+			continue
+		}
+		reason := "not attached"
+		if ok {
+			// Checkout rules are changed by attaching the root again
+			reason = "checkout rules changed"
+			p.add(phaseRemoveSetting, PlanActionDelete, PlanResourceVcsRootEntry, id, reason, func() error {
+				return p.client.BuildTypes.DetachVcsRoot(bt.ID, rootID)
+			})
+		}
+		p.add(phaseAddSetting, PlanActionCreate, PlanResourceVcsRootEntry, id, reason, func() error {
+			return p.client.BuildTypes.AttachVcsRootEntry(bt.ID, entry)
+		})
+	}
+
+	for rootID := range liveRules {
+		if wanted[rootID] {
+			continue
+		}
+		detached := rootID
+		p.add(phaseRemoveSetting, PlanActionDelete, PlanResourceVcsRootEntry, bt.ID+"/"+detached, "not in desired state", func() error {
+			return p.client.BuildTypes.DetachVcsRoot(bt.ID, detached)
+		})
+	}
+}
+
+// planSettings matches the desired triggers, features or dependencies with the live ones of a build configuration.
+// Items with an ID are matched by ID, others by type and properties.
+func (p *reconcilePlanner) planSettings(buildTypeID string, resource PlanResource, wanted []interface{}, live []*settingItem, create func(interface{}) error, remove func(string) error) error {
+	unmatched := make([]*settingItem, 0, len(live))
+	for _, item := range live {
+		if !item.inherited() {
+			unmatched = append(unmatched, item)
+		}
+	}
+
+	for _, w := range wanted {
+		value := w
+		item, err := newSettingItem(value)
+		if err != nil {
+			return err
+		}
+
+		match := -1
+		for i, l := range unmatched {
+			if (item.ID != "" && l.ID == item.ID) || (item.ID == "" && l.signature() == item.signature()) {
+				match = i
+				break
+			}
+		}
+
+		reason := "not found on server"
+		if match >= 0 {
+			l := unmatched[match]
+			unmatched = append(unmatched[:match], unmatched[match+1:]...)
+			if l.signature() == item.signature() {
+				continue
+			}
+			reason = "settings changed"
+			liveID := l.ID
+			p.add(phaseRemoveSetting, PlanActionDelete, resource, buildTypeID+"/"+liveID, reason, func() error {
+				return remove(liveID)
+			})
+		}
+
+		id := item.ID
+		if id == "" {
+			id = item.Type
+		}
+		p.add(phaseAddSetting, PlanActionCreate, resource, buildTypeID+"/"+id, reason, func() error {
+			return create(value)
+		})
+	}
+
+	for _, l := range unmatched {
+		liveID := l.ID
+		p.add(phaseRemoveSetting, PlanActionDelete, resource, buildTypeID+"/"+liveID, "not in desired state", func() error {
+			return remove(liveID)
+		})
+	}
+	return nil
+}
+
+func settingsSignature(items []*settingItem) string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.signature()
+	}
+	return strings.Join(out, "\n")
+}
+
+func buildTypeStepItems(bt *BuildType) ([]*settingItem, error) {
+	out := make([]*settingItem, len(bt.Steps))
+	for i, s := range bt.Steps {
+		step, err := serializeStep(s)
+		if err != nil {
+			return nil, err
+		}
+		item, err := newSettingItem(step)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = item
+	}
+	return out, nil
+}
+
+// propertiesSignature returns the sorted non-empty properties. Secure properties are skipped, since the server never returns their values.
+func propertiesSignature(p *Properties) string {
+	if p == nil {
+		return ""
+	}
+	var out []string
+	for _, item := range p.Items {
+		if item.Value == "" || strings.HasPrefix(item.Name, "secure:") {
+			continue
+		}
+		out = append(out, item.Name+"="+item.Value)
+	}
+	sort.Strings(out)
+	return strings.Join(out, "\n")
+}
+
+func parametersSignature(p *Parameters) string {
+	if p == nil {
+		return ""
+	}
+	var out []string
+	for _, item := range p.Items {
+		if item.Inherited {
+			continue
+		}
+		prop := item.Property()
+		// The server never returns the values of password parameters, only their specification is compared
+		if item.IsPassword() {
+			prop.Value = ""
+		}
+		if prop.Type != nil {
+			prop.Value += " [" + prop.Type.RawValue + "]"
+		}
+		out = append(out, prop.Name+"="+prop.Value)
+	}
+	sort.Strings(out)
+	return strings.Join(out, "\n")
+}
+
+// buildTypeSettingsSignature skips the build counter, which changes with every build
+func buildTypeSettingsSignature(opt *BuildTypeOptions) string {
+	if opt == nil {
+		return ""
+	}
+	props := opt.properties()
+	props.Remove("buildNumberCounter")
+	return propertiesSignature(props)
+}
+
+func rootProjectID(id string) string {
+	if id == "" {
+		return "_Root"
+	}
+	return id
+}
+
+func changesReason(changed []string) string {
+	return strings.Join(changed, ", ") + " changed"
+}
@@ -0,0 +1,137 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newReconcileTestSpec(t *testing.T) (*ProjectSpec, *BuildTypeSpec) {
+	require := require.New(t)
+
+	project, err := NewProject("Proj", "", "")
+	require.NoError(err)
+	project.ID = "Proj"
+	project.Parameters = NewParameters(&Parameter{Type: ParameterTypes.EnvironmentVariable, Name: "A", Value: "1"})
+
+	opt, err := NewGitVcsRootOptionsDefaults("refs/heads/main", "https://example.com/repo.git")
+	require.NoError(err)
+	root, err := NewGitVcsRoot("Proj", "Git", opt)
+	require.NoError(err)
+	root.ID = "Proj_Git"
+
+	bt, err := NewBuildType("Proj", "Build")
+	require.NoError(err)
+	bt.ID = "Proj_Build"
+	bt.VcsRootEntries = []*VcsRootEntry{NewVcsRootEntry(&VcsRootReference{ID: "Proj_Git"})}
+
+	trigger, err := NewTriggerVcs([]string{"+:*"}, []string{"+:<default>"})
+	require.NoError(err)
+
+	btSpec := NewBuildTypeSpec(bt)
+	btSpec.Triggers = []Trigger{trigger}
+	btSpec.SnapshotDependencies = []*SnapshotDependency{NewSnapshotDependency("Proj_Lib")}
+
+	spec := NewProjectSpec(project)
+	spec.VcsRoots = []VcsRoot{root}
+	spec.BuildTypes = []*BuildTypeSpec{btSpec}
+	return spec, btSpec
+}
+
+func Test_ReconcilerPlanAndApply(t *testing.T) {
+	require := require.New(t)
+	spec, btSpec := newReconcileTestSpec(t)
+
+	// The live trigger is the same as the desired one, only with a server assigned id
+	trigger, err := newSettingItem(btSpec.Triggers[0])
+	require.NoError(err)
+	trigger.ID = "TRIGGER_1"
+	liveTrigger, err := json.Marshal(trigger)
+	require.NoError(err)
+
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/projects/id%3AProj":         `{"id":"Proj","name":"Old name","parentProjectId":"_Root","uuid":"u1","parameters":{"property":[{"name":"env.A","value":"1"}]}}`,
+		"/httpAuth/app/rest/projects":                   `{"project":[{"id":"Proj_Stale"}]}`,
+		"/httpAuth/app/rest/buildTypes":                 `{"buildType":[{"id":"Proj_Build"},{"id":"Proj_Old"}]}`,
+		"/httpAuth/app/rest/vcs-roots":                  `{"vcs-root":[]}`,
+		"/httpAuth/app/rest/buildTypes/id%3AProj_Build": fmt.Sprintf(reconcileBuildTypeTemplateJSON, liveTrigger),
+	})
+	sut := NewReconciler(client)
+
+	plan, err := sut.Plan(spec)
+	require.NoError(err)
+	require.Equal(`update project 'Proj': name changed
+create vcsRoot 'Proj_Git': not found on server
+delete feature 'Proj_Build/BUILD_EXT_1': not in desired state
+create vcsRootEntry 'Proj_Build/Proj_Git': not attached
+create snapshotDependency 'Proj_Build/snapshot_dependency': not found on server
+delete buildType 'Proj_Old': build type in 'Proj' not in desired state
+delete project 'Proj_Stale': subproject of 'Proj' not in desired state`, plan.String())
+
+	var deletions []*PlanOperation
+	for _, op := range plan.Operations {
+		if op.Action == PlanActionDelete {
+			deletions = append(deletions, op)
+		}
+	}
+	require.NoError(sut.Apply(&Plan{Operations: deletions}))
+	require.Equal([]recordedRequest{
+		{Method: "DELETE", Path: "/httpAuth/app/rest/buildTypes/id%3AProj_Build/features/BUILD_EXT_1"},
+		{Method: "DELETE", Path: "/httpAuth/app/rest/buildTypes/Proj_Old"},
+		{Method: "DELETE", Path: "/httpAuth/app/rest/projects/id%3AProj_Stale"},
+	}, fake.requests)
+}
+
+func Test_ReconcilerRemovesAllProjectParameters(t *testing.T) {
+	require := require.New(t)
+	spec, _ := newReconcileTestSpec(t)
+	spec.Project.Parameters = NewParametersEmpty()
+	spec.BuildTypes = nil
+	spec.VcsRoots = nil
+
+	live := `{"id":"Proj","name":"Proj","parentProjectId":"_Root","uuid":"u1","parameters":{"count":1,"property":[{"name":"env.A","value":"1"}]}}`
+	client, fake := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/projects/id%3AProj": live,
+		"/httpAuth/app/rest/projects/uuid%3Au1": live,
+		"/httpAuth/app/rest/projects":           `{"project":[]}`,
+		"/httpAuth/app/rest/buildTypes":         `{"buildType":[]}`,
+		"/httpAuth/app/rest/vcs-roots":          `{"vcs-root":[]}`,
+	})
+	sut := NewReconciler(client)
+
+	plan, err := sut.Plan(spec)
+	require.NoError(err)
+	require.Equal(`update project 'Proj': parameters changed`, plan.String())
+
+	require.NoError(sut.Apply(plan))
+	require.Contains(fake.requests, recordedRequest{Method: "PUT", Path: "/httpAuth/app/rest/projects/id%3AProj/parameters", Body: "{}\n"})
+}
+
+func Test_ProjectSpecValidate(t *testing.T) {
+	spec, btSpec := newReconcileTestSpec(t)
+	btSpec.BuildType.ProjectID = "Other"
+	require.EqualError(t, spec.Validate(), "build type 'Proj_Build' must have 'Proj' as project ID")
+
+	spec, btSpec = newReconcileTestSpec(t)
+	btSpec.BuildType.ID = ""
+	require.EqualError(t, spec.Validate(), "build type 'Build' in project 'Proj' must have an ID")
+}
+
+const reconcileBuildTypeTemplateJSON = `
+{
+	"id": "Proj_Build",
+	"name": "Build",
+	"projectId": "Proj",
+	"templateFlag": false,
+	"settings": {"property": [{"name": "buildNumberCounter", "value": "12"}]},
+	"parameters": {"property": [{"name": "inherited.param", "value": "x", "inherited": true}]},
+	"steps": {"count": 0},
+	"vcs-root-entries": {"count": 0},
+	"triggers": {"count": 1, "trigger": [%s]},
+	"features": {"count": 1, "feature": [{"id": "BUILD_EXT_1", "type": "golang", "properties": {"property": [{"name": "test.format", "value": "json"}]}}]},
+	"snapshot-dependencies": {"count": 0},
+	"artifact-dependencies": {"count": 0}
+}
+`
@@ -0,0 +1,252 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// StepDecoder creates a Step from its JSON representation, as returned by the REST API
+type StepDecoder func(data []byte) (Step, error)
+
+// TriggerDecoder creates a Trigger from its JSON representation, as returned by the REST API
+type TriggerDecoder func(data []byte) (Trigger, error)
+
+// BuildFeatureDecoder creates a BuildFeature from its JSON representation, as returned by the REST API
+type BuildFeatureDecoder func(data []byte) (BuildFeature, error)
+
+// ProjectFeatureDecoder creates a ProjectFeature of the project with given id from its JSON representation, as returned by the REST API
+type ProjectFeatureDecoder func(projectID string, data []byte) (ProjectFeature, error)
+
+// VcsRootDecoder creates a VcsRoot from its JSON representation, as returned by the REST API
+type VcsRootDecoder func(data []byte) (VcsRoot, error)
+
+// extensions holds the decoders of the step, trigger, feature and VCS root types known to the services
+var extensions = struct {
+	sync.RWMutex
+	steps           map[string]StepDecoder
+	triggers        map[string]TriggerDecoder
+	buildFeatures   map[string]BuildFeatureDecoder
+	projectFeatures map[string]ProjectFeatureDecoder
+	vcsRoots        map[string]VcsRootDecoder
+}{
+	steps:           make(map[string]StepDecoder),
+	triggers:        make(map[string]TriggerDecoder),
+	buildFeatures:   make(map[string]BuildFeatureDecoder),
+	projectFeatures: make(map[string]ProjectFeatureDecoder),
+	vcsRoots:        make(map[string]VcsRootDecoder),
+}
+
+// RegisterStepType makes the services read build steps of the given type, such as a runner of a TeamCity plugin, with the given decoder.
+// Registering a type again replaces its decoder, including for built-in types.
+func RegisterStepType(stepType string, decode StepDecoder) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	extensions.steps[stepType] = decode
+}
+
+// RegisterTriggerType makes the services read build triggers of the given type with the given decoder.
+// Registering a type again replaces its decoder, including for built-in types.
+func RegisterTriggerType(triggerType string, decode TriggerDecoder) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	extensions.triggers[triggerType] = decode
+}
+
+// RegisterBuildFeatureType makes the services read build features of the given type with the given decoder.
+// Registering a type again replaces its decoder, including for built-in types.
+func RegisterBuildFeatureType(featureType string, decode BuildFeatureDecoder) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	extensions.buildFeatures[featureType] = decode
+}
+
+// RegisterProjectFeatureType makes the services read project features of the given type with the given decoder.
+// Registering a type again replaces its decoder, including for built-in types.
+func RegisterProjectFeatureType(featureType string, decode ProjectFeatureDecoder) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	extensions.projectFeatures[featureType] = decode
+}
+
+// RegisterVcsRootType makes the services read VCS roots with the given vcsName, such as "jetbrains.git", with the given decoder.
+// Registering a type again replaces its decoder, including for built-in types.
+func RegisterVcsRootType(vcsName string, decode VcsRootDecoder) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	extensions.vcsRoots[vcsName] = decode
+}
+
+func init() {
+	RegisterStepType(StepTypePowershell, func(data []byte) (Step, error) {
+		var s StepPowershell
+		return &s, s.UnmarshalJSON(data)
+	})
+	RegisterStepType(StepTypeCommandLine, func(data []byte) (Step, error) {
+		var s StepCommandLine
+		return &s, s.UnmarshalJSON(data)
+	})
+	RegisterStepType(StepTypeOctopusPushPackage, func(data []byte) (Step, error) {
+		var s StepOctopusPushPackage
+		return &s, s.UnmarshalJSON(data)
+	})
+	RegisterStepType(StepTypeOctopusCreateRelease, func(data []byte) (Step, error) {
+		var s StepOctopusCreateRelease
+		return &s, s.UnmarshalJSON(data)
+	})
+
+	RegisterTriggerType(BuildTriggerVcs, func(data []byte) (Trigger, error) {
+		var t TriggerVcs
+		return &t, t.UnmarshalJSON(data)
+	})
+	RegisterTriggerType(BuildTriggerBuildFinish, func(data []byte) (Trigger, error) {
+		var t TriggerBuildFinish
+		return &t, t.UnmarshalJSON(data)
+	})
+	RegisterTriggerType(BuildTriggerSchedule, func(data []byte) (Trigger, error) {
+		var t TriggerSchedule
+		return &t, t.UnmarshalJSON(data)
+	})
+
+	RegisterBuildFeatureType("commit-status-publisher", func(data []byte) (BuildFeature, error) {
+		var f FeatureCommitStatusPublisher
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("golang", func(data []byte) (BuildFeature, error) {
+		var f FeatureGolangPublisher
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("xml-report-plugin", func(data []byte) (BuildFeature, error) {
+		var f FeatureXMLReportProcessing
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("JetBrains.FileContentReplacer", func(data []byte) (BuildFeature, error) {
+		var f FeatureFileContentReplacer
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("swabra", func(data []byte) (BuildFeature, error) {
+		var f FeatureSwabra
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("ssh-agent-build-feature", func(data []byte) (BuildFeature, error) {
+		var f FeatureSSHAgent
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("perfmon", func(data []byte) (BuildFeature, error) {
+		var f FeaturePerformanceMonitor
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("pullRequests", func(data []byte) (BuildFeature, error) {
+		var f FeaturePullRequests
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("AutoMergeFeature", func(data []byte) (BuildFeature, error) {
+		var f FeatureAutoMerge
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("BuildFailureOnMetric", func(data []byte) (BuildFeature, error) {
+		var f FailureConditionOnMetric
+		return &f, f.UnmarshalJSON(data)
+	})
+	RegisterBuildFeatureType("BuildFailureOnMessage", func(data []byte) (BuildFeature, error) {
+		var f FailureConditionOnText
+		return &f, f.UnmarshalJSON(data)
+	})
+
+	RegisterProjectFeatureType("versionedSettings", func(projectID string, data []byte) (ProjectFeature, error) {
+		var feature projectFeatureJSON
+		if err := json.Unmarshal(data, &feature); err != nil {
+			return nil, err
+		}
+		return loadProjectFeatureVersionedSettings(projectID, feature)
+	})
+
+	RegisterVcsRootType(VcsNames.Git, func(data []byte) (VcsRoot, error) {
+		var r GitVcsRoot
+		return &r, r.UnmarshalJSON(data)
+	})
+}
+
+// decodeStep reads a step with the decoder registered for its type
+func decodeStep(data []byte) (Step, error) {
+	var payload stepJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	extensions.RLock()
+	decode, ok := extensions.steps[payload.Type]
+	extensions.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unsupported step type: '%s' (id:'%s')", payload.Type, payload.ID)
+	}
+	return decode(data)
+}
+
+// decodeTrigger reads a trigger with the decoder registered for its type
+func decodeTrigger(data []byte) (Trigger, error) {
+	var payload triggerJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	extensions.RLock()
+	decode, ok := extensions.triggers[payload.Type]
+	extensions.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unsupported trigger type: '%s' (id:'%s')", payload.Type, payload.ID)
+	}
+	return decode(data)
+}
+
+// decodeBuildFeature reads a build feature of the build configuration with given id with the decoder registered for its type
+func decodeBuildFeature(buildTypeID string, data []byte) (BuildFeature, error) {
+	var payload buildFeatureJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	extensions.RLock()
+	decode, ok := extensions.buildFeatures[payload.Type]
+	extensions.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unsupported build feature type: '%s' (id:'%s') for buildTypeID: %s", payload.Type, payload.ID, buildTypeID)
+	}
+	out, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	out.SetBuildTypeID(buildTypeID)
+	return out, nil
+}
+
+// decodeProjectFeature reads a project feature of the project with given id with the decoder registered for its type
+func decodeProjectFeature(projectID string, feature projectFeatureJSON) (ProjectFeature, error) {
+	extensions.RLock()
+	decode, ok := extensions.projectFeatures[feature.Type]
+	extensions.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown project feature type %q", feature.Type)
+	}
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return nil, err
+	}
+	return decode(projectID, data)
+}
+
+// decodeVcsRoot reads a VCS root with the decoder registered for its VCS name
+func decodeVcsRoot(data []byte) (VcsRoot, error) {
+	var payload vcsRootJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	extensions.RLock()
+	decode, ok := extensions.vcsRoots[payload.VcsName]
+	extensions.RUnlock()
+	if !ok {
+		projectID := ""
+		if payload.Project != nil {
+			projectID = payload.Project.ID
+		}
+		return nil, fmt.Errorf("Unsupported VCS Root type: '%s' (id:'%s') for projectID: %s", payload.VcsName, payload.ID, projectID)
+	}
+	return decode(data)
+}
@@ -0,0 +1,103 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepGradle is a step type defined outside of the built-in ones, as an application would for a plugin runner
+type stepGradle struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Tasks string `json:"-"`
+}
+
+func (s *stepGradle) GetID() string   { return s.ID }
+func (s *stepGradle) GetName() string { return s.Name }
+func (s *stepGradle) Type() string    { return "gradle-runner" }
+
+func (s *stepGradle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&stepJSON{
+		ID:         s.ID,
+		Name:       s.Name,
+		Type:       s.Type(),
+		Properties: NewProperties(&Property{Name: "ui.gradleRunner.gradle.tasks.names", Value: s.Tasks}),
+	})
+}
+
+func (s *stepGradle) UnmarshalJSON(data []byte) error {
+	var aux stepJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.ID = aux.ID
+	s.Name = aux.Name
+	s.Tasks, _ = aux.Properties.GetOk("ui.gradleRunner.gradle.tasks.names")
+	return nil
+}
+
+func Test_RegisterStepType(t *testing.T) {
+	require := require.New(t)
+	RegisterStepType("gradle-runner", func(data []byte) (Step, error) {
+		var s stepGradle
+		return &s, s.UnmarshalJSON(data)
+	})
+
+	bt, err := NewBuildType("Project", "Build")
+	require.NoError(err)
+	bt.Steps = []Step{&stepGradle{ID: "RUNNER_1", Name: "build", Tasks: "clean build"}}
+	var payload struct {
+		Steps json.RawMessage `json:"steps"`
+	}
+	dt, err := json.Marshal(bt)
+	require.NoError(err)
+	require.NoError(json.Unmarshal(dt, &payload))
+
+	var steps []Step
+	require.NoError(stepsReadingFunc(payload.Steps, &steps))
+	require.Len(steps, 1)
+	assert.Equal(t, &stepGradle{ID: "RUNNER_1", Name: "build", Tasks: "clean build"}, steps[0])
+}
+
+func Test_UnregisteredTypesAreUnsupported(t *testing.T) {
+	_, err := decodeStep([]byte(`{"id":"RUNNER_1","type":"maven2"}`))
+	assert.EqualError(t, err, "Unsupported step type: 'maven2' (id:'RUNNER_1')")
+
+	_, err = decodeVcsRoot([]byte(`{"id":"Svn","vcsName":"svn"}`))
+	assert.EqualError(t, err, "Unsupported VCS Root type: 'svn' (id:'Svn') for projectID: ")
+}
+
+type triggerRetry struct {
+	triggerJSON
+}
+
+func (t *triggerRetry) ID() string   { return t.triggerJSON.ID }
+func (t *triggerRetry) Type() string { return t.triggerJSON.Type }
+func (t *triggerRetry) Disabled() bool {
+	return t.triggerJSON.Disabled != nil && *t.triggerJSON.Disabled
+}
+func (t *triggerRetry) SetBuildTypeID(buildTypeID string) { t.triggerJSON.BuildTypeID = buildTypeID }
+func (t *triggerRetry) BuildTypeID() string               { return t.triggerJSON.BuildTypeID }
+
+func Test_RegisterTriggerTypeIsUsedByService(t *testing.T) {
+	require := require.New(t)
+	RegisterTriggerType("retryBuildTrigger", func(data []byte) (Trigger, error) {
+		var out triggerRetry
+		return &out, json.Unmarshal(data, &out.triggerJSON)
+	})
+
+	client, _ := newRecordingServer(t, map[string]string{
+		"/httpAuth/app/rest/buildTypes/Project_Build/triggers/TRIGGER_1": `{"id":"TRIGGER_1","type":"retryBuildTrigger","properties":{"count":1,"property":[{"name":"retryAttempts","value":"2"}]}}`,
+	})
+
+	actual, err := client.TriggerService("Project_Build").GetByID("TRIGGER_1")
+	require.NoError(err)
+	require.IsType(&triggerRetry{}, actual)
+	assert.Equal(t, "TRIGGER_1", actual.ID())
+	assert.Equal(t, "Project_Build", actual.BuildTypeID())
+	value, _ := actual.(*triggerRetry).Properties.GetOk("retryAttempts")
+	assert.Equal(t, "2", value)
+}
@@ -77,6 +77,30 @@ func (r *restHelper) getWithFields(path string, fields getFields, out interface{
 	return r.handleRestError(dt, response.StatusCode, "GET", resourceDescription)
 }
 
+// getStream performs a GET request and hands the response body to the caller without buffering it.
+// The caller is responsible for closing the returned io.ReadCloser.
+func (r *restHelper) getStream(path string, accept string, resourceDescription string) (io.ReadCloser, error) {
+	request, err := r.sling.New().Get(path).Set("Accept", accept).Request()
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == 200 {
+		return response.Body, nil
+	}
+
+	defer response.Body.Close()
+	dt, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.handleRestError(dt, response.StatusCode, "GET", resourceDescription)
+}
+
 func (r *restHelper) putCustom(path string, data interface{}, out interface{}, resourceDescription string, reader responseReadFunc) error {
 	request, _ := r.sling.New().Put(path).BodyJSON(data).Request()
 	response, err := r.httpClient.Do(request)
@@ -126,8 +150,24 @@ func (r *restHelper) postCustom(path string, data interface{}, out interface{},
 }
 
 func (r *restHelper) putTextPlain(path string, data string, resourceDescription string) (string, error) {
-	req, err := r.sling.New().Put(path).
-		BodyProvider(textPlainBodyProvider{payload: data}).
+	return r.sendTextPlain("PUT", path, data, resourceDescription)
+}
+
+func (r *restHelper) postTextPlain(path string, data string, resourceDescription string) (string, error) {
+	return r.sendTextPlain("POST", path, data, resourceDescription)
+}
+
+func (r *restHelper) sendTextPlain(method string, path string, data string, resourceDescription string) (string, error) {
+	var s *sling.Sling
+	switch method {
+	case "POST":
+		s = r.sling.New().Post(path)
+	case "PUT":
+		s = r.sling.New().Put(path)
+	default:
+		return "", fmt.Errorf("unsupported method '%s'", method)
+	}
+	req, err := s.BodyProvider(textPlainBodyProvider{payload: data}).
 		Add("Accept", "text/plain").
 		Request()
 
@@ -145,11 +185,11 @@ func (r *restHelper) putTextPlain(path string, data string, resourceDescription
 		return "", err
 	}
 
-	if resp.StatusCode == 201 || resp.StatusCode == 200 {
+	if resp.StatusCode == 201 || resp.StatusCode == 200 || resp.StatusCode == 204 {
 		return string(bodyBytes), nil
 	}
 
-	return "", r.handleRestError(bodyBytes, resp.StatusCode, "PUT", resourceDescription)
+	return "", r.handleRestError(bodyBytes, resp.StatusCode, method, resourceDescription)
 }
 
 func (r *restHelper) post(path string, data interface{}, out interface{}, resourceDescription string) error {
@@ -190,6 +230,42 @@ func (r *restHelper) put(path string, data interface{}, out interface{}, resourc
 	return r.handleRestError(dt, response.StatusCode, "PUT", resourceDescription)
 }
 
+// send performs a request for operations that don't return a representation, such as actions on a resource.
+// A nil data sends no body.
+func (r *restHelper) send(method string, path string, data interface{}, resourceDescription string) error {
+	var s *sling.Sling
+	switch method {
+	case "POST":
+		s = r.sling.New().Post(path)
+	case "PUT":
+		s = r.sling.New().Put(path)
+	default:
+		return fmt.Errorf("unsupported method '%s'", method)
+	}
+	if data != nil {
+		s = s.BodyJSON(data)
+	}
+
+	request, err := s.Request()
+	if err != nil {
+		return err
+	}
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == 200 || response.StatusCode == 201 || response.StatusCode == 204 {
+		return nil
+	}
+	dt, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return r.handleRestError(dt, response.StatusCode, method, resourceDescription)
+}
+
 func (r *restHelper) delete(path string, resourceDescription string) error {
 	return r.deleteByIDWithSling(r.sling, path, resourceDescription)
 }
@@ -221,6 +297,16 @@ func (r *restHelper) handleRestError(dt []byte, status int, op string, res strin
 	return fmt.Errorf("Error '%d' when performing '%s' operation - %s: %s", status, op, res, string(dt))
 }
 
+// isNotFoundError returns true if err was returned by handleRestError for a 404 response
+func isNotFoundError(err error) bool {
+	return isStatusError(err, 404)
+}
+
+// isStatusError returns true if err was returned by handleRestError for a response with given status
+func isStatusError(err error, status int) bool {
+	return err != nil && strings.HasPrefix(err.Error(), fmt.Sprintf("Error '%d'", status))
+}
+
 func replaceValue(i, v interface{}) {
 	val := reflect.ValueOf(i)
 	if val.Kind() != reflect.Ptr {
@@ -0,0 +1,109 @@
+package teamcity
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dghubble/sling"
+)
+
+// secureTokenPrefix is the prefix of references to secure tokens, which TeamCity replaces by their values
+const secureTokenPrefix = "credentialsJSON:"
+
+// IsSecureToken returns true if v is a reference to a secure token, such as "credentialsJSON:0b803f24-..."
+func IsSecureToken(v string) bool {
+	return strings.HasPrefix(v, secureTokenPrefix) && len(v) > len(secureTokenPrefix)
+}
+
+// SecureTokenService provides operations for managing the secure tokens of a project.
+// A secure token stores a secret value in the project, and is referenced as "credentialsJSON:<uuid>" in password parameters and settings,
+// such as the ones stored in VCS with CredentialsStorageTypeCredentialsJSON.
+type SecureTokenService struct {
+	ProjectID  string
+	httpClient *http.Client
+	base       *sling.Sling
+	restHelper *restHelper
+}
+
+func newSecureTokenService(projectID string, c *http.Client, base *sling.Sling) *SecureTokenService {
+	sling := base.New().Path(fmt.Sprintf("projects/%s/secure/", LocatorID(projectID)))
+	return &SecureTokenService{
+		ProjectID:  projectID,
+		httpClient: c,
+		base:       sling,
+		restHelper: newRestHelper(c, sling),
+	}
+}
+
+// Create stores value as a secure token of the project, and returns its reference, such as "credentialsJSON:0b803f24-..."
+func (s *SecureTokenService) Create(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("value is required")
+	}
+	token, err := s.restHelper.postTextPlain("tokens", value, fmt.Sprintf("secure token of project '%s'", s.ProjectID))
+	if err != nil {
+		return "", err
+	}
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, secureTokenPrefix) {
+		token = secureTokenPrefix + token
+	}
+	return token, nil
+}
+
+// Resolve returns the value of a secure token of the project. It requires the permission to manage the server settings.
+func (s *SecureTokenService) Resolve(token string) (string, error) {
+	if !IsSecureToken(token) {
+		return "", fmt.Errorf("invalid secure token '%s', must start with '%s'", token, secureTokenPrefix)
+	}
+	body, err := s.restHelper.getStream("values/"+url.PathEscape(strings.TrimPrefix(token, secureTokenPrefix)), "text/plain", fmt.Sprintf("secure token of project '%s'", s.ProjectID))
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	value, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// PasswordParameter stores value as a secure token of the project, and returns a password parameter referencing it
+func (s *SecureTokenService) PasswordParameter(t string, name string, value string) (*Parameter, error) {
+	token, err := s.Create(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewPasswordParameterFromToken(t, name, token)
+}
+
+// SetGitPassword stores value as a secure token of the project, and sets the secret of the Git VCS root options to a reference to it.
+// The secret depends on AuthMethod: the password for GitAuthMethodPassword, or the key passphrase for GitAuthSSHUploadedKey and GitAuthSSHCustomKey,
+// both held by the Password field. An error is returned for other methods, which have no secret.
+func (s *SecureTokenService) SetGitPassword(opt *GitVcsRootOptions, value string) error {
+	if opt == nil {
+		return fmt.Errorf("opt can't be nil")
+	}
+	switch opt.AuthMethod {
+	case GitAuthMethodPassword, GitAuthSSHUploadedKey, GitAuthSSHCustomKey:
+	default:
+		return fmt.Errorf("auth method '%s' has no password or key passphrase", opt.AuthMethod)
+	}
+	token, err := s.Create(value)
+	if err != nil {
+		return err
+	}
+	opt.Password = token
+	return nil
+}
+
+// NewPasswordParameterFromToken creates a password parameter whose value is the secure token with given reference, so that its value is never sent in plain text
+func NewPasswordParameterFromToken(t string, name string, token string) (*Parameter, error) {
+	if !IsSecureToken(token) {
+		return nil, fmt.Errorf("invalid secure token '%s', must start with '%s'", token, secureTokenPrefix)
+	}
+	return NewParameterWithSpec(t, name, token, &ParameterSpec{Type: ParameterSpecPassword, Display: ParameterDisplayHidden})
+}
@@ -0,0 +1,68 @@
+package teamcity
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SecureTokenService(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "POST /httpAuth/app/rest/projects/id%3AProj/secure/tokens":
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "text/plain; charset=utf-8" || string(body) != "s3cr3t" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("0b803f24-9fb8-4b3f-a4d5-0a0a6cd0f4e7"))
+		case "GET /httpAuth/app/rest/projects/id%3AProj/secure/values/0b803f24-9fb8-4b3f-a4d5-0a0a6cd0f4e7":
+			w.Write([]byte("s3cr3t"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	sut := client.SecureTokenService("Proj")
+
+	token, err := sut.Create("s3cr3t")
+	require.NoError(err)
+	assert.Equal(t, "credentialsJSON:0b803f24-9fb8-4b3f-a4d5-0a0a6cd0f4e7", token)
+	assert.True(t, IsSecureToken(token))
+
+	value, err := sut.Resolve(token)
+	require.NoError(err)
+	assert.Equal(t, "s3cr3t", value)
+
+	_, err = sut.Resolve("s3cr3t")
+	assert.EqualError(t, err, "invalid secure token 's3cr3t', must start with 'credentialsJSON:'")
+
+	param, err := sut.PasswordParameter(ParameterTypes.EnvironmentVariable, "TOKEN", "s3cr3t")
+	require.NoError(err)
+	assert.Equal(t, token, param.Value)
+	assert.True(t, param.IsPassword())
+	assert.Equal(t, "password display='hidden'", param.Property().Type.RawValue)
+
+	opt, err := NewGitVcsRootOptions("refs/heads/main", "https://github.com/org/repo", "", GitAuthMethodPassword, "ci", "")
+	require.NoError(err)
+	require.NoError(sut.SetGitPassword(opt, "s3cr3t"))
+	assert.Equal(t, token, opt.Password)
+
+	// The Password field holds the key passphrase of SSH keys
+	sshKey := &GitVcsRootOptions{AuthMethod: GitAuthSSHUploadedKey, PrivateKeySource: "deploy"}
+	require.NoError(sut.SetGitPassword(sshKey, "s3cr3t"))
+	assert.Equal(t, token, sshKey.Password)
+
+	anonymous, err := NewGitVcsRootOptionsDefaults("refs/heads/main", "https://github.com/org/repo")
+	require.NoError(err)
+	assert.EqualError(t, sut.SetGitPassword(anonymous, "s3cr3t"), "auth method 'ANONYMOUS' has no password or key passphrase")
+	assert.Empty(t, anonymous.Password)
+}
+
+func Test_NewPasswordParameterFromToken(t *testing.T) {
+	_, err := NewPasswordParameterFromToken(ParameterTypes.Configuration, "password", "plaintext")
+	assert.EqualError(t, err, "invalid secure token 'plaintext', must start with 'credentialsJSON:'")
+}
@@ -0,0 +1,279 @@
+package teamcity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const serviceMessagePrefix = "##teamcity["
+
+// ServiceMessage represents a TeamCity service message such as "##teamcity[testStarted name='MyTest']".
+// Messages in single-attribute form, like "##teamcity[buildNumber '1.2.3']", have Argument set instead of Attributes.
+// See https://www.jetbrains.com/help/teamcity/service-messages.html
+type ServiceMessage struct {
+	Name       string
+	Argument   string
+	Attributes map[string]string
+}
+
+// ServiceMessageEvent is implemented by the typed events returned by ServiceMessage.Event.
+// Use a type switch to handle the concrete event types.
+type ServiceMessageEvent interface {
+	MessageName() string
+}
+
+// TestStartedEvent is emitted by the "testStarted" service message
+type TestStartedEvent struct {
+	Name                  string
+	CaptureStandardOutput bool
+}
+
+// TestFinishedEvent is emitted by the "testFinished" service message. Duration is in milliseconds.
+type TestFinishedEvent struct {
+	Name     string
+	Duration int64
+}
+
+// TestFailedEvent is emitted by the "testFailed" service message
+type TestFailedEvent struct {
+	Name     string
+	Message  string
+	Details  string
+	Expected string
+	Actual   string
+}
+
+// TestIgnoredEvent is emitted by the "testIgnored" service message
+type TestIgnoredEvent struct {
+	Name    string
+	Message string
+}
+
+// BuildProblemEvent is emitted by the "buildProblem" service message
+type BuildProblemEvent struct {
+	Description string
+	Identity    string
+}
+
+// BuildStatusEvent is emitted by the "buildStatus" service message
+type BuildStatusEvent struct {
+	Status string
+	Text   string
+}
+
+// BuildNumberEvent is emitted by the "buildNumber" service message
+type BuildNumberEvent struct {
+	Number string
+}
+
+// SetParameterEvent is emitted by the "setParameter" service message
+type SetParameterEvent struct {
+	Name  string
+	Value string
+}
+
+// MessageEvent is emitted by the "message" service message. Status is one of NORMAL, WARNING, FAILURE or ERROR.
+type MessageEvent struct {
+	Text         string
+	Status       string
+	ErrorDetails string
+}
+
+// BlockOpenedEvent is emitted by the "blockOpened" service message
+type BlockOpenedEvent struct {
+	Name        string
+	Description string
+}
+
+// BlockClosedEvent is emitted by the "blockClosed" service message
+type BlockClosedEvent struct {
+	Name string
+}
+
+// ProgressMessageEvent is emitted by the "progressMessage" service message
+type ProgressMessageEvent struct {
+	Text string
+}
+
+// PublishArtifactsEvent is emitted by the "publishArtifacts" service message
+type PublishArtifactsEvent struct {
+	Paths string
+}
+
+// MessageName returns "testStarted"
+func (e *TestStartedEvent) MessageName() string { return "testStarted" }
+
+// MessageName returns "testFinished"
+func (e *TestFinishedEvent) MessageName() string { return "testFinished" }
+
+// MessageName returns "testFailed"
+func (e *TestFailedEvent) MessageName() string { return "testFailed" }
+
+// MessageName returns "testIgnored"
+func (e *TestIgnoredEvent) MessageName() string { return "testIgnored" }
+
+// MessageName returns "buildProblem"
+func (e *BuildProblemEvent) MessageName() string { return "buildProblem" }
+
+// MessageName returns "buildStatus"
+func (e *BuildStatusEvent) MessageName() string { return "buildStatus" }
+
+// MessageName returns "buildNumber"
+func (e *BuildNumberEvent) MessageName() string { return "buildNumber" }
+
+// MessageName returns "setParameter"
+func (e *SetParameterEvent) MessageName() string { return "setParameter" }
+
+// MessageName returns "message"
+func (e *MessageEvent) MessageName() string { return "message" }
+
+// MessageName returns "blockOpened"
+func (e *BlockOpenedEvent) MessageName() string { return "blockOpened" }
+
+// MessageName returns "blockClosed"
+func (e *BlockClosedEvent) MessageName() string { return "blockClosed" }
+
+// MessageName returns "progressMessage"
+func (e *ProgressMessageEvent) MessageName() string { return "progressMessage" }
+
+// MessageName returns "publishArtifacts"
+func (e *PublishArtifactsEvent) MessageName() string { return "publishArtifacts" }
+
+// Event converts this message to one of the typed ServiceMessageEvent structs.
+// Returns nil for message names that have no typed representation; the raw message can still be used in that case.
+func (m *ServiceMessage) Event() ServiceMessageEvent {
+	a := m.Attributes
+	switch m.Name {
+	case "testStarted":
+		capture, _ := strconv.ParseBool(a["captureStandardOutput"])
+		return &TestStartedEvent{Name: a["name"], CaptureStandardOutput: capture}
+	case "testFinished":
+		duration, _ := strconv.ParseInt(a["duration"], 10, 64)
+		return &TestFinishedEvent{Name: a["name"], Duration: duration}
+	case "testFailed":
+		return &TestFailedEvent{Name: a["name"], Message: a["message"], Details: a["details"], Expected: a["expected"], Actual: a["actual"]}
+	case "testIgnored":
+		return &TestIgnoredEvent{Name: a["name"], Message: a["message"]}
+	case "buildProblem":
+		return &BuildProblemEvent{Description: a["description"], Identity: a["identity"]}
+	case "buildStatus":
+		return &BuildStatusEvent{Status: a["status"], Text: a["text"]}
+	case "buildNumber":
+		return &BuildNumberEvent{Number: m.Argument}
+	case "setParameter":
+		return &SetParameterEvent{Name: a["name"], Value: a["value"]}
+	case "message":
+		return &MessageEvent{Text: a["text"], Status: a["status"], ErrorDetails: a["errorDetails"]}
+	case "blockOpened":
+		return &BlockOpenedEvent{Name: a["name"], Description: a["description"]}
+	case "blockClosed":
+		return &BlockClosedEvent{Name: a["name"]}
+	case "progressMessage":
+		return &ProgressMessageEvent{Text: m.Argument}
+	case "publishArtifacts":
+		return &PublishArtifactsEvent{Paths: m.Argument}
+	}
+	return nil
+}
+
+// ParseServiceMessage looks for a service message in the given log line and parses it.
+// Returns false if the line does not contain a service message, and an error if the message is malformed.
+func ParseServiceMessage(line string) (*ServiceMessage, bool, error) {
+	start := strings.Index(line, serviceMessagePrefix)
+	if start < 0 {
+		return nil, false, nil
+	}
+	rest := line[start+len(serviceMessagePrefix):]
+
+	nameEnd := strings.IndexAny(rest, " ]")
+	if nameEnd <= 0 {
+		return nil, true, fmt.Errorf("service message has no name: %q", line)
+	}
+	out := &ServiceMessage{
+		Name:       rest[:nameEnd],
+		Attributes: make(map[string]string),
+	}
+	rest = strings.TrimLeft(rest[nameEnd:], " ")
+
+	for {
+		if strings.HasPrefix(rest, "]") {
+			return out, true, nil
+		}
+		if rest == "" {
+			return nil, true, fmt.Errorf("unterminated service message: %q", line)
+		}
+
+		if rest[0] == '\'' {
+			value, remaining, err := readServiceMessageValue(rest)
+			if err != nil {
+				return nil, true, fmt.Errorf("%s: %q", err, line)
+			}
+			out.Argument = value
+			rest = strings.TrimLeft(remaining, " ")
+			continue
+		}
+
+		eq := strings.Index(rest, "=")
+		if eq <= 0 {
+			return nil, true, fmt.Errorf("invalid attribute in service message: %q", line)
+		}
+		key := strings.TrimSpace(rest[:eq])
+		value, remaining, err := readServiceMessageValue(rest[eq+1:])
+		if err != nil {
+			return nil, true, fmt.Errorf("%s: %q", err, line)
+		}
+		out.Attributes[key] = value
+		rest = strings.TrimLeft(remaining, " ")
+	}
+}
+
+// readServiceMessageValue reads a single quoted value, handling the '|' escape sequences used by TeamCity
+func readServiceMessageValue(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "'") {
+		return "", "", fmt.Errorf("expected quoted value in service message")
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\'':
+			return sb.String(), s[i+1:], nil
+		case '|':
+			i++
+			if i >= len(s) {
+				return "", "", fmt.Errorf("invalid escape sequence in service message")
+			}
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'x':
+				sb.WriteRune('\u0085')
+			case 'l':
+				sb.WriteRune('\u2028')
+			case 'p':
+				sb.WriteRune('\u2029')
+			case '0':
+				if i+5 < len(s) && s[i+1] == 'x' {
+					r, err := strconv.ParseUint(s[i+2:i+6], 16, 32)
+					if err != nil {
+						return "", "", fmt.Errorf("invalid unicode escape in service message")
+					}
+					sb.WriteRune(rune(r))
+					i += 5
+				} else {
+					sb.WriteByte('0')
+				}
+			default:
+				// |' |[ |] and || are escaped literally
+				sb.WriteByte(s[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated value in service message")
+}
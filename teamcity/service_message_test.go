@@ -0,0 +1,57 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseServiceMessageAttributes(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	msg, ok, err := ParseServiceMessage("[10:01:02]i: ##teamcity[testFailed name='pkg.TestFoo' message='expected |'a|' got |[b|]' details='line1|nline2']")
+	require.NoError(err)
+	require.True(ok)
+
+	assert.Equal("testFailed", msg.Name)
+	assert.Equal("pkg.TestFoo", msg.Attributes["name"])
+	assert.Equal("expected 'a' got [b]", msg.Attributes["message"])
+	assert.Equal("line1\nline2", msg.Attributes["details"])
+
+	event, isType := msg.Event().(*TestFailedEvent)
+	require.True(isType)
+	assert.Equal("pkg.TestFoo", event.Name)
+	assert.Equal("line1\nline2", event.Details)
+}
+
+func Test_ParseServiceMessageArgument(t *testing.T) {
+	require := require.New(t)
+
+	msg, ok, err := ParseServiceMessage("##teamcity[buildNumber '1.2.|0x00e9']")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("1.2.é", msg.Argument)
+	require.Equal(&BuildNumberEvent{Number: "1.2.é"}, msg.Event())
+}
+
+func Test_ParseServiceMessageNotFound(t *testing.T) {
+	msg, ok, err := ParseServiceMessage("[10:01:02]i: Step 1/2: Build")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, msg)
+}
+
+func Test_ParseServiceMessageMalformed(t *testing.T) {
+	_, ok, err := ParseServiceMessage("##teamcity[message text='unterminated")
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
+
+func Test_ServiceMessageUnknownEvent(t *testing.T) {
+	msg, _, err := ParseServiceMessage("##teamcity[customMessage key='value']")
+	require.NoError(t, err)
+	assert.Nil(t, msg.Event())
+	assert.Equal(t, "value", msg.Attributes["key"])
+}
@@ -0,0 +1,38 @@
+package teamcity
+
+import "fmt"
+
+// StatusPublisherAzureDevOpsOptions represents parameters used to create Azure DevOps Commit Status Publisher Feature
+type StatusPublisherAzureDevOpsOptions struct {
+	//ServerURL is the Azure DevOps Server URL, for instance "https://dev.azure.com/organization". Detected from the VCS root URL if empty.
+	ServerURL string `prop:"tfsServerUrl"`
+	//AccessToken is a personal access token with the 'Code (status)' and 'Code (read)' scopes
+	AccessToken string `prop:"secure:tfsAccessToken"`
+	//PublishPullRequests also publishes the status of pull requests builds
+	PublishPullRequests bool `prop:"tfsPublishPullRequests"`
+}
+
+// NewFeatureCommitStatusPublisherAzureDevOps creates a Build Feature Commit status Publisher to Azure DevOps with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherAzureDevOps(opt StatusPublisherAzureDevOpsOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.AccessToken == "" {
+		return nil, fmt.Errorf("AccessToken is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherAzureDevOpsOptions) Properties() *Properties {
+	props := statusPublisherProperties(publisherIDAzureDevOps, &s)
+	props.AddOrReplaceValue("tfsAuthType", "token")
+	return props
+}
+
+// CommitStatusPublisherAzureDevOpsOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherAzureDevOpsOptions
+func CommitStatusPublisherAzureDevOpsOptionsFromProperties(p *Properties) (*StatusPublisherAzureDevOpsOptions, error) {
+	var out StatusPublisherAzureDevOpsOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,26 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureCommitPublisher_AzureDevOps(t *testing.T) {
+	t.Run("AccessToken Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherAzureDevOps(teamcity.StatusPublisherAzureDevOpsOptions{}, "")
+		require.EqualError(t, err, "AccessToken is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		opt := teamcity.StatusPublisherAzureDevOpsOptions{AccessToken: "1234", PublishPullRequests: true}
+		assert.Equal(t, map[string]string{
+			"publisherId":            "tfs",
+			"tfsAuthType":            "token",
+			"secure:tfsAccessToken":  "1234",
+			"tfsPublishPullRequests": "true",
+		}, opt.Properties().Map())
+	})
+}
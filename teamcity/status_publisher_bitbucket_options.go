@@ -0,0 +1,70 @@
+package teamcity
+
+import "fmt"
+
+// StatusPublisherBitbucketCloudOptions represents parameters used to create Bitbucket Cloud Commit Status Publisher Feature
+type StatusPublisherBitbucketCloudOptions struct {
+	//Username of the Bitbucket Cloud account
+	Username string `prop:"bitbucketUsername"`
+	//Password is an app password of the account with the 'repository:write' permission
+	Password string `prop:"secure:bitbucketPassword"`
+}
+
+// NewFeatureCommitStatusPublisherBitbucketCloud creates a Build Feature Commit status Publisher to Bitbucket Cloud with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherBitbucketCloud(opt StatusPublisherBitbucketCloudOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.Username == "" || opt.Password == "" {
+		return nil, fmt.Errorf("Username/Password is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherBitbucketCloudOptions) Properties() *Properties {
+	return statusPublisherProperties(publisherIDBitbucketCloud, &s)
+}
+
+// CommitStatusPublisherBitbucketCloudOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherBitbucketCloudOptions
+func CommitStatusPublisherBitbucketCloudOptionsFromProperties(p *Properties) (*StatusPublisherBitbucketCloudOptions, error) {
+	var out StatusPublisherBitbucketCloudOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StatusPublisherBitbucketServerOptions represents parameters used to create Bitbucket Server (formerly Stash) Commit Status Publisher Feature
+type StatusPublisherBitbucketServerOptions struct {
+	//URL is the Bitbucket Server URL, for instance "https://bitbucket.example.com"
+	URL string `prop:"stashBaseUrl"`
+	//Username of an account with write access to the repositories
+	Username string `prop:"stashUsername"`
+	//Password or personal access token of the account
+	Password string `prop:"secure:stashPassword"`
+}
+
+// NewFeatureCommitStatusPublisherBitbucketServer creates a Build Feature Commit status Publisher to Bitbucket Server with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherBitbucketServer(opt StatusPublisherBitbucketServerOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+	if opt.Username == "" || opt.Password == "" {
+		return nil, fmt.Errorf("Username/Password is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherBitbucketServerOptions) Properties() *Properties {
+	return statusPublisherProperties(publisherIDBitbucketServer, &s)
+}
+
+// CommitStatusPublisherBitbucketServerOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherBitbucketServerOptions
+func CommitStatusPublisherBitbucketServerOptionsFromProperties(p *Properties) (*StatusPublisherBitbucketServerOptions, error) {
+	var out StatusPublisherBitbucketServerOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,46 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureCommitPublisher_BitbucketCloud(t *testing.T) {
+	t.Run("Password Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherBitbucketCloud(teamcity.StatusPublisherBitbucketCloudOptions{Username: "bob"}, "")
+		require.EqualError(t, err, "Username/Password is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		opt := teamcity.StatusPublisherBitbucketCloudOptions{Username: "bob", Password: "1234"}
+		assert.Equal(t, map[string]string{
+			"publisherId":              "bitbucketCloudPublisher",
+			"bitbucketUsername":        "bob",
+			"secure:bitbucketPassword": "1234",
+		}, opt.Properties().Map())
+	})
+}
+
+func TestFeatureCommitPublisher_BitbucketServer(t *testing.T) {
+	t.Run("URL Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherBitbucketServer(teamcity.StatusPublisherBitbucketServerOptions{Username: "bob", Password: "1234"}, "")
+		require.EqualError(t, err, "URL is required")
+	})
+	t.Run("Username Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherBitbucketServer(teamcity.StatusPublisherBitbucketServerOptions{URL: "https://bitbucket.example.com", Password: "1234"}, "")
+		require.EqualError(t, err, "Username/Password is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		opt := teamcity.StatusPublisherBitbucketServerOptions{URL: "https://bitbucket.example.com", Username: "bob", Password: "1234"}
+		assert.Equal(t, map[string]string{
+			"publisherId":          "atlassianStashPublisher",
+			"stashBaseUrl":         "https://bitbucket.example.com",
+			"stashUsername":        "bob",
+			"secure:stashPassword": "1234",
+		}, opt.Properties().Map())
+	})
+}
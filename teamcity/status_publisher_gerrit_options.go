@@ -0,0 +1,54 @@
+package teamcity
+
+import "fmt"
+
+// StatusPublisherGerritOptions represents parameters used to create Gerrit Commit Status Publisher Feature, which votes on the changes built
+type StatusPublisherGerritOptions struct {
+	//Server is the Gerrit SSH server, as host[:port]
+	Server string `prop:"gerritServer"`
+	//Project is the name of the Gerrit project
+	Project string `prop:"gerritProject"`
+	//Username of the account used to vote
+	Username string `prop:"gerritUsername"`
+	//KeyName is the name of a SSH key uploaded to the project, used to connect to the server
+	KeyName string `prop:"teamcitySshKey"`
+	//SuccessVote is the vote for successful builds, for instance "+1"
+	SuccessVote string `prop:"successVote"`
+	//FailureVote is the vote for failed builds, for instance "-1"
+	FailureVote string `prop:"failureVote"`
+}
+
+// NewFeatureCommitStatusPublisherGerrit creates a Build Feature Commit status Publisher to Gerrit with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherGerrit(opt StatusPublisherGerritOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.Server == "" {
+		return nil, fmt.Errorf("Server is required")
+	}
+	if opt.Project == "" {
+		return nil, fmt.Errorf("Project is required")
+	}
+	if opt.Username == "" {
+		return nil, fmt.Errorf("Username is required")
+	}
+	if opt.KeyName == "" {
+		return nil, fmt.Errorf("KeyName is required")
+	}
+	if opt.SuccessVote == "" || opt.FailureVote == "" {
+		return nil, fmt.Errorf("SuccessVote/FailureVote is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherGerritOptions) Properties() *Properties {
+	return statusPublisherProperties(publisherIDGerrit, &s)
+}
+
+// CommitStatusPublisherGerritOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherGerritOptions
+func CommitStatusPublisherGerritOptionsFromProperties(p *Properties) (*StatusPublisherGerritOptions, error) {
+	var out StatusPublisherGerritOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,45 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureCommitPublisher_Gerrit(t *testing.T) {
+	valid := teamcity.StatusPublisherGerritOptions{
+		Server:      "gerrit.example.com:29418",
+		Project:     "backend",
+		Username:    "ci",
+		KeyName:     "gerrit_key",
+		SuccessVote: "+1",
+		FailureVote: "-1",
+	}
+
+	t.Run("KeyName Required", func(t *testing.T) {
+		opt := valid
+		opt.KeyName = ""
+		_, err := teamcity.NewFeatureCommitStatusPublisherGerrit(opt, "")
+		require.EqualError(t, err, "KeyName is required")
+	})
+	t.Run("Votes Required", func(t *testing.T) {
+		opt := valid
+		opt.FailureVote = ""
+		_, err := teamcity.NewFeatureCommitStatusPublisherGerrit(opt, "")
+		require.EqualError(t, err, "SuccessVote/FailureVote is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		assert.Equal(t, map[string]string{
+			"publisherId":    "gerritStatusPublisher",
+			"gerritServer":   "gerrit.example.com:29418",
+			"gerritProject":  "backend",
+			"gerritUsername": "ci",
+			"teamcitySshKey": "gerrit_key",
+			"successVote":    "+1",
+			"failureVote":    "-1",
+		}, valid.Properties().Map())
+	})
+}
@@ -62,23 +62,14 @@ func NewFeatureCommitStatusPublisherGithub(opt StatusPublisherGithubOptions, vcs
 		}
 	}
 
-	out := &FeatureCommitStatusPublisher{
-		Options:    opt,
-		properties: opt.Properties(),
-	}
-
-	if vcsRootID != "" {
-		out.vcsRootID = vcsRootID
-	}
-
-	return out, nil
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
 }
 
 // Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
 func (s StatusPublisherGithubOptions) Properties() *Properties {
 	props := NewPropertiesEmpty()
 
-	props.AddOrReplaceValue("publisherId", "githubStatusPublisher")
+	props.AddOrReplaceValue("publisherId", publisherIDGithub)
 	props.AddOrReplaceValue("github_authentication_type", s.AuthenticationType)
 	props.AddOrReplaceValue("github_host", s.Host)
 
@@ -0,0 +1,37 @@
+package teamcity
+
+import "fmt"
+
+// StatusPublisherGitlabOptions represents parameters used to create GitLab Commit Status Publisher Feature
+type StatusPublisherGitlabOptions struct {
+	//APIURL is the GitLab API URL, for instance "https://gitlab.com/api/v4"
+	APIURL string `prop:"gitlabApiUrl"`
+	//AccessToken is a personal access token with the 'api' scope
+	AccessToken string `prop:"secure:gitlabAccessToken"`
+}
+
+// NewFeatureCommitStatusPublisherGitlab creates a Build Feature Commit status Publisher to GitLab with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherGitlab(opt StatusPublisherGitlabOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.APIURL == "" {
+		return nil, fmt.Errorf("APIURL is required")
+	}
+	if opt.AccessToken == "" {
+		return nil, fmt.Errorf("AccessToken is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherGitlabOptions) Properties() *Properties {
+	return statusPublisherProperties(publisherIDGitlab, &s)
+}
+
+// CommitStatusPublisherGitlabOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherGitlabOptions
+func CommitStatusPublisherGitlabOptionsFromProperties(p *Properties) (*StatusPublisherGitlabOptions, error) {
+	var out StatusPublisherGitlabOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,33 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureCommitPublisher_Gitlab(t *testing.T) {
+	t.Run("APIURL Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherGitlab(teamcity.StatusPublisherGitlabOptions{AccessToken: "1234"}, "")
+		require.EqualError(t, err, "APIURL is required")
+	})
+	t.Run("AccessToken Required", func(t *testing.T) {
+		_, err := teamcity.NewFeatureCommitStatusPublisherGitlab(teamcity.StatusPublisherGitlabOptions{APIURL: "https://gitlab.com/api/v4"}, "")
+		require.EqualError(t, err, "AccessToken is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		opt := teamcity.StatusPublisherGitlabOptions{APIURL: "https://gitlab.com/api/v4", AccessToken: "1234"}
+		actual, err := teamcity.NewFeatureCommitStatusPublisherGitlab(opt, "Project_VcsRootId")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Project_VcsRootId", actual.VcsRootID())
+		assert.Equal(t, map[string]string{
+			"publisherId":              "gitlabStatusPublisher",
+			"gitlabApiUrl":             "https://gitlab.com/api/v4",
+			"secure:gitlabAccessToken": "1234",
+		}, actual.Properties().Map())
+	})
+}
@@ -0,0 +1,48 @@
+package teamcity
+
+import "fmt"
+
+// StatusPublisherSpaceOptions represents parameters used to create JetBrains Space Commit Status Publisher Feature
+type StatusPublisherSpaceOptions struct {
+	//ServerURL is the Space organization URL, for instance "https://company.jetbrains.space"
+	ServerURL string `prop:"spaceServerUrl"`
+	//ClientID of the Space application used to publish statuses
+	ClientID string `prop:"spaceClientId"`
+	//ClientSecret of the Space application
+	ClientSecret string `prop:"secure:spaceClientSecret"`
+	//ProjectKey is the key of the Space project
+	ProjectKey string `prop:"spaceProjectKey"`
+	//DisplayName is the name of the statuses in Space. Defaults to "TeamCity".
+	DisplayName string `prop:"spaceCommitsPublisherDisplayName"`
+}
+
+// NewFeatureCommitStatusPublisherSpace creates a Build Feature Commit status Publisher to JetBrains Space with the given options and validates the required properties.
+// VcsRootID is optional - if empty, it will apply the commit publisher feature to all VCS roots.
+func NewFeatureCommitStatusPublisherSpace(opt StatusPublisherSpaceOptions, vcsRootID string) (*FeatureCommitStatusPublisher, error) {
+	if opt.ServerURL == "" {
+		return nil, fmt.Errorf("ServerURL is required")
+	}
+	if opt.ClientID == "" || opt.ClientSecret == "" {
+		return nil, fmt.Errorf("ClientID/ClientSecret is required")
+	}
+	if opt.ProjectKey == "" {
+		return nil, fmt.Errorf("ProjectKey is required")
+	}
+	return newFeatureCommitStatusPublisher(opt, vcsRootID), nil
+}
+
+// Properties returns a *Properties collection with properties filled related to this commit publisher parameters to be used in build features
+func (s StatusPublisherSpaceOptions) Properties() *Properties {
+	props := statusPublisherProperties(publisherIDSpace, &s)
+	props.AddOrReplaceValue("spaceCredentialsType", "spaceCredentialsJBA")
+	return props
+}
+
+// CommitStatusPublisherSpaceOptionsFromProperties grabs a Properties collection and transforms back to a StatusPublisherSpaceOptions
+func CommitStatusPublisherSpaceOptionsFromProperties(p *Properties) (*StatusPublisherSpaceOptions, error) {
+	var out StatusPublisherSpaceOptions
+	if err := UnmarshalProperties(p, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
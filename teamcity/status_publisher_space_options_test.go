@@ -0,0 +1,29 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureCommitPublisher_Space(t *testing.T) {
+	t.Run("ProjectKey Required", func(t *testing.T) {
+		opt := teamcity.StatusPublisherSpaceOptions{ServerURL: "https://company.jetbrains.space", ClientID: "id", ClientSecret: "secret"}
+		_, err := teamcity.NewFeatureCommitStatusPublisherSpace(opt, "")
+		require.EqualError(t, err, "ProjectKey is required")
+	})
+
+	t.Run("Correct Properties", func(t *testing.T) {
+		opt := teamcity.StatusPublisherSpaceOptions{ServerURL: "https://company.jetbrains.space", ClientID: "id", ClientSecret: "secret", ProjectKey: "BACKEND"}
+		assert.Equal(t, map[string]string{
+			"publisherId":              "spaceStatusPublisher",
+			"spaceServerUrl":           "https://company.jetbrains.space",
+			"spaceCredentialsType":     "spaceCredentialsJBA",
+			"spaceClientId":            "id",
+			"secure:spaceClientSecret": "secret",
+			"spaceProjectKey":          "BACKEND",
+		}, opt.Properties().Map())
+	})
+}
@@ -2,7 +2,6 @@ package teamcity
 
 import (
 	"encoding/json"
-	"fmt"
 )
 
 // BuildStepType represents most common step types for build steps
@@ -34,12 +33,12 @@ const (
 )
 
 // Step interface represents a a build configuration/template build step. To interact with concrete step types, see the Step* types.
+// Steps of other types can be read by registering them with RegisterStepType.
 type Step interface {
 	GetID() string
 	GetName() string
 	Type() string
-
-	serializable() *stepJSON
+	MarshalJSON() ([]byte, error)
 }
 
 type stepJSON struct {
@@ -79,33 +78,7 @@ var stepsReadingFunc = func(dt []byte, out interface{}) error {
 }
 
 var stepReadingFunc = func(dt []byte, out interface{}) error {
-	var payload stepJSON
-	if err := json.Unmarshal(dt, &payload); err != nil {
-		return err
-	}
-
-	var step Step
-	var err error
-	switch payload.Type {
-	case string(StepTypePowershell):
-		var ps StepPowershell
-		err = ps.UnmarshalJSON(dt)
-		step = &ps
-	case string(StepTypeCommandLine):
-		var cmd StepCommandLine
-		err = cmd.UnmarshalJSON(dt)
-		step = &cmd
-	case string(StepTypeOctopusPushPackage):
-		var opp StepOctopusPushPackage
-		err = opp.UnmarshalJSON(dt)
-		step = &opp
-	case string(StepTypeOctopusCreateRelease):
-		var ocr StepOctopusCreateRelease
-		err = ocr.UnmarshalJSON(dt)
-		step = &ocr
-	default:
-		return fmt.Errorf("Unsupported step type: '%s' (id:'%s')", payload.Type, payload.ID)
-	}
+	step, err := decodeStep(dt)
 	if err != nil {
 		return err
 	}
@@ -113,3 +86,19 @@ var stepReadingFunc = func(dt []byte, out interface{}) error {
 	replaceValue(out, &step)
 	return nil
 }
+
+// serializeStep returns the REST representation of a step, using its MarshalJSON method for steps not defined in this package
+func serializeStep(s Step) (*stepJSON, error) {
+	if builtin, ok := s.(interface{ serializable() *stepJSON }); ok {
+		return builtin.serializable(), nil
+	}
+	dt, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var out stepJSON
+	if err := json.Unmarshal(dt, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
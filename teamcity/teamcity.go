@@ -59,13 +59,19 @@ type Client struct {
 	RetryTimeout time.Duration
 
 	commonBase *sling.Sling
+	webBase    *sling.Sling
 
-	AgentPools *AgentPoolsService
-	BuildTypes *BuildTypeService
-	Groups     *GroupService
-	Projects   *ProjectService
-	Server     *ServerService
-	VcsRoots   *VcsRootService
+	AgentPools         *AgentPoolsService
+	Builds             *BuildService
+	BuildTypes         *BuildTypeService
+	Groups             *GroupService
+	Investigations     *InvestigationService
+	Mutes              *MuteService
+	ProblemOccurrences *ProblemOccurrenceService
+	Projects           *ProjectService
+	Server             *ServerService
+	TestOccurrences    *TestOccurrenceService
+	VcsRoots           *VcsRootService
 }
 
 func NewClient(auth Auth, httpClient *http.Client) (*Client, error) {
@@ -90,30 +96,45 @@ func newClientInstance(auth Auth, address string, httpClient *http.Client) (*Cli
 		Set("Accept", "application/json").
 		Set("Origin", address)
 
+	// webClient is used for the few resources served outside of the REST API, such as build logs
+	webClient := sharedClient.New()
+
 	switch a := auth.(type) {
 	case tokenAuth:
 		sharedClient = sharedClient.
 			Base(address+"/app/rest/").
 			Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+		webClient = webClient.
+			Base(address+"/").
+			Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
 	case basicAuth:
 		sharedClient = sharedClient.
 			Base(address+"/httpAuth/app/rest/").
 			SetBasicAuth(a.username, a.password)
+		webClient = webClient.
+			Base(address+"/httpAuth/").
+			SetBasicAuth(a.username, a.password)
 
 	default:
 		return nil, errors.New("unsupported authentication")
 	}
 
 	return &Client{
-		address:    address,
-		HTTPClient: httpClient,
-		commonBase: sharedClient,
-		AgentPools: newAgentPoolsService(sharedClient.New(), httpClient),
-		BuildTypes: newBuildTypeService(sharedClient.New(), httpClient),
-		Groups:     newGroupService(sharedClient.New(), httpClient),
-		Projects:   newProjectService(sharedClient.New(), httpClient),
-		Server:     newServerService(sharedClient.New()),
-		VcsRoots:   newVcsRootService(sharedClient.New(), httpClient),
+		address:            address,
+		HTTPClient:         httpClient,
+		commonBase:         sharedClient,
+		webBase:            webClient,
+		AgentPools:         newAgentPoolsService(sharedClient.New(), httpClient),
+		Builds:             newBuildService(sharedClient.New(), httpClient),
+		BuildTypes:         newBuildTypeService(sharedClient.New(), httpClient),
+		Groups:             newGroupService(sharedClient.New(), httpClient),
+		Investigations:     newInvestigationService(sharedClient.New(), httpClient),
+		Mutes:              newMuteService(sharedClient.New(), httpClient),
+		ProblemOccurrences: newProblemOccurrenceService(sharedClient.New(), httpClient),
+		Projects:           newProjectService(sharedClient.New(), httpClient),
+		Server:             newServerService(sharedClient.New()),
+		TestOccurrences:    newTestOccurrenceService(sharedClient.New(), httpClient),
+		VcsRoots:           newVcsRootService(sharedClient.New(), httpClient),
 	}, nil
 }
 
@@ -134,11 +155,36 @@ func (c *Client) AgentRequirementService(id string) *AgentRequirementService {
 	return newAgentRequirementService(id, c.HTTPClient, c.commonBase.New())
 }
 
+// ArtifactService returns a service to browse and download artifacts for the build matching the given locator, such as LocatorIDInt(buildID)
+func (c *Client) ArtifactService(build Locator) *ArtifactService {
+	return newArtifactService(build, c.HTTPClient, c.commonBase.New())
+}
+
+// BuildLogService returns a service to download and tail the log of the build with given id
+func (c *Client) BuildLogService(buildID int) *BuildLogService {
+	return newBuildLogService(buildID, c.HTTPClient, c.commonBase.New(), c.webBase.New())
+}
+
 // BuildFeatureService returns a service to manage agent requirements for a build configuration with given id
 func (c *Client) BuildFeatureService(id string) *BuildFeatureService {
 	return newBuildFeatureService(id, c.HTTPClient, c.commonBase.New())
 }
 
+// FailureConditionService returns a service to manage failure conditions for a build configuration with given id
+func (c *Client) FailureConditionService(id string) *FailureConditionService {
+	return newFailureConditionService(id, c.HTTPClient, c.commonBase.New())
+}
+
+// ProjectParameterService returns a service to manage the parameters of a project with given id one at a time
+func (c *Client) ProjectParameterService(id string) *ParameterService {
+	return newParameterService("projects/"+LocatorID(id).String(), fmt.Sprintf("project '%s'", id), c.HTTPClient, c.commonBase.New())
+}
+
+// BuildTypeParameterService returns a service to manage the parameters of a build configuration with given id one at a time
+func (c *Client) BuildTypeParameterService(id string) *ParameterService {
+	return newParameterService("buildTypes/"+LocatorID(id).String(), fmt.Sprintf("build type '%s'", id), c.HTTPClient, c.commonBase.New())
+}
+
 // ProjectFeatureService returns a service to manage project features for a project with given id
 func (c *Client) ProjectFeatureService(id string) *ProjectFeatureService {
 	return newProjectFeatureService(id, c.HTTPClient, c.commonBase.New())
@@ -154,11 +200,21 @@ func (c *Client) BuildTemplateService(id string) *BuildTemplateService {
 	return NewBuildTemplateService(id, c.HTTPClient, c.commonBase.New())
 }
 
+// SecureTokenService returns a service to manage the secure tokens of a project with given id
+func (c *Client) SecureTokenService(projectID string) *SecureTokenService {
+	return newSecureTokenService(projectID, c.HTTPClient, c.commonBase.New())
+}
+
 // TriggerService returns a service to manage build triggers for a build configuration with given id
 func (c *Client) TriggerService(buildTypeID string) *TriggerService {
 	return newTriggerService(buildTypeID, c.HTTPClient, c.commonBase.New())
 }
 
+// VersionedSettingsService returns a service to synchronize the settings of a project with given id with a VCS
+func (c *Client) VersionedSettingsService(projectID string) *VersionedSettingsService {
+	return newVersionedSettingsService(projectID, c.HTTPClient, c.commonBase.New())
+}
+
 // Validate tests if the client is properly configured and can be used
 func (c *Client) Validate() (bool, error) {
 	response, err := c.commonBase.Get("server").ReceiveSuccess(nil)
@@ -0,0 +1,188 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dghubble/sling"
+)
+
+// TestStatus represents the result of a test occurrence
+type TestStatus string
+
+const (
+	// TestStatusSuccess is reported for passed tests
+	TestStatusSuccess TestStatus = "SUCCESS"
+	// TestStatusFailure is reported for failed tests
+	TestStatusFailure TestStatus = "FAILURE"
+	// TestStatusUnknown is reported for ignored tests
+	TestStatusUnknown TestStatus = "UNKNOWN"
+)
+
+// listPageSize is the number of items requested per page when listing all items of a collection
+const listPageSize = 1000
+
+// TestReference identifies a test across builds
+type TestReference struct {
+	ID   string `json:"id,omitempty" xml:"id"`
+	Name string `json:"name,omitempty" xml:"name"`
+	Href string `json:"href,omitempty" xml:"href"`
+}
+
+// TestOccurrence is a single run of a test within a build
+type TestOccurrence struct {
+	ID     string     `json:"id,omitempty" xml:"id"`
+	Name   string     `json:"name,omitempty" xml:"name"`
+	Status TestStatus `json:"status,omitempty" xml:"status"`
+	// Duration in milliseconds
+	Duration int64 `json:"duration,omitempty" xml:"duration"`
+
+	Ignored               bool `json:"ignored,omitempty" xml:"ignored"`
+	Muted                 bool `json:"muted,omitempty" xml:"muted"`
+	CurrentlyMuted        bool `json:"currentlyMuted,omitempty" xml:"currentlyMuted"`
+	CurrentlyInvestigated bool `json:"currentlyInvestigated,omitempty" xml:"currentlyInvestigated"`
+	NewFailure            bool `json:"newFailure,omitempty" xml:"newFailure"`
+
+	// Details contains the failure message and stacktrace for failed tests
+	Details string `json:"details,omitempty" xml:"details"`
+	// IgnoreDetails contains the reason for ignored tests
+	IgnoreDetails string `json:"ignoreDetails,omitempty" xml:"ignoreDetails"`
+
+	Href  string          `json:"href,omitempty" xml:"href"`
+	Build *BuildReference `json:"build,omitempty"`
+	Test  *TestReference  `json:"test,omitempty"`
+}
+
+type testOccurrencesJSON struct {
+	Count int32             `json:"count,omitempty" xml:"count"`
+	Href  string            `json:"href,omitempty" xml:"href"`
+	Items []*TestOccurrence `json:"testOccurrence"`
+}
+
+// TestOccurrenceLocator filters test occurrences. At least one of BuildID, TestName, TestID or CurrentlyFailing must be set.
+// Zero values are omitted from the locator.
+type TestOccurrenceLocator struct {
+	BuildID     int
+	BuildTypeID string
+	// ProjectID returns occurrences affecting the project and its subprojects. Use with CurrentlyFailing or CurrentlyMuted.
+	ProjectID string
+	TestName  string
+	TestID    string
+	Status    TestStatus
+
+	CurrentlyFailing      *bool
+	CurrentlyInvestigated *bool
+	CurrentlyMuted        *bool
+	Muted                 *bool
+	Ignored               *bool
+
+	// Count limits the number of occurrences returned. If zero, all occurrences are returned.
+	Count int
+	Start int
+}
+
+func (l TestOccurrenceLocator) dimensions() locatorDimensions {
+	var d locatorDimensions
+	if l.BuildID != 0 {
+		d.add("build", fmt.Sprintf("(id:%d)", l.BuildID))
+	}
+	if l.BuildTypeID != "" {
+		d.add("buildType", fmt.Sprintf("(id:%s)", l.BuildTypeID))
+	}
+	if l.ProjectID != "" {
+		d.add("affectedProject", fmt.Sprintf("(id:%s)", l.ProjectID))
+	}
+	if l.TestID != "" || l.TestName != "" {
+		var test locatorDimensions
+		test.add("id", l.TestID)
+		if l.TestName != "" {
+			test.add("name", locatorValue(l.TestName))
+		}
+		d.add("test", "("+test.String()+")")
+	}
+	d.add("status", string(l.Status))
+	d.addBool("currentlyFailing", l.CurrentlyFailing)
+	d.addBool("currentlyInvestigated", l.CurrentlyInvestigated)
+	d.addBool("currentlyMuted", l.CurrentlyMuted)
+	d.addBool("muted", l.Muted)
+	d.addBool("ignored", l.Ignored)
+	d.addInt("count", l.Count)
+	d.addInt("start", l.Start)
+	return d
+}
+
+// String returns the unescaped locator, for instance "build:(id:1),status:FAILURE"
+func (l TestOccurrenceLocator) String() string {
+	return l.dimensions().String()
+}
+
+// TestOccurrenceService has operations for querying test results
+type TestOccurrenceService struct {
+	sling      *sling.Sling
+	httpClient *http.Client
+	restHelper *restHelper
+}
+
+func newTestOccurrenceService(base *sling.Sling, httpClient *http.Client) *TestOccurrenceService {
+	sling := base.Path("testOccurrences/")
+	return &TestOccurrenceService{
+		sling:      sling,
+		httpClient: httpClient,
+		restHelper: newRestHelper(httpClient, sling),
+	}
+}
+
+func (s *TestOccurrenceService) fields() getFields {
+	return getFields{
+		Fields: fmt.Sprintf("count,href,testOccurrence(id,name,status,duration,ignored,muted,currentlyMuted,currentlyInvestigated,newFailure,details,ignoreDetails,href,build(%s),test(id,name,href))", buildReferenceFields),
+	}
+}
+
+// List returns the test occurrences matching the locator, requesting further pages until all are returned unless locator.Count is set.
+func (s *TestOccurrenceService) List(locator TestOccurrenceLocator) ([]*TestOccurrence, error) {
+	if locator.Count > 0 {
+		return s.page(locator)
+	}
+
+	var out []*TestOccurrence
+	locator.Count = listPageSize
+	for {
+		page, err := s.page(locator)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < listPageSize {
+			return out, nil
+		}
+		locator.Start += listPageSize
+	}
+}
+
+func (s *TestOccurrenceService) page(locator TestOccurrenceLocator) ([]*TestOccurrence, error) {
+	var out testOccurrencesJSON
+	err := s.restHelper.getWithFields(locator.dimensions().query(), s.fields(), &out, "test occurrences")
+	if err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// ListByBuild returns all test occurrences for the build with given id
+func (s *TestOccurrenceService) ListByBuild(buildID int) ([]*TestOccurrence, error) {
+	return s.List(TestOccurrenceLocator{BuildID: buildID})
+}
+
+// ListFailedByBuild returns the failed test occurrences for the build with given id
+func (s *TestOccurrenceService) ListFailedByBuild(buildID int) ([]*TestOccurrence, error) {
+	return s.List(TestOccurrenceLocator{BuildID: buildID, Status: TestStatusFailure})
+}
+
+// History returns the occurrences of the test with given name across builds.
+// buildTypeID is optional and restricts the history to a single build configuration.
+func (s *TestOccurrenceService) History(testName string, buildTypeID string) ([]*TestOccurrence, error) {
+	if testName == "" {
+		return nil, fmt.Errorf("testName is required")
+	}
+	return s.List(TestOccurrenceLocator{TestName: testName, BuildTypeID: buildTypeID})
+}
@@ -0,0 +1,100 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TestOccurrenceLocator(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("build:(id:42),status:FAILURE", TestOccurrenceLocator{BuildID: 42, Status: TestStatusFailure}.String())
+	assert.Equal("affectedProject:(id:Root),currentlyFailing:true,muted:false",
+		TestOccurrenceLocator{ProjectID: "Root", CurrentlyFailing: NewTrue(), Muted: NewFalse()}.String())
+	assert.Equal("buildType:(id:Bt1),test:(name:(pkg: TestFoo(a,b)))",
+		TestOccurrenceLocator{BuildTypeID: "Bt1", TestName: "pkg: TestFoo(a,b)"}.String())
+	assert.Equal("test:(id:-123,name:pkg.TestFoo)", TestOccurrenceLocator{TestID: "-123", TestName: "pkg.TestFoo"}.String())
+	assert.Equal("test:(name:$base64:cGtnOiBUZXN0Rm9vKCk6KQ==)", TestOccurrenceLocator{TestName: "pkg: TestFoo():)"}.String())
+}
+
+func Test_TestOccurrenceDeserialize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var sut testOccurrencesJSON
+	require.NoError(json.Unmarshal([]byte(testOccurrencesJSONFixture), &sut))
+	require.Len(sut.Items, 1)
+
+	actual := sut.Items[0]
+	assert.Equal("pkg.TestFoo", actual.Name)
+	assert.Equal(TestStatusFailure, actual.Status)
+	assert.Equal(int64(1520), actual.Duration)
+	assert.True(actual.NewFailure)
+	assert.False(actual.Muted)
+	assert.Equal("expected 1, got 2", actual.Details)
+	assert.Equal(42, actual.Build.ID)
+	assert.Equal("Bt1", actual.Build.BuildTypeID)
+	assert.Equal("-7101", actual.Test.ID)
+}
+
+func Test_TestOccurrenceListPaginates(t *testing.T) {
+	require := require.New(t)
+
+	var locators []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locator := r.URL.Query().Get("locator")
+		locators = append(locators, locator)
+
+		count := listPageSize
+		if strings.HasSuffix(locator, fmt.Sprintf("start:%d", listPageSize)) {
+			count = 1
+		}
+		items := make([]string, count)
+		for i := range items {
+			items[i] = `{"name":"test"}`
+		}
+		fmt.Fprintf(w, `{"count":%d,"testOccurrence":[%s]}`, count, strings.Join(items, ","))
+	}))
+
+	actual, err := client.TestOccurrences.ListByBuild(42)
+	require.NoError(err)
+	require.Len(actual, listPageSize+1)
+	require.Equal([]string{
+		fmt.Sprintf("build:(id:42),count:%d", listPageSize),
+		fmt.Sprintf("build:(id:42),count:%d,start:%d", listPageSize, listPageSize),
+	}, locators)
+}
+
+const testOccurrencesJSONFixture = `
+{
+	"count": 1,
+	"testOccurrence": [
+		{
+			"id": "build:(id:42),id:2000",
+			"name": "pkg.TestFoo",
+			"status": "FAILURE",
+			"duration": 1520,
+			"newFailure": true,
+			"muted": false,
+			"details": "expected 1, got 2",
+			"build": {
+				"id": 42,
+				"buildTypeId": "Bt1",
+				"number": "17",
+				"status": "FAILURE",
+				"state": "finished"
+			},
+			"test": {
+				"id": "-7101",
+				"name": "pkg.TestFoo"
+			}
+		}
+	]
+}
+`
@@ -1,10 +1,5 @@
 package teamcity
 
-import (
-	"encoding/json"
-	"fmt"
-)
-
 type triggerType = string
 
 const (
@@ -37,6 +32,7 @@ type triggerJSON struct {
 }
 
 // Trigger represents a build trigger to be associated with a build configuration. Use the constructor methods to create new instances.
+// Triggers of other types can be read by registering them with RegisterTriggerType.
 type Trigger interface {
 	ID() string
 	Type() string
@@ -46,35 +42,11 @@ type Trigger interface {
 }
 
 var triggerReadingFunc = func(dt []byte, out interface{}) error {
-	var payload triggerJSON
-	if err := json.Unmarshal(dt, &payload); err != nil {
+	obj, err := decodeTrigger(dt)
+	if err != nil {
 		return err
 	}
 
-	var obj Trigger
-	switch payload.Type {
-	case string(TriggerTypes.Vcs):
-		var vcs TriggerVcs
-		if err := vcs.UnmarshalJSON(dt); err != nil {
-			return err
-		}
-		obj = &vcs
-	case string(TriggerTypes.BuildFinish):
-		var finish TriggerBuildFinish
-		if err := finish.UnmarshalJSON(dt); err != nil {
-			return err
-		}
-		obj = &finish
-	case string(TriggerTypes.Schedule):
-		var sch TriggerSchedule
-		if err := sch.UnmarshalJSON(dt); err != nil {
-			return err
-		}
-		obj = &sch
-	default:
-		return fmt.Errorf("Unsupported trigger type: '%s' (id:'%s')", payload.Type, payload.ID)
-	}
-
 	replaceValue(out, &obj)
 	return nil
 }
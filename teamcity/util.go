@@ -20,3 +20,12 @@ func NewBool(b bool) *bool {
 func NewInt32(i int32) *int32 {
 	return &i
 }
+
+func containsString(values []string, v string) bool {
+	for _, item := range values {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
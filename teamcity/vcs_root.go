@@ -1,7 +1,6 @@
 package teamcity
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,7 +8,7 @@ import (
 	"github.com/dghubble/sling"
 )
 
-// VcsRoot interface represents a base type of VCSRoot
+// VcsRoot interface represents a base type of VCSRoot. VCS roots of other types can be read by registering them with RegisterVcsRootType.
 type VcsRoot interface {
 	//GetID returns the ID of this VCS Root
 	GetID() string
@@ -213,22 +212,5 @@ func (s *VcsRootService) readVcsRootResponse(resp *http.Response) (VcsRoot, erro
 		return nil, err
 	}
 
-	var payload vcsRootJSON
-	if err := json.Unmarshal(bodyBytes, &payload); err != nil {
-		return nil, err
-	}
-
-	var out VcsRoot
-	switch payload.VcsName {
-	case VcsNames.Git:
-		var git GitVcsRoot
-		if err := git.UnmarshalJSON(bodyBytes); err != nil {
-			return nil, err
-		}
-		out = &git
-	default:
-		return nil, fmt.Errorf("Unsupported VCS Root type: '%s' (id:'%s') for projectID: %s", payload.VcsName, payload.ID, payload.Project.ID)
-	}
-
-	return out, nil
+	return decodeVcsRoot(bodyBytes)
 }
@@ -0,0 +1,195 @@
+package teamcity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// VersionedSettingsSynchronization is whether the settings of a project are synchronized with a VCS
+type VersionedSettingsSynchronization = string
+
+const (
+	// VersionedSettingsSynchronizationEnabled synchronizes the project settings with the VCS root of the config
+	VersionedSettingsSynchronizationEnabled VersionedSettingsSynchronization = "enabled"
+	// VersionedSettingsSynchronizationDisabled does not synchronize the project settings
+	VersionedSettingsSynchronizationDisabled VersionedSettingsSynchronization = "disabled"
+	// VersionedSettingsSynchronizationParent uses the synchronization settings of the parent project
+	VersionedSettingsSynchronizationParent VersionedSettingsSynchronization = "useParentProjectSettings"
+)
+
+// VersionedSettingsBuildSettingsMode is which settings builds use when the settings in VCS and on the server differ
+type VersionedSettingsBuildSettingsMode = string
+
+const (
+	// VersionedSettingsBuildSettingsModeUseCurrent uses the current settings of the server by default, allowing personal builds with the settings in VCS
+	VersionedSettingsBuildSettingsModeUseCurrent VersionedSettingsBuildSettingsMode = "useCurrentByDefault"
+	// VersionedSettingsBuildSettingsModeFromVcs uses the settings in VCS of the revision built
+	VersionedSettingsBuildSettingsModeFromVcs VersionedSettingsBuildSettingsMode = "useFromVCS"
+	// VersionedSettingsBuildSettingsModeAlwaysUseCurrent always uses the current settings of the server
+	VersionedSettingsBuildSettingsModeAlwaysUseCurrent VersionedSettingsBuildSettingsMode = "alwaysUseCurrent"
+)
+
+// VersionedSettingsConfig represents how the settings of a project are stored in a VCS
+type VersionedSettingsConfig struct {
+	SynchronizationMode VersionedSettingsSynchronization `json:"synchronizationMode,omitempty"`
+	// VcsRootID is the id of the VCS root the settings are stored in
+	VcsRootID string                  `json:"vcsRootId,omitempty"`
+	Format    VersionedSettingsFormat `json:"format,omitempty"`
+	// SettingsPath is the directory of the settings in the VCS root, ".teamcity" by default
+	SettingsPath      string                             `json:"settingsPath,omitempty"`
+	BuildSettingsMode VersionedSettingsBuildSettingsMode `json:"buildSettingsMode,omitempty"`
+	// ShowSettingsChanges shows the changes of the settings in builds affected by them
+	ShowSettingsChanges *bool `json:"showSettingsChanges,omitempty"`
+	// AllowUIEditing allows editing the settings in the UI, committing changes to the VCS
+	AllowUIEditing *bool `json:"allowUIEditing,omitempty"`
+	// StoreSecureValuesOutsideVcs stores passwords as secure tokens of the project instead of scrambled in the VCS
+	StoreSecureValuesOutsideVcs *bool `json:"storeSecureValuesOutsideVcs,omitempty"`
+	// PortableDsl generates Kotlin DSL without server specific ids
+	PortableDsl *bool `json:"portableDsl,omitempty"`
+	// ImportDecision is what to do when enabling synchronization with a VCS root which already has settings,
+	// such as "importFromVCS" or "overrideInVCS"
+	ImportDecision string `json:"importDecision,omitempty"`
+}
+
+// VersionedSettingsStatus represents the result of the last synchronization of the settings of a project with a VCS
+type VersionedSettingsStatus struct {
+	// Type is "info", or "warning" if the last synchronization failed
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Timestamp is when the status changed, see Time
+	Timestamp string `json:"timestamp,omitempty"`
+	// DslOutdated is true if the Kotlin DSL in VCS was changed but not applied yet
+	DslOutdated bool `json:"dslOutdated,omitempty"`
+	// MissingContextParameters are the context parameters used by the Kotlin DSL, which have no value in the project
+	MissingContextParameters *Properties `json:"missingContextParameters,omitempty"`
+}
+
+// Failed returns true if the last synchronization failed, the error being described by Message
+func (s *VersionedSettingsStatus) Failed() bool {
+	return s.Type == "warning"
+}
+
+// Time parses Timestamp into a time.Time
+func (s *VersionedSettingsStatus) Time() (time.Time, error) {
+	return time.Parse(TimeLayout, s.Timestamp)
+}
+
+// VersionedSettingsService provides operations for synchronizing the settings of a project with a VCS
+type VersionedSettingsService struct {
+	ProjectID  string
+	httpClient *http.Client
+	base       *sling.Sling
+	restHelper *restHelper
+}
+
+func newVersionedSettingsService(projectID string, c *http.Client, base *sling.Sling) *VersionedSettingsService {
+	sling := base.New().Path(fmt.Sprintf("projects/%s/versionedSettings/", LocatorID(projectID)))
+	return &VersionedSettingsService{
+		ProjectID:  projectID,
+		httpClient: c,
+		base:       sling,
+		restHelper: newRestHelper(c, sling),
+	}
+}
+
+// GetConfig returns how the settings of the project are stored in a VCS
+func (s *VersionedSettingsService) GetConfig() (*VersionedSettingsConfig, error) {
+	var out VersionedSettingsConfig
+	if err := s.restHelper.get("config", &out, s.description()); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateConfig changes how the settings of the project are stored in a VCS, and returns the config as stored
+func (s *VersionedSettingsService) UpdateConfig(config *VersionedSettingsConfig) (*VersionedSettingsConfig, error) {
+	if config == nil {
+		return nil, errors.New("config can't be nil")
+	}
+	if config.SynchronizationMode == VersionedSettingsSynchronizationEnabled && config.VcsRootID == "" {
+		return nil, fmt.Errorf("VcsRootID is required for SynchronizationMode '%s'", config.SynchronizationMode)
+	}
+	var out VersionedSettingsConfig
+	if err := s.restHelper.put("config", config, &out, s.description()); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetSettingsPath changes the directory of the settings in the VCS root, keeping the rest of the config
+func (s *VersionedSettingsService) SetSettingsPath(path string) (*VersionedSettingsConfig, error) {
+	config, err := s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.SettingsPath = path
+	return s.UpdateConfig(config)
+}
+
+// GetStatus returns the result of the last synchronization of the settings of the project
+func (s *VersionedSettingsService) GetStatus() (*VersionedSettingsStatus, error) {
+	var out VersionedSettingsStatus
+	if err := s.restHelper.get("status", &out, s.description()); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LoadFromVcs replaces the settings of the project by the ones in the VCS. Loading is asynchronous, see WaitForSync.
+func (s *VersionedSettingsService) LoadFromVcs() error {
+	_, err := s.restHelper.postTextPlain("loadSettings", "", s.description())
+	return err
+}
+
+// CommitCurrentSettings commits the current settings of the project to the VCS
+func (s *VersionedSettingsService) CommitCurrentSettings() error {
+	_, err := s.restHelper.postTextPlain("commitCurrentSettings", "", s.description())
+	return err
+}
+
+// CheckForChanges checks the VCS for changes of the settings, which are then applied asynchronously, see WaitForSync
+func (s *VersionedSettingsService) CheckForChanges() error {
+	_, err := s.restHelper.postTextPlain("checkForChanges", "", s.description())
+	return err
+}
+
+// WaitForSync polls the status of the project every interval until it differs from previous, the status read with GetStatus before LoadFromVcs or CheckForChanges was called,
+// and the Kotlin DSL is up to date. Comparing statuses of the server avoids depending on the clocks of the client and the server being in sync.
+// It returns the status, with an error if the synchronization failed, if its timestamp is invalid, or if ctx is cancelled.
+func (s *VersionedSettingsService) WaitForSync(ctx context.Context, previous *VersionedSettingsStatus, interval time.Duration) (*VersionedSettingsStatus, error) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	for {
+		status, err := s.GetStatus()
+		if err != nil {
+			return nil, err
+		}
+		if previous == nil || status.Timestamp != previous.Timestamp || status.Message != previous.Message || status.Type != previous.Type {
+			if _, err := status.Time(); err != nil {
+				return status, fmt.Errorf("invalid timestamp of the versioned settings status of project '%s': %s", s.ProjectID, err)
+			}
+			if !status.DslOutdated {
+				if status.Failed() {
+					return status, fmt.Errorf("synchronization of the settings of project '%s' failed: %s", s.ProjectID, status.Message)
+				}
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *VersionedSettingsService) description() string {
+	return fmt.Sprintf("versioned settings of project '%s'", s.ProjectID)
+}
@@ -0,0 +1,87 @@
+package teamcity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VersionedSettingsService(t *testing.T) {
+	require := require.New(t)
+	var loaded bool
+	var config VersionedSettingsConfig
+	statuses := []string{
+		`{"type":"info","message":"Settings were loaded from VCS","timestamp":"20240102T090000+0000"}`,
+		`{"type":"info","message":"Running DSL","timestamp":"20240102T100000+0000","dslOutdated":true}`,
+		`{"type":"info","message":"Settings were loaded from VCS","timestamp":"20240102T100005+0000"}`,
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /httpAuth/app/rest/projects/id%3AProj/versionedSettings/config":
+			w.Write([]byte(`{"synchronizationMode":"enabled","vcsRootId":"Proj_Settings","format":"kotlin","buildSettingsMode":"useFromVCS","allowUIEditing":true}`))
+		case "PUT /httpAuth/app/rest/projects/id%3AProj/versionedSettings/config":
+			json.NewDecoder(r.Body).Decode(&config)
+			json.NewEncoder(w).Encode(&config)
+		case "POST /httpAuth/app/rest/projects/id%3AProj/versionedSettings/loadSettings":
+			loaded = true
+		case "GET /httpAuth/app/rest/projects/id%3AProj/versionedSettings/status":
+			w.Write([]byte(statuses[0]))
+			if loaded && len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	sut := client.VersionedSettingsService("Proj")
+
+	actual, err := sut.SetSettingsPath(".teamcity/ci")
+	require.NoError(err)
+	assert.Equal(t, &VersionedSettingsConfig{
+		SynchronizationMode: VersionedSettingsSynchronizationEnabled,
+		VcsRootID:           "Proj_Settings",
+		Format:              VersionedSettingsFormatKotlin,
+		SettingsPath:        ".teamcity/ci",
+		BuildSettingsMode:   VersionedSettingsBuildSettingsModeFromVcs,
+		AllowUIEditing:      NewTrue(),
+	}, actual)
+
+	_, err = sut.UpdateConfig(&VersionedSettingsConfig{SynchronizationMode: VersionedSettingsSynchronizationEnabled})
+	assert.EqualError(t, err, "VcsRootID is required for SynchronizationMode 'enabled'")
+
+	before, err := sut.GetStatus()
+	require.NoError(err)
+	require.NoError(sut.LoadFromVcs())
+	status, err := sut.WaitForSync(context.Background(), before, time.Millisecond)
+	require.NoError(err)
+	assert.Equal(t, "20240102T100005+0000", status.Timestamp)
+	assert.False(t, status.Failed())
+}
+
+func Test_VersionedSettingsServiceWaitForSyncFailure(t *testing.T) {
+	statusPath := "/httpAuth/app/rest/projects/id%3AProj/versionedSettings/status"
+	client, fake := newRecordingServer(t, map[string]string{
+		statusPath: `{"type":"warning","message":"Compilation error: Settings.kts:12","timestamp":"20240102T100005+0000"}`,
+	})
+	sut := client.VersionedSettingsService("Proj")
+
+	previous := &VersionedSettingsStatus{Type: "info", Message: "Settings were loaded from VCS", Timestamp: "20240102T090000+0000"}
+	status, err := sut.WaitForSync(context.Background(), previous, time.Millisecond)
+	assert.EqualError(t, err, "synchronization of the settings of project 'Proj' failed: Compilation error: Settings.kts:12")
+	assert.True(t, status.Failed())
+
+	// The status does not change until the synchronization completes
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sut.WaitForSync(ctx, status, time.Millisecond)
+	assert.Equal(t, context.Canceled, err)
+
+	fake.responses[statusPath] = `{"type":"info","message":"Settings were loaded from VCS","timestamp":"yesterday"}`
+	_, err = sut.WaitForSync(context.Background(), previous, time.Millisecond)
+	assert.ErrorContains(t, err, "invalid timestamp of the versioned settings status of project 'Proj'")
+}
@@ -0,0 +1,278 @@
+package teamcity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BuildEventType is the kind of lifecycle change reported by a Watcher
+type BuildEventType string
+
+const (
+	// BuildEventQueued is sent when a build is added to the queue
+	BuildEventQueued BuildEventType = "queued"
+	// BuildEventStarted is sent when a build starts running
+	BuildEventStarted BuildEventType = "started"
+	// BuildEventFinished is sent when a build finishes, successfully or not
+	BuildEventFinished BuildEventType = "finished"
+	// BuildEventCanceled is sent instead of BuildEventFinished when a build was canceled, either while queued or running
+	BuildEventCanceled BuildEventType = "canceled"
+	// BuildEventRemoved is sent when a queued or running build no longer exists on the server, for instance because it was deleted.
+	// Only the ID and the last known State of its Build are set.
+	BuildEventRemoved BuildEventType = "removed"
+)
+
+// BuildEvent is a lifecycle change of a build, as delivered by Watcher.Watch
+type BuildEvent struct {
+	Type  BuildEventType
+	Build *Build
+
+	// Err is set if polling failed. The watcher keeps polling after sending it.
+	Err error
+}
+
+// WatcherOptions controls which builds a Watcher tracks and how it polls
+type WatcherOptions struct {
+	// ProjectIDs are the projects to watch, including their subprojects
+	ProjectIDs []string
+	// BuildTypeIDs are the build configurations to watch
+	BuildTypeIDs []string
+	// PollInterval is the delay between polls. Defaults to 30 seconds.
+	PollInterval time.Duration
+	// Store persists the checkpoint between polls and runs. Defaults to a MemoryCheckpointStore.
+	Store CheckpointStore
+}
+
+// Watcher polls TeamCity for builds of a set of projects and build configurations and reports their lifecycle changes.
+// Finished builds are queried by finish date, so only builds that finished since the last one reported are fetched on each poll, whatever their id.
+// State changes that happen entirely between two polls, for instance a build that is queued and starts before it was observed, are
+// reported as a single event for the latest state.
+type Watcher struct {
+	builds       *BuildService
+	scopes       []BuildLocator
+	pollInterval time.Duration
+	store        CheckpointStore
+}
+
+// NewWatcher returns a Watcher for the builds selected by opt. At least one project or build configuration is required.
+func NewWatcher(client *Client, opt WatcherOptions) (*Watcher, error) {
+	if client == nil {
+		return nil, errors.New("client is required")
+	}
+	if len(opt.ProjectIDs) == 0 && len(opt.BuildTypeIDs) == 0 {
+		return nil, errors.New("at least one project or build type to watch is required")
+	}
+
+	scopes := make([]BuildLocator, 0, len(opt.ProjectIDs)+len(opt.BuildTypeIDs))
+	for _, id := range opt.ProjectIDs {
+		scopes = append(scopes, BuildLocator{ProjectID: id, DefaultFilter: NewFalse()})
+	}
+	for _, id := range opt.BuildTypeIDs {
+		scopes = append(scopes, BuildLocator{BuildTypeID: id, DefaultFilter: NewFalse()})
+	}
+
+	interval := opt.PollInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	store := opt.Store
+	if store == nil {
+		store = &MemoryCheckpointStore{}
+	}
+
+	return &Watcher{
+		builds:       client.Builds,
+		scopes:       scopes,
+		pollInterval: interval,
+		store:        store,
+	}, nil
+}
+
+// Watch polls until ctx is cancelled, sending build events to the returned channel in build id order.
+// The checkpoint is saved after all events of a poll were received, so events are delivered at least once:
+// if the watcher is stopped in the middle of a poll, the events of that poll are sent again when it is resumed.
+// On the first run, builds that already finished are not reported; queued and running builds are.
+// The channel is closed when watching stops.
+func (w *Watcher) Watch(ctx context.Context) <-chan BuildEvent {
+	out := make(chan BuildEvent)
+
+	go func() {
+		defer close(out)
+
+		checkpoint, err := w.store.Load()
+		if err != nil {
+			w.send(ctx, out, BuildEvent{Err: err})
+			return
+		}
+		if checkpoint == nil {
+			checkpoint = newWatcherCheckpoint()
+		}
+
+		for {
+			next, events, err := w.poll(checkpoint)
+			if err != nil {
+				if !w.send(ctx, out, BuildEvent{Err: err}) {
+					return
+				}
+			} else {
+				for _, e := range events {
+					if !w.send(ctx, out, e) {
+						return
+					}
+				}
+				if err := w.store.Save(next); err != nil {
+					if !w.send(ctx, out, BuildEvent{Err: err}) {
+						return
+					}
+				} else {
+					checkpoint = next
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.pollInterval):
+			}
+		}
+	}()
+
+	return out
+}
+
+func (w *Watcher) send(ctx context.Context, out chan<- BuildEvent, e BuildEvent) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case out <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// poll compares the current builds against the checkpoint and returns the events since then, along with the new checkpoint.
+// The given checkpoint is not modified.
+func (w *Watcher) poll(checkpoint *WatcherCheckpoint) (*WatcherCheckpoint, []BuildEvent, error) {
+	next := newWatcherCheckpoint()
+	next.Initialized = true
+	next.LastBuildID = checkpoint.LastBuildID
+	next.LastFinishDate = checkpoint.LastFinishDate
+	next.LastFinishedIDs = append([]int{}, checkpoint.LastFinishedIDs...)
+
+	reported := make(map[int]bool)
+	for _, id := range checkpoint.LastFinishedIDs {
+		reported[id] = true
+	}
+	var lastFinish time.Time
+	if checkpoint.LastFinishDate != "" {
+		var err error
+		if lastFinish, err = time.Parse(TimeLayout, checkpoint.LastFinishDate); err != nil {
+			return nil, nil, fmt.Errorf("invalid finish date '%s' in checkpoint", checkpoint.LastFinishDate)
+		}
+	}
+
+	finished := make(map[int]*Build)
+	active := make(map[int]*Build)
+	for _, scope := range w.scopes {
+		for _, state := range []string{"queued", "running"} {
+			l := scope
+			l.State = state
+			builds, err := w.builds.List(l)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, b := range builds {
+				active[b.ID] = b
+			}
+		}
+
+		l := scope
+		l.State = "finished"
+		switch {
+		case !checkpoint.Initialized:
+			// Only establish where to start from, without reporting past builds
+			l.Count = 1
+		case !lastFinish.IsZero():
+			// Finish dates are precise to the second, so the builds reported during the last second are returned again
+			l.FinishedAfter = lastFinish.Add(-time.Second)
+		default:
+			// Checkpoints saved before finish dates were recorded
+			l.SinceBuildID = checkpoint.LastBuildID
+		}
+		builds, err := w.builds.List(l)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, b := range builds {
+			if reported[b.ID] {
+				continue
+			}
+			if err := next.recordFinished(b); err != nil {
+				return nil, nil, err
+			}
+			if checkpoint.Initialized {
+				finished[b.ID] = b
+			}
+		}
+	}
+
+	// Builds that were active but are no longer listed finished out of the scope of the watcher, or no longer exist. Fetch them individually to find out.
+	var removed []*Build
+	for id := range checkpoint.Active {
+		if _, ok := active[id]; ok {
+			continue
+		}
+		if _, ok := finished[id]; ok {
+			continue
+		}
+		b, err := w.builds.GetByID(id)
+		if err != nil {
+			if isNotFoundError(err) {
+				removed = append(removed, &Build{ID: id, State: checkpoint.Active[id]})
+				continue
+			}
+			return nil, nil, err
+		}
+		if b.State == "finished" {
+			if err := next.recordFinished(b); err != nil {
+				return nil, nil, err
+			}
+			finished[id] = b
+		} else {
+			active[id] = b
+		}
+	}
+
+	var events []BuildEvent
+	for _, b := range removed {
+		events = append(events, BuildEvent{Type: BuildEventRemoved, Build: b})
+	}
+	for id, b := range finished {
+		delete(active, id)
+		if b.Canceled() {
+			events = append(events, BuildEvent{Type: BuildEventCanceled, Build: b})
+		} else {
+			events = append(events, BuildEvent{Type: BuildEventFinished, Build: b})
+		}
+	}
+	for id, b := range active {
+		next.Active[id] = b.State
+		previous, known := checkpoint.Active[id]
+		switch {
+		case !known && b.State == "queued":
+			events = append(events, BuildEvent{Type: BuildEventQueued, Build: b})
+		case b.State == "running" && previous != "running":
+			events = append(events, BuildEvent{Type: BuildEventStarted, Build: b})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Build.ID < events[j].Build.ID
+	})
+	return next, events, nil
+}
@@ -0,0 +1,149 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// WatcherCheckpoint is the state a Watcher needs to resume without rescanning or repeating events.
+// It is serializable to JSON so it can be persisted by a CheckpointStore.
+type WatcherCheckpoint struct {
+	// Initialized is false until the first poll has recorded the latest finished build
+	Initialized bool `json:"initialized"`
+	// LastBuildID is the highest id of the finished builds already reported
+	LastBuildID int `json:"lastBuildId"`
+	// LastFinishDate is the latest finish date of the builds already reported, as returned by the server
+	LastFinishDate string `json:"lastFinishDate,omitempty"`
+	// LastFinishedIDs are the ids of the builds already reported that finished at LastFinishDate.
+	// Finish dates are precise to the second, so builds finishing during that second are fetched again and skipped.
+	LastFinishedIDs []int `json:"lastFinishedIds,omitempty"`
+	// Active holds the state ("queued" or "running") of the builds already reported, by build id
+	Active map[int]string `json:"active"`
+}
+
+func newWatcherCheckpoint() *WatcherCheckpoint {
+	return &WatcherCheckpoint{
+		Active: make(map[int]string),
+	}
+}
+
+// recordFinished updates the last finished build with a build that was reported as finished
+func (c *WatcherCheckpoint) recordFinished(b *Build) error {
+	if b.ID > c.LastBuildID {
+		c.LastBuildID = b.ID
+	}
+	if b.FinishDate == "" {
+		return nil
+	}
+	finish, err := time.Parse(TimeLayout, b.FinishDate)
+	if err != nil {
+		return fmt.Errorf("invalid finish date '%s' of build %d", b.FinishDate, b.ID)
+	}
+	if c.LastFinishDate != "" {
+		last, err := time.Parse(TimeLayout, c.LastFinishDate)
+		if err != nil {
+			return fmt.Errorf("invalid finish date '%s' in checkpoint", c.LastFinishDate)
+		}
+		if finish.Before(last) {
+			return nil
+		}
+		if finish.Equal(last) {
+			for _, id := range c.LastFinishedIDs {
+				if id == b.ID {
+					return nil
+				}
+			}
+			c.LastFinishedIDs = append(c.LastFinishedIDs, b.ID)
+			return nil
+		}
+	}
+	c.LastFinishDate = b.FinishDate
+	c.LastFinishedIDs = []int{b.ID}
+	return nil
+}
+
+// CheckpointStore persists the checkpoint of a Watcher between runs
+type CheckpointStore interface {
+	// Load returns the last saved checkpoint, or nil if none was saved yet
+	Load() (*WatcherCheckpoint, error)
+	// Save persists the checkpoint, replacing the previous one
+	Save(checkpoint *WatcherCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory. It is the default store for a Watcher and does not survive restarts.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *WatcherCheckpoint
+}
+
+// Load returns the last saved checkpoint, or nil if none was saved yet
+func (s *MemoryCheckpointStore) Load() (*WatcherCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoint, nil
+}
+
+// Save replaces the checkpoint held in memory
+func (s *MemoryCheckpointStore) Save(checkpoint *WatcherCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// FileCheckpointStore persists the checkpoint as a JSON file
+type FileCheckpointStore struct {
+	Path string
+}
+
+// NewFileCheckpointStore returns a CheckpointStore writing to the file at path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{Path: path}
+}
+
+// Load reads the checkpoint from the file, returning nil if the file does not exist
+func (s *FileCheckpointStore) Load() (*WatcherCheckpoint, error) {
+	dt, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := newWatcherCheckpoint()
+	if err := json.Unmarshal(dt, out); err != nil {
+		return nil, err
+	}
+	if out.Active == nil {
+		out.Active = make(map[int]string)
+	}
+	return out, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it over the previous one, so a crash never leaves a partial file
+func (s *FileCheckpointStore) Save(checkpoint *WatcherCheckpoint) error {
+	dt, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(dt); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
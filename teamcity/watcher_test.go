@@ -0,0 +1,201 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBuildServer serves the builds endpoints from an in-memory list, honouring the state, sinceBuild, finishDate and count dimensions
+type fakeBuildServer struct {
+	mu     sync.Mutex
+	builds map[int]*Build
+}
+
+func (f *fakeBuildServer) set(builds ...*Build) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, b := range builds {
+		f.builds[b.ID] = b
+	}
+}
+
+func (f *fakeBuildServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var id int
+	if _, err := fmt.Sscanf(r.URL.Path, "/httpAuth/app/rest/builds/id:%d", &id); err == nil {
+		b, ok := f.builds[id]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		json.NewEncoder(w).Encode(b)
+		return
+	}
+
+	locator := r.URL.Query().Get("locator")
+	var finishedAfter time.Time
+	if m := finishDateDimension.FindStringSubmatch(locator); m != nil {
+		finishedAfter, _ = time.Parse(TimeLayout, m[1])
+		locator = strings.Replace(locator, m[0], "", 1)
+	}
+	var state string
+	var since, count int
+	for _, dim := range strings.Split(locator, ",") {
+		fmt.Sscanf(dim, "state:%s", &state)
+		fmt.Sscanf(dim, "sinceBuild:(id:%d)", &since)
+		fmt.Sscanf(dim, "count:%d", &count)
+	}
+	out := buildsJSON{}
+	for i := 1000; i > 0 && (count == 0 || len(out.Items) < count); i-- {
+		b, ok := f.builds[i]
+		if !ok || b.State != state || b.ID <= since {
+			continue
+		}
+		if !finishedAfter.IsZero() {
+			if finish, _ := time.Parse(TimeLayout, b.FinishDate); !finish.After(finishedAfter) {
+				continue
+			}
+		}
+		out.Items = append(out.Items, b)
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+var finishDateDimension = regexp.MustCompile(`finishDate:\(date:([^,]+),condition:after\)`)
+
+func newWatcherTestSetup(t *testing.T, store CheckpointStore) (*fakeBuildServer, *Watcher) {
+	fake := &fakeBuildServer{builds: make(map[int]*Build)}
+	client := newTestClient(t, fake)
+	watcher, err := NewWatcher(client, WatcherOptions{ProjectIDs: []string{"Project"}, Store: store})
+	require.NoError(t, err)
+	return fake, watcher
+}
+
+func eventSummary(events []BuildEvent) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = fmt.Sprintf("%d %s", e.Build.ID, e.Type)
+	}
+	return out
+}
+
+func Test_WatcherPollReportsLifecycle(t *testing.T) {
+	require := require.New(t)
+
+	fake, sut := newWatcherTestSetup(t, nil)
+	fake.set(
+		&Build{ID: 10, State: "finished", Status: BuildStatusSuccess, FinishDate: "20240101T100000+0000"},
+		&Build{ID: 11, State: "running"},
+	)
+
+	checkpoint, events, err := sut.poll(newWatcherCheckpoint())
+	require.NoError(err)
+	require.Equal([]string{"11 started"}, eventSummary(events))
+	require.Equal(10, checkpoint.LastBuildID)
+
+	fake.set(
+		&Build{ID: 11, State: "finished", Status: BuildStatusFailure, FinishDate: "20240101T100500+0000"},
+		&Build{ID: 12, State: "queued"},
+		&Build{ID: 13, State: "queued"},
+	)
+	checkpoint, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"11 finished", "12 queued", "13 queued"}, eventSummary(events))
+	require.Equal(11, checkpoint.LastBuildID)
+
+	// 13 is deleted from the queue without a trace, 12 is canceled after it started
+	delete(fake.builds, 13)
+	fake.set(&Build{ID: 12, State: "running"})
+	checkpoint, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"12 started", "13 removed"}, eventSummary(events))
+	require.Equal("queued", events[1].Build.State)
+
+	fake.set(&Build{ID: 12, State: "finished", CanceledInfo: &Comment{Text: "stopped"}, FinishDate: "20240101T101000+0000"})
+	checkpoint, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"12 canceled"}, eventSummary(events))
+	require.Empty(checkpoint.Active)
+
+	_, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Empty(events)
+}
+
+func Test_WatcherReportsBuildFinishedBeforeNewerOne(t *testing.T) {
+	require := require.New(t)
+
+	fake, sut := newWatcherTestSetup(t, nil)
+	fake.set(&Build{ID: 20, State: "running"})
+	checkpoint, _, err := sut.poll(newWatcherCheckpoint())
+	require.NoError(err)
+
+	fake.set(&Build{ID: 21, State: "finished", FinishDate: "20240101T100000+0000"})
+	checkpoint, events, err := sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"21 finished"}, eventSummary(events))
+
+	fake.set(&Build{ID: 20, State: "finished", FinishDate: "20240101T100500+0000"})
+	_, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"20 finished"}, eventSummary(events))
+}
+
+func Test_WatcherReportsOlderBuildQueuedAndFinishedBetweenPolls(t *testing.T) {
+	require := require.New(t)
+
+	fake, sut := newWatcherTestSetup(t, nil)
+	fake.set(&Build{ID: 31, State: "finished", FinishDate: "20240101T100000+0000"})
+	checkpoint, _, err := sut.poll(newWatcherCheckpoint())
+	require.NoError(err)
+
+	fake.set(&Build{ID: 32, State: "finished", FinishDate: "20240101T100500+0000"})
+	checkpoint, events, err := sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"32 finished"}, eventSummary(events))
+
+	// 30 was never seen active, and finishes in the same second as 32 after it was reported
+	fake.set(&Build{ID: 30, State: "finished", FinishDate: "20240101T100500+0000"})
+	checkpoint, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Equal([]string{"30 finished"}, eventSummary(events))
+	require.Equal("20240101T100500+0000", checkpoint.LastFinishDate)
+	require.ElementsMatch([]int{30, 32}, checkpoint.LastFinishedIDs)
+
+	_, events, err = sut.poll(checkpoint)
+	require.NoError(err)
+	require.Empty(events)
+}
+
+func Test_FileCheckpointStoreRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	sut := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	actual, err := sut.Load()
+	require.NoError(err)
+	require.Nil(actual)
+
+	expected := &WatcherCheckpoint{Initialized: true, LastBuildID: 42, Active: map[int]string{43: "running"}}
+	require.NoError(sut.Save(expected))
+
+	actual, err = sut.Load()
+	require.NoError(err)
+	assert.Equal(t, expected, actual)
+}
+
+func Test_WatcherRequiresScope(t *testing.T) {
+	_, err := NewWatcher(&Client{}, WatcherOptions{})
+	assert.EqualError(t, err, "at least one project or build type to watch is required")
+}